@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"iter"
 	"os"
 	"path/filepath"
 
@@ -15,10 +16,11 @@ import (
 )
 
 var (
-	inputDir   string
-	outputDB   string
-	workers    int
-	configPath string
+	inputDir    string
+	outputDB    string
+	workers     int
+	configPath  string
+	incremental bool
 )
 
 func main() {
@@ -37,6 +39,7 @@ func main() {
 	rootCmd.Flags().StringVarP(&outputDB, "output", "o", "poetry.db", "Output unified SQLite database")
 	rootCmd.Flags().IntVarP(&workers, "workers", "w", 0, "Number of concurrent workers (0 = number of CPUs)")
 	rootCmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to datas.json config file (default: <input>/loader/datas.json)")
+	rootCmd.Flags().BoolVar(&incremental, "incremental", false, "Apply only added, changed and removed poems to an existing database, keeping poem IDs")
 
 	if err := rootCmd.Execute(); err != nil {
 		logger.Fatal("Command execution failed", zap.Error(err))
@@ -51,23 +54,38 @@ func run(cmd *cobra.Command, args []string) error {
 
 	logger.Info("Loading poetry data", zap.String("config", configPath))
 
-	// Load all poetry data
+	// Poems are streamed from the JSON files as they are processed
 	jsonLoader, err := loader.NewJSONLoader(configPath)
 	if err != nil {
 		return fmt.Errorf("failed to create loader: %w", err)
 	}
 
-	poems, err := jsonLoader.LoadAll()
+	poems := jsonLoader.Poems()
+
+	authors, err := jsonLoader.LoadAuthors()
 	if err != nil {
-		return fmt.Errorf("failed to load poems: %w", err)
+		return fmt.Errorf("failed to load authors: %w", err)
 	}
 
-	logger.Info("Loaded poems from JSON files", zap.Int("count", len(poems)))
+	logger.Info("Loaded authors from JSON files", zap.Int("count", len(authors)))
+
+	// Update the existing database if asked to, otherwise build it anew
+	_, statErr := os.Stat(outputDB)
+	if incremental && statErr == nil {
+		logger.Info("Updating unified database")
+		if err := updateUnifiedDatabase(outputDB, poems, authors, workers); err != nil {
+			return fmt.Errorf("failed to update database: %w", err)
+		}
+	} else {
+		if incremental {
+			logger.Info("No database to update, building it", zap.String("database", outputDB))
+		}
 
-	// Process unified database with both language variants
-	logger.Info("Processing unified database")
-	if err := processUnifiedDatabase(outputDB, poems, workers); err != nil {
-		return fmt.Errorf("failed to process database: %w", err)
+		// Process unified database with both language variants
+		logger.Info("Processing unified database")
+		if err := processUnifiedDatabase(outputDB, poems, authors, workers); err != nil {
+			return fmt.Errorf("failed to process database: %w", err)
+		}
 	}
 
 	logger.Info("Processing complete", zap.String("database", outputDB))
@@ -80,12 +98,47 @@ func run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func processUnifiedDatabase(dbPath string, poems []loader.PoemWithMeta, workers int) error {
-	// Remove existing database
-	if err := os.Remove(dbPath); err != nil && !os.IsNotExist(err) {
+// processUnifiedDatabase builds the database at dbPath anew. It is built
+// under a temporary name and only then moved to dbPath, so that a build that
+// fails is removed and leaves the previous database as it was, rather than a
+// half-filled one in its place.
+func processUnifiedDatabase(dbPath string, poems iter.Seq2[loader.PoemWithMeta, error], authors []loader.AuthorData, workers int) error {
+	buildPath := dbPath + ".build"
+	if err := removeDatabase(buildPath); err != nil {
+		return fmt.Errorf("failed to remove previous build: %w", err)
+	}
+
+	if err := buildUnifiedDatabase(buildPath, poems, authors, workers); err != nil {
+		if removeErr := removeDatabase(buildPath); removeErr != nil {
+			logger.Warn("Failed to remove failed build", zap.String("database", buildPath), zap.Error(removeErr))
+		}
+		return err
+	}
+
+	// The WAL of the previous database must not be applied to the new one
+	if err := removeDatabase(dbPath); err != nil {
 		return fmt.Errorf("failed to remove existing database: %w", err)
 	}
+	if err := os.Rename(buildPath, dbPath); err != nil {
+		return fmt.Errorf("failed to move database into place: %w", err)
+	}
+	return nil
+}
+
+// removeDatabase removes the SQLite database at path along with its WAL and
+// shared memory files, if there are any
+func removeDatabase(path string) error {
+	for _, name := range []string{path, path + "-wal", path + "-shm"} {
+		if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
 
+// buildUnifiedDatabase builds a database with both language variants at
+// dbPath, where there is none
+func buildUnifiedDatabase(dbPath string, poems iter.Seq2[loader.PoemWithMeta, error], authors []loader.AuthorData, workers int) error {
 	// Open database with single connection (safe for data processing)
 	db, err := database.Open(dbPath, 1, 1)
 	if err != nil {
@@ -106,6 +159,9 @@ func processUnifiedDatabase(dbPath string, poems []loader.PoemWithMeta, workers
 	if err := procSimp.Process(poems); err != nil {
 		return fmt.Errorf("failed to process simplified poems: %w", err)
 	}
+	if err := procSimp.ImportAuthors(authors); err != nil {
+		return fmt.Errorf("failed to import simplified authors: %w", err)
+	}
 
 	// Process traditional Chinese version
 	logger.Info("Processing language variant", zap.String("lang", "zh-Hant"))
@@ -114,8 +170,84 @@ func processUnifiedDatabase(dbPath string, poems []loader.PoemWithMeta, workers
 	if err := procTrad.Process(poems); err != nil {
 		return fmt.Errorf("failed to process traditional poems: %w", err)
 	}
+	if err := procTrad.ImportAuthors(authors); err != nil {
+		return fmt.Errorf("failed to import traditional authors: %w", err)
+	}
 
-	// Optimize database
+	optimizeDatabase(db)
+	return nil
+}
+
+// updateUnifiedDatabase applies the poems added, changed and removed in the
+// source data since the database at dbPath was built, keeping poem IDs
+func updateUnifiedDatabase(dbPath string, poems iter.Seq2[loader.PoemWithMeta, error], authors []loader.AuthorData, workers int) error {
+	db, err := database.Open(dbPath, 1, 1)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	// Poems are compared by columns only the current schema has
+	version, err := db.GetSchemaVersion()
+	if err != nil {
+		return fmt.Errorf("failed to get schema version: %w", err)
+	}
+	if version != database.SchemaVersion {
+		return fmt.Errorf("database schema version %d differs from %d, rebuild it without --incremental",
+			version, database.SchemaVersion)
+	}
+
+	// Plan once so that both language variants give new poems the same IDs
+	repoSimp := database.NewRepositoryWithLang(db, database.LangHans)
+	repoTrad := database.NewRepositoryWithLang(db, database.LangHant)
+	plan, err := processor.PlanUpdate(poems, repoSimp, repoTrad)
+	if err != nil {
+		return fmt.Errorf("failed to plan update: %w", err)
+	}
+	logger.Info("Update planned",
+		zap.Int("added", plan.Added),
+		zap.Int("changed", plan.Changed),
+		zap.Int("deleted", plan.Deleted),
+		zap.Int("moved", len(plan.Moved)),
+	)
+	poemsChanged := len(plan.Work) > 0 || len(plan.Removed) > 0 || len(plan.Duplicates) > 0 || len(plan.Moved) > 0
+
+	// Author descriptions are set again either way, as they may have changed
+	// without any poem changing
+	logger.Info("Updating language variant", zap.String("lang", "zh-Hans"))
+	procSimp := processor.NewProcessor(repoSimp, workers, false)
+	if poemsChanged {
+		if err := procSimp.Update(plan); err != nil {
+			return fmt.Errorf("failed to update simplified poems: %w", err)
+		}
+	}
+	if err := procSimp.ImportAuthors(authors); err != nil {
+		return fmt.Errorf("failed to import simplified authors: %w", err)
+	}
+
+	logger.Info("Updating language variant", zap.String("lang", "zh-Hant"))
+	procTrad := processor.NewProcessor(repoTrad, workers, true)
+	if poemsChanged {
+		if err := procTrad.Update(plan); err != nil {
+			return fmt.Errorf("failed to update traditional poems: %w", err)
+		}
+	}
+	if err := procTrad.ImportAuthors(authors); err != nil {
+		return fmt.Errorf("failed to import traditional authors: %w", err)
+	}
+
+	if !poemsChanged {
+		logger.Info("Poems are up to date")
+		return nil
+	}
+
+	optimizeDatabase(db)
+	return nil
+}
+
+// optimizeDatabase reclaims free pages and refreshes the query planner's
+// statistics after poems were written
+func optimizeDatabase(db *database.DB) {
 	logger.Info("Optimizing database")
 	if err := db.Exec("VACUUM").Error; err != nil {
 		logger.Warn("Failed to vacuum database", zap.Error(err))
@@ -124,8 +256,6 @@ func processUnifiedDatabase(dbPath string, poems []loader.PoemWithMeta, workers
 	if err := db.Exec("ANALYZE").Error; err != nil {
 		logger.Warn("Failed to analyze database", zap.Error(err))
 	}
-
-	return nil
 }
 
 func printStatistics(dbPath string) error {
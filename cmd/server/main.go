@@ -25,6 +25,7 @@ import (
 // Defining the Graphql handler
 func graphqlHandler(resolver *graph.Resolver) gin.HandlerFunc {
 	h := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
+	h.AroundFields(graph.ConvertLang)
 
 	return func(c *gin.Context) {
 		h.ServeHTTP(c.Writer, c.Request)
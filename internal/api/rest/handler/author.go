@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -18,22 +19,26 @@ func NewAuthorHandler(repo *database.Repository) *AuthorHandler {
 	return &AuthorHandler{repo: repo}
 }
 
-// ListAuthors returns a list of authors
-// Supports ?lang=zh-Hans (default) or ?lang=zh-Hant
+// ListAuthors returns a list of authors, most prolific first
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
+// Pass the returned next_cursor as ?cursor= to page by keyset instead of ?page=
 func (h *AuthorHandler) ListAuthors(c *gin.Context) {
-	lang := parseLang(c)
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
 	repo := h.repo.WithLang(lang)
 	pagination := ParsePagination(c)
 
-	authors, err := repo.GetAuthorsWithStats(pagination.PageSize, pagination.Offset())
+	page, err := pagination.dbPage(database.DecodeAuthorCursor)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "Failed to fetch authors")
+		respondError(c, http.StatusBadRequest, "Invalid cursor")
 		return
 	}
 
-	total, err := repo.CountAuthors()
+	authors, total, adjacent, err := repo.PageAuthors(nil, page)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "Failed to count authors")
+		respondError(c, http.StatusInternalServerError, "Failed to fetch authors")
 		return
 	}
 
@@ -42,13 +47,21 @@ func (h *AuthorHandler) ListAuthors(c *gin.Context) {
 		data[i] = formatAuthorWithStats(&author)
 	}
 
-	c.JSON(http.StatusOK, NewPaginationResponse(data, pagination, int64(total)))
+	var nextCursor string
+	if adjacent.After && len(authors) > 0 {
+		nextCursor = database.EncodeAuthorCursor(authors[len(authors)-1])
+	}
+
+	respond(c, http.StatusOK, NewCursorPaginationResponse(data, pagination, int64(total), nextCursor))
 }
 
 // GetAuthor returns a specific author by ID
-// Supports ?lang=zh-Hans (default) or ?lang=zh-Hant
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
 func (h *AuthorHandler) GetAuthor(c *gin.Context) {
-	lang := parseLang(c)
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
 	repo := h.repo.WithLang(lang)
 
 	id, ok := parseID(c, "id", "author")
@@ -64,3 +77,40 @@ func (h *AuthorHandler) GetAuthor(c *gin.Context) {
 
 	respondOK(c, formatAuthor(author))
 }
+
+// ListAuthorPoems returns a paginated list of poems by a specific author, ordered by poem ID
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
+func (h *AuthorHandler) ListAuthorPoems(c *gin.Context) {
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	repo := h.repo.WithLang(lang)
+
+	id, ok := parseID(c, "id", "author")
+	if !ok {
+		return
+	}
+
+	if _, err := repo.GetAuthorByID(id); err != nil {
+		respondError(c, http.StatusNotFound, "Author not found")
+		return
+	}
+
+	opts, ok := parsePoemOptions(c)
+	if !ok {
+		return
+	}
+
+	resp, err := poemPage(repo, database.PoemFilter{AuthorIDs: []int64{id}}, ParsePagination(c), opts)
+	if errors.Is(err, database.ErrInvalidCursor) {
+		respondError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to fetch poems")
+		return
+	}
+
+	respond(c, http.StatusOK, resp)
+}
@@ -107,6 +107,10 @@ func TestGetAuthor(t *testing.T) {
 	// Create test data
 	dynastyID, _ := repo.GetOrCreateDynasty("唐")
 	authorID, _ := repo.GetOrCreateAuthor("李白", dynastyID)
+	_, err := repo.UpdateAuthorDescriptions([]database.AuthorDescription{
+		{Name: "李白", Dynasty: "唐", Description: "李白字太白，号青莲居士。"},
+	})
+	require.NoError(t, err)
 
 	router.GET("/authors/:id", handler.GetAuthor)
 
@@ -125,6 +129,7 @@ func TestGetAuthor(t *testing.T) {
 				assert.NotNil(t, data)
 				assert.Equal(t, "李白", data["name"])
 				assert.Equal(t, "唐", data["dynasty"])
+				assert.Equal(t, "李白字太白，号青莲居士。", data["description"])
 				// Ensure ID is present
 				assert.NotNil(t, data["id"])
 			},
@@ -166,6 +171,135 @@ func TestGetAuthor(t *testing.T) {
 	}
 }
 
+func TestListAuthorPoems(t *testing.T) {
+	router, repo, _ := setupTestRouter(t)
+	handler := NewAuthorHandler(repo)
+
+	// createTestPoem attributes every poem to 李白
+	createTestPoem(t, repo, 3, "将进酒", "")
+	createTestPoem(t, repo, 1, "静夜思", "")
+	createTestPoem(t, repo, 2, "望庐山瀑布", "")
+
+	author, err := repo.GetAuthorByName("李白")
+	require.NoError(t, err)
+	authorID := strconv.FormatInt(author.ID, 10)
+
+	router.GET("/authors/:id/poems", handler.ListAuthorPoems)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		checkResponse  func(*testing.T, map[string]any)
+	}{
+		{
+			name:           "list poems ordered by ID",
+			path:           "/authors/" + authorID + "/poems",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				data := resp["data"].([]any)
+				require.Len(t, data, 3)
+				for i, item := range data {
+					poem := item.(map[string]any)
+					assert.Equal(t, float64(i+1), poem["id"])
+					assert.Equal(t, "李白", poem["author"].(map[string]any)["name"])
+				}
+
+				pagination := resp["pagination"].(map[string]any)
+				assert.Equal(t, float64(3), pagination["total"])
+			},
+		},
+		{
+			name:           "second page",
+			path:           "/authors/" + authorID + "/poems?page=2&page_size=2",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				data := resp["data"].([]any)
+				require.Len(t, data, 1)
+				assert.Equal(t, float64(3), data[0].(map[string]any)["id"])
+
+				pagination := resp["pagination"].(map[string]any)
+				assert.Equal(t, float64(3), pagination["total"])
+				assert.Equal(t, float64(2), pagination["total_pages"])
+			},
+		},
+		{
+			name:           "non-existent author",
+			path:           "/authors/999999/poems",
+			expectedStatus: http.StatusNotFound,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Equal(t, "Author not found", resp["error"])
+			},
+		},
+		{
+			name:           "invalid author ID",
+			path:           "/authors/invalid/poems",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.checkResponse != nil {
+				var response map[string]any
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+				tt.checkResponse(t, response)
+			}
+		})
+	}
+}
+
+func TestListAuthorsCursor(t *testing.T) {
+	router, repo, _ := setupTestRouter(t)
+	handler := NewAuthorHandler(repo)
+
+	// Only 李白 has poems, so he sorts first; the rest tie and sort by ID
+	createTestPoem(t, repo, 1, "静夜思", "")
+	dynastyID, _ := repo.GetOrCreateDynasty("唐")
+	for _, name := range []string{"杜甫", "白居易", "王维"} {
+		_, _ = repo.GetOrCreateAuthor(name, dynastyID)
+	}
+
+	router.GET("/authors", handler.ListAuthors)
+
+	var names []string
+	query := "?page_size=3"
+	for {
+		req := httptest.NewRequest(http.MethodGet, "/authors"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		for _, item := range response["data"].([]any) {
+			names = append(names, item.(map[string]any)["name"].(string))
+		}
+
+		next, ok := response["pagination"].(map[string]any)["next_cursor"].(string)
+		if !ok {
+			break
+		}
+		query = "?page_size=3&cursor=" + next
+	}
+	assert.Equal(t, []string{"李白", "杜甫", "白居易", "王维"}, names)
+
+	t.Run("poem cursor is rejected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/authors?cursor="+database.EncodePoemCursor(1), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 // TestPaginationBoundariesREST tests edge cases in REST API pagination
 func TestPaginationBoundariesREST(t *testing.T) {
 	router, repo, _ := setupTestRouter(t)
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/palemoky/chinese-poetry-api/internal/database"
+)
+
+// CiPaiHandler handles 词牌-related requests
+type CiPaiHandler struct {
+	repo *database.Repository
+}
+
+// NewCiPaiHandler creates a new 词牌 handler
+func NewCiPaiHandler(repo *database.Repository) *CiPaiHandler {
+	return &CiPaiHandler{repo: repo}
+}
+
+// ListCiPai returns a paginated list of 词牌, most used first
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
+func (h *CiPaiHandler) ListCiPai(c *gin.Context) {
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	repo := h.repo.WithLang(lang)
+	pagination := ParsePagination(c)
+
+	ciPais, total, err := repo.GetCiPaisWithStats(pagination.PageSize, pagination.Offset())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to fetch ci pai")
+		return
+	}
+
+	data := make([]map[string]any, len(ciPais))
+	for i, ciPai := range ciPais {
+		data[i] = formatCiPaiWithStats(&ciPai)
+	}
+
+	respond(c, http.StatusOK, NewPaginationResponse(data, pagination, int64(total)))
+}
+
+// GetCiPai returns a specific 词牌 with its poem count
+// The id may also be a name, the tune's own or an alias: /cipai/百字令
+// returns 念奴娇
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
+func (h *CiPaiHandler) GetCiPai(c *gin.Context) {
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	repo := h.repo.WithLang(lang)
+
+	id, ok := resolveCiPaiID(c, repo)
+	if !ok {
+		return
+	}
+
+	ciPai, err := repo.GetCiPaiWithStats(id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, "Ci pai not found")
+		return
+	}
+
+	respondOK(c, formatCiPaiWithStats(ciPai))
+}
+
+// ListCiPaiPoems returns a paginated list of poems written to a specific 词牌, ordered by poem ID
+// The id may also be a name, as for GetCiPai
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
+func (h *CiPaiHandler) ListCiPaiPoems(c *gin.Context) {
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	repo := h.repo.WithLang(lang)
+
+	id, ok := resolveCiPaiID(c, repo)
+	if !ok {
+		return
+	}
+
+	opts, ok := parsePoemOptions(c)
+	if !ok {
+		return
+	}
+
+	resp, err := poemPage(repo, database.PoemFilter{CiPaiIDs: []int64{id}}, ParsePagination(c), opts)
+	if errors.Is(err, database.ErrInvalidCursor) {
+		respondError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to fetch poems")
+		return
+	}
+
+	respond(c, http.StatusOK, resp)
+}
+
+// resolveCiPaiID reads the id URL parameter as either a numeric ID or the
+// name or alias of a 词牌.
+// On failure it writes the error response and returns false.
+func resolveCiPaiID(c *gin.Context, repo *database.Repository) (int64, bool) {
+	param := c.Param("id")
+	if id, err := strconv.ParseInt(param, 10, 64); err == nil {
+		return id, true
+	}
+
+	ids, err := repo.GetCiPaiIDs([]string{param})
+	if err != nil {
+		respondError(c, http.StatusNotFound, "Ci pai not found")
+		return 0, false
+	}
+	return ids[0], true
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCiPai(t *testing.T) {
+	router, repo := setupPoemTestRouter(t)
+	handler := NewCiPaiHandler(repo)
+
+	nianNuJiao, err := repo.GetOrCreateCiPai("念奴娇")
+	require.NoError(t, err)
+	for _, id := range []int64{1, 2} {
+		poem := createTestPoem(t, repo, id, "词"+strconv.FormatInt(id, 10), "")
+		poem.CiPaiID = &nianNuJiao
+		require.NoError(t, repo.UpsertPoem(poem))
+	}
+
+	router.GET("/cipai", handler.ListCiPai)
+
+	req := httptest.NewRequest(http.MethodGet, "/cipai?page_size=1", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	// The most used tune comes first
+	data := response["data"].([]any)
+	require.Len(t, data, 1)
+	first := data[0].(map[string]any)
+	assert.Equal(t, "念奴娇", first["name"])
+	assert.Equal(t, float64(2), first["poem_count"])
+	assert.Contains(t, first["aliases"], "百字令")
+	assert.Greater(t, response["pagination"].(map[string]any)["total"], float64(1))
+}
+
+func TestGetCiPai(t *testing.T) {
+	router, repo := setupPoemTestRouter(t)
+	handler := NewCiPaiHandler(repo)
+
+	nianNuJiao, err := repo.GetOrCreateCiPai("念奴娇")
+	require.NoError(t, err)
+
+	router.GET("/cipai/:id", handler.GetCiPai)
+
+	tests := []struct {
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name:           "get by ID",
+			id:             strconv.FormatInt(nianNuJiao, 10),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "get by name",
+			id:             "念奴娇",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "get by alias",
+			id:             "百字令",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "non-existent ID",
+			id:             "999999",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "non-existent name",
+			id:             "不存在的词牌",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/cipai/"+tt.id, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]any
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				data := response["data"].(map[string]any)
+				assert.Equal(t, float64(nianNuJiao), data["id"])
+				assert.Equal(t, "念奴娇", data["name"])
+				assert.Equal(t, float64(100), data["chars"])
+				assert.Equal(t, float64(0), data["poem_count"])
+			}
+		})
+	}
+}
+
+func TestListCiPaiPoems(t *testing.T) {
+	router, repo := setupPoemTestRouter(t)
+	handler := NewCiPaiHandler(repo)
+
+	nianNuJiao, err := repo.GetOrCreateCiPai("念奴娇")
+	require.NoError(t, err)
+	poem := createTestPoem(t, repo, 1, "念奴娇·赤壁怀古", "")
+	poem.CiPaiID = &nianNuJiao
+	require.NoError(t, repo.UpsertPoem(poem))
+	createTestPoem(t, repo, 2, "静夜思", "")
+
+	router.GET("/cipai/:id/poems", handler.ListCiPaiPoems)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		wantTotal      float64
+	}{
+		{
+			name:           "poems by ID",
+			path:           "/cipai/" + strconv.FormatInt(nianNuJiao, 10) + "/poems",
+			expectedStatus: http.StatusOK,
+			wantTotal:      1,
+		},
+		{
+			name:           "poems by alias",
+			path:           "/cipai/百字令/poems",
+			expectedStatus: http.StatusOK,
+			wantTotal:      1,
+		},
+		{
+			name:           "non-existent name",
+			path:           "/cipai/不存在的词牌/poems",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]any
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				data := response["data"].([]any)
+				require.Len(t, data, int(tt.wantTotal))
+				ciPai := data[0].(map[string]any)["ci_pai"].(map[string]any)
+				assert.Equal(t, "念奴娇", ciPai["name"])
+				assert.Equal(t, tt.wantTotal, response["pagination"].(map[string]any)["total"])
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/palemoky/chinese-poetry-api/internal/database"
+	"github.com/palemoky/chinese-poetry-api/internal/pinyin"
 )
 
 // parseID extracts and validates an int64 ID from a URL parameter.
@@ -15,26 +16,85 @@ func parseID(c *gin.Context, param, entityName string) (int64, bool) {
 	idStr := c.Param(param)
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + entityName + " ID"})
+		respondError(c, http.StatusBadRequest, "Invalid "+entityName+" ID")
 		return 0, false
 	}
 	return id, true
 }
 
-// respondError sends a JSON error response with the given status code and message.
+// respondError sends an error response with the given status code and message.
 func respondError(c *gin.Context, status int, message string) {
-	c.JSON(status, gin.H{"error": message})
+	respond(c, status, gin.H{"error": message})
 }
 
-// respondOK sends a JSON success response with the given data.
+// respondOK sends a success response with the given data.
 func respondOK(c *gin.Context, data any) {
-	c.JSON(http.StatusOK, gin.H{"data": data})
+	respond(c, http.StatusOK, gin.H{"data": data})
 }
 
+// langKey is the context key parseLang stores the requested variant under,
+// for respond to render regional variants
+const langKey = "lang"
+
 // parseLang extracts language variant from query parameter.
-// Supported values: "zh-Hans" (simplified), "zh-Hant" (traditional)
-// Defaults to simplified Chinese (zh-Hans).
-func parseLang(c *gin.Context) database.Lang {
-	lang := c.DefaultQuery("lang", "zh-Hans")
-	return database.ParseLang(lang)
+// Supported values: "zh-Hans" (simplified), "zh-Hant" (traditional),
+// "zh-TW" (Taiwan), "zh-HK" (Hong Kong), "ja" (Japanese Shinjitai)
+// Defaults to simplified Chinese (zh-Hans). An unknown value sends an error
+// response and returns false.
+func parseLang(c *gin.Context) (database.Lang, bool) {
+	lang, err := database.ParseLang(c.DefaultQuery("lang", "zh-Hans"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "lang must be zh-Hans, zh-Hant, zh-TW, zh-HK or ja")
+		return "", false
+	}
+	c.Set(langKey, lang)
+	return lang, true
+}
+
+// requestLang returns the variant parseLang read for the request, or "" if
+// it has not been called
+func requestLang(c *gin.Context) database.Lang {
+	value, _ := c.Get(langKey)
+	lang, _ := value.(database.Lang)
+	return lang
+}
+
+// poemOptions selects the optional parts of a formatted poem
+type poemOptions struct {
+	style   pinyin.Style // Annotate with pinyin in this style, unless empty
+	explain bool         // Add how the poem's type was decided
+}
+
+// parsePoemOptions extracts the options of poem responses from the pinyin
+// and explain query parameters.
+// On failure it writes the error response and returns false.
+func parsePoemOptions(c *gin.Context) (poemOptions, bool) {
+	style, ok := parsePinyinStyle(c)
+	if !ok {
+		return poemOptions{}, false
+	}
+
+	var explain bool
+	if value := c.Query("explain"); value != "" {
+		var err error
+		if explain, err = strconv.ParseBool(value); err != nil {
+			respondError(c, http.StatusBadRequest, "explain must be true or false")
+			return poemOptions{}, false
+		}
+	}
+
+	return poemOptions{style: style, explain: explain}, true
+}
+
+// parsePinyinStyle extracts the optional pinyin annotation style from the
+// query parameter. Supported values: "marks" (jìng yè sī), "numbers" (jing4 ye4 si1)
+// Returns "" when the parameter is absent, or sends an error response and
+// returns false when it is invalid.
+func parsePinyinStyle(c *gin.Context) (pinyin.Style, bool) {
+	style := pinyin.Style(c.Query("pinyin"))
+	if style != "" && !style.IsValid() {
+		respondError(c, http.StatusBadRequest, "pinyin must be marks or numbers")
+		return "", false
+	}
+	return style, true
 }
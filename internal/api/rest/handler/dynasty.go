@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -19,9 +20,12 @@ func NewDynastyHandler(repo *database.Repository) *DynastyHandler {
 }
 
 // ListDynasties returns a list of dynasties
-// Supports ?lang=zh-Hans (default) or ?lang=zh-Hant
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
 func (h *DynastyHandler) ListDynasties(c *gin.Context) {
-	lang := parseLang(c)
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
 	repo := h.repo.WithLang(lang)
 
 	dynasties, err := repo.GetDynastiesWithStats()
@@ -39,9 +43,12 @@ func (h *DynastyHandler) ListDynasties(c *gin.Context) {
 }
 
 // GetDynasty returns a specific dynasty by ID
-// Supports ?lang=zh-Hans (default) or ?lang=zh-Hant
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
 func (h *DynastyHandler) GetDynasty(c *gin.Context) {
-	lang := parseLang(c)
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
 	repo := h.repo.WithLang(lang)
 
 	id, ok := parseID(c, "id", "dynasty")
@@ -57,3 +64,40 @@ func (h *DynastyHandler) GetDynasty(c *gin.Context) {
 
 	respondOK(c, formatDynasty(dynasty))
 }
+
+// ListDynastyPoems returns a paginated list of poems from a specific dynasty, ordered by poem ID
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
+func (h *DynastyHandler) ListDynastyPoems(c *gin.Context) {
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	repo := h.repo.WithLang(lang)
+
+	id, ok := parseID(c, "id", "dynasty")
+	if !ok {
+		return
+	}
+
+	if _, err := repo.GetDynastyByID(id); err != nil {
+		respondError(c, http.StatusNotFound, "Dynasty not found")
+		return
+	}
+
+	opts, ok := parsePoemOptions(c)
+	if !ok {
+		return
+	}
+
+	resp, err := poemPage(repo, database.PoemFilter{DynastyIDs: []int64{id}}, ParsePagination(c), opts)
+	if errors.Is(err, database.ErrInvalidCursor) {
+		respondError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to fetch poems")
+		return
+	}
+
+	respond(c, http.StatusOK, resp)
+}
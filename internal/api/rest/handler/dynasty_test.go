@@ -98,3 +98,55 @@ func TestGetDynasty(t *testing.T) {
 		})
 	}
 }
+
+func TestListDynastyPoems(t *testing.T) {
+	router, repo := setupDynastyTestRouter(t)
+	handler := NewDynastyHandler(repo)
+
+	// createTestPoem attributes every poem to 唐
+	createTestPoem(t, repo, 2, "春晓", "")
+	createTestPoem(t, repo, 1, "静夜思", "")
+	songID, _ := repo.GetOrCreateDynasty("宋")
+	tang, err := repo.GetDynastyByName("唐")
+	require.NoError(t, err)
+
+	router.GET("/dynasties/:id/poems", handler.ListDynastyPoems)
+
+	t.Run("lists only poems from the dynasty", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/dynasties/"+strconv.FormatInt(tang.ID, 10)+"/poems", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		data := response["data"].([]any)
+		require.Len(t, data, 2)
+		assert.Equal(t, "静夜思", data[0].(map[string]any)["title"])
+		assert.Equal(t, "春晓", data[1].(map[string]any)["title"])
+		assert.Equal(t, float64(2), response["pagination"].(map[string]any)["total"])
+	})
+
+	t.Run("dynasty without poems", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/dynasties/"+strconv.FormatInt(songID, 10)+"/poems", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Empty(t, response["data"])
+		assert.Equal(t, float64(0), response["pagination"].(map[string]any)["total"])
+	})
+
+	t.Run("non-existent dynasty", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/dynasties/999999/poems", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
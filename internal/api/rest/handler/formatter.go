@@ -1,6 +1,13 @@
 package handler
 
-import "github.com/palemoky/chinese-poetry-api/internal/database"
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/palemoky/chinese-poetry-api/internal/database"
+	"github.com/palemoky/chinese-poetry-api/internal/diff"
+	"github.com/palemoky/chinese-poetry-api/internal/pinyin"
+)
 
 // formatDynasty formats a dynasty for API response, excluding created_at.
 func formatDynasty(d *database.Dynasty) map[string]any {
@@ -37,6 +44,9 @@ func formatAuthor(a *database.Author) map[string]any {
 	if a.Dynasty != nil {
 		result["dynasty"] = a.Dynasty.Name
 	}
+	if a.Description != nil {
+		result["description"] = *a.Description
+	}
 	return result
 }
 
@@ -73,8 +83,32 @@ func formatPoetryTypeWithStats(t *database.PoetryTypeWithStats) map[string]any {
 	return result
 }
 
-// formatPoem formats a poem for API response with nested objects.
-func formatPoem(poem *database.Poem) map[string]any {
+// formatCiPai formats a 词牌 for API response, excluding created_at.
+func formatCiPai(p *database.CiPai) map[string]any {
+	aliases := []string{}
+	_ = json.Unmarshal(p.Aliases, &aliases)
+
+	result := map[string]any{
+		"id":      p.ID,
+		"name":    p.Name,
+		"aliases": aliases,
+	}
+	if p.Chars != nil {
+		result["chars"] = *p.Chars
+	}
+	return result
+}
+
+// formatCiPaiWithStats formats a 词牌 with statistics for API response.
+func formatCiPaiWithStats(p *database.CiPaiWithStats) map[string]any {
+	result := formatCiPai(&p.CiPai)
+	result["poem_count"] = p.PoemCount
+	return result
+}
+
+// formatPoem formats a poem for API response with nested objects and its
+// analyses, plus the pinyin and classification reasoning if opts ask for them.
+func formatPoem(poem *database.Poem, opts poemOptions) map[string]any {
 	var typeData map[string]any
 	if poem.Type != nil {
 		typeData = map[string]any{
@@ -100,7 +134,7 @@ func formatPoem(poem *database.Poem) map[string]any {
 		dynastyData = formatDynasty(poem.Dynasty)
 	}
 
-	return map[string]any{
+	result := map[string]any{
 		"id":      poem.ID,
 		"type":    typeData,
 		"title":   poem.Title,
@@ -108,4 +142,99 @@ func formatPoem(poem *database.Poem) map[string]any {
 		"author":  authorData,
 		"dynasty": dynastyData,
 	}
+	if stanzas, err := poem.Stanzas(); err == nil {
+		result["stanzas"] = stanzas
+	}
+	if poem.CiPai != nil {
+		result["ci_pai"] = formatCiPai(poem.CiPai)
+	}
+	if poem.GongDiao != "" {
+		result["gong_diao"] = poem.GongDiao
+	}
+	if poem.QuPai != "" {
+		result["qu_pai"] = poem.QuPai
+	}
+	if len(poem.MeterDetail) > 0 {
+		result["meter"] = poem.MeterDetail
+	}
+	if len(poem.RhymeDetail) > 0 {
+		result["rhyme"] = poem.RhymeDetail
+	}
+	result["source"] = poem.Source()
+	if opts.style != "" {
+		result["pinyin"] = formatPinyin(poem, opts.style)
+	}
+	if opts.explain {
+		result["classification"] = formatClassification(poem)
+	}
+	return result
+}
+
+// formatClassification formats how a poem's type was decided: the rule, its
+// confidence and the evidence it went on.
+func formatClassification(poem *database.Poem) map[string]any {
+	result := map[string]any{
+		"rule":       poem.TypeRule,
+		"confidence": poem.TypeConfidence,
+	}
+	if len(poem.TypeEvidence) > 0 {
+		result["evidence"] = poem.TypeEvidence
+	}
+	return result
+}
+
+// formatPoemDiff formats how variant differs from poem: character-level diffs
+// of their titles and of their contents, paragraphs joined by newlines.
+func formatPoemDiff(poem, variant *database.Poem) map[string]any {
+	var from, to []string
+	_ = json.Unmarshal(poem.Content, &from)
+	_ = json.Unmarshal(variant.Content, &to)
+
+	return map[string]any{
+		"title":   diff.Chars(poem.Title, variant.Title),
+		"content": diff.Chars(strings.Join(from, "\n"), strings.Join(to, "\n")),
+	}
+}
+
+// formatPinyin formats the pinyin of a poem's title and of each paragraph.
+func formatPinyin(poem *database.Poem, style pinyin.Style) map[string]any {
+	var paragraphs []string
+	_ = json.Unmarshal(poem.Content, &paragraphs)
+
+	content := make([]string, len(paragraphs))
+	for i, paragraph := range paragraphs {
+		content[i] = pinyin.Annotate(paragraph, style)
+	}
+	return map[string]any{
+		"title":   pinyin.Annotate(poem.Title, style),
+		"content": content,
+	}
+}
+
+// formatPoems formats a slice of poems for API list responses.
+func formatPoems(poems []database.Poem, opts poemOptions) []map[string]any {
+	data := make([]map[string]any, len(poems))
+	for i := range poems {
+		data[i] = formatPoem(&poems[i], opts)
+	}
+	return data
+}
+
+// formatSearchResult formats a search result as a poem plus why it matched.
+func formatSearchResult(r *database.SearchResult, opts poemOptions) map[string]any {
+	result := formatPoem(&r.Poem, opts)
+	result["score"] = r.Score
+	result["highlight"] = r.Highlight
+	result["snippet"] = r.Snippet
+	result["matches"] = r.Matches
+	return result
+}
+
+// formatSearchResults formats a slice of search results for API list responses.
+func formatSearchResults(results []database.SearchResult, opts poemOptions) []map[string]any {
+	data := make([]map[string]any, len(results))
+	for i := range results {
+		data[i] = formatSearchResult(&results[i], opts)
+	}
+	return data
 }
@@ -14,7 +14,7 @@ func HealthHandler(db *database.DB) gin.HandlerFunc {
 		// Check database connection
 		sqlDB, err := db.DB.DB()
 		if err != nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
+			respond(c, http.StatusServiceUnavailable, gin.H{
 				"status": "unhealthy",
 				"error":  "failed to get database connection",
 			})
@@ -22,14 +22,14 @@ func HealthHandler(db *database.DB) gin.HandlerFunc {
 		}
 
 		if err := sqlDB.Ping(); err != nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
+			respond(c, http.StatusServiceUnavailable, gin.H{
 				"status": "unhealthy",
 				"error":  "database connection failed",
 			})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
+		respond(c, http.StatusOK, gin.H{
 			"status": "healthy",
 		})
 	}
@@ -40,12 +40,12 @@ func StatsHandler(repo *database.Repository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		stats, err := repo.GetStatistics()
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
+			respond(c, http.StatusInternalServerError, gin.H{
 				"error": "failed to get statistics",
 			})
 			return
 		}
 
-		c.JSON(http.StatusOK, stats)
+		respond(c, http.StatusOK, stats)
 	}
 }
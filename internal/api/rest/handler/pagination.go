@@ -4,12 +4,15 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/palemoky/chinese-poetry-api/internal/database"
 )
 
 // PaginationParams holds pagination parameters
 type PaginationParams struct {
 	Page     int
 	PageSize int
+	Cursor   string // Opaque keyset cursor; when set, Page is ignored
 }
 
 // Offset returns the database offset
@@ -35,7 +38,23 @@ func ParsePagination(c *gin.Context) PaginationParams {
 	return PaginationParams{
 		Page:     page,
 		PageSize: pageSize,
+		Cursor:   c.Query("cursor"),
+	}
+}
+
+// dbPage converts the params into a repository page, using decode to parse
+// the cursor if there is one. Returns database.ErrInvalidCursor for a
+// malformed cursor.
+func (p PaginationParams) dbPage(decode func(string) (*database.Cursor, error)) (database.Page, error) {
+	page := database.Page{Limit: p.PageSize, Offset: p.Offset()}
+	if p.Cursor != "" {
+		after, err := decode(p.Cursor)
+		if err != nil {
+			return page, err
+		}
+		page.After = after
 	}
+	return page, nil
 }
 
 // NewPaginationResponse creates a standardized pagination response
@@ -52,3 +71,25 @@ func NewPaginationResponse(data any, params PaginationParams, total int64) gin.H
 		},
 	}
 }
+
+// NewCursorPaginationResponse creates a pagination response for listings that
+// support keyset pagination. nextCursor continues the listing and is omitted
+// on the last page. Page numbers are only reported for offset pages.
+func NewCursorPaginationResponse(data any, params PaginationParams, total int64, nextCursor string) gin.H {
+	pagination := gin.H{
+		"page_size": params.PageSize,
+		"total":     total,
+	}
+	if params.Cursor == "" {
+		pagination["page"] = params.Page
+		pagination["total_pages"] = (int(total) + params.PageSize - 1) / params.PageSize
+	}
+	if nextCursor != "" {
+		pagination["next_cursor"] = nextCursor
+	}
+
+	return gin.H{
+		"data":       data,
+		"pagination": pagination,
+	}
+}
@@ -1,13 +1,19 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 
+	"github.com/palemoky/chinese-poetry-api/internal/classifier"
 	"github.com/palemoky/chinese-poetry-api/internal/database"
+	"github.com/palemoky/chinese-poetry-api/internal/helpers"
+	"github.com/palemoky/chinese-poetry-api/internal/search"
 )
 
 // PoemHandler handles poem-related requests
@@ -23,34 +29,123 @@ func NewPoemHandler(repo *database.Repository) *PoemHandler {
 }
 
 // ListPoems retrieves a paginated list of poems
-// Supports ?lang=zh-Hans (default) or ?lang=zh-Hant
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
+// Supports the same filters as RandomPoem: ?author=李白&author=杜甫&type=五言绝句&dynasty=唐&cipai=念奴娇
+// ?meter=strict, e.g. ?type=七言律诗&meter=strict for strictly regulated 七律,
+// ?rhyme=十一尤 for poems rhyming in a group of 平水韵 or 词林正韵, and
+// ?dataset=tangsong for the poems of one upstream dataset
+// Values of one filter are ORed together; different filters are ANDed.
+// Poems are ordered by ID; pass the returned next_cursor as ?cursor= to page
+// by keyset instead of ?page=.
 func (h *PoemHandler) ListPoems(c *gin.Context) {
-	lang := parseLang(c)
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
 	repo := h.repo.WithLang(lang)
-	pagination := ParsePagination(c)
 
-	poems, err := repo.ListPoems(pagination.PageSize, pagination.Offset())
+	filter, ok := parsePoemFilter(c, repo)
+	if !ok {
+		return
+	}
+	opts, ok := parsePoemOptions(c)
+	if !ok {
+		return
+	}
+
+	resp, err := poemPage(repo, filter, ParsePagination(c), opts)
+	if errors.Is(err, database.ErrInvalidCursor) {
+		respondError(c, http.StatusBadRequest, "invalid cursor")
+		return
+	}
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "failed to retrieve poems")
 		return
 	}
 
-	total, err := repo.CountPoems()
+	respond(c, http.StatusOK, resp)
+}
+
+// GetPoem returns a specific poem by ID
+// Supports ?explain=true, adding how the poem's type was decided (also on
+// the other poem endpoints)
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
+func (h *PoemHandler) GetPoem(c *gin.Context) {
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	repo := h.repo.WithLang(lang)
+
+	id, ok := parseID(c, "id", "poem")
+	if !ok {
+		return
+	}
+	opts, ok := parsePoemOptions(c)
+	if !ok {
+		return
+	}
+
+	poem, err := repo.GetPoemByID(strconv.FormatInt(id, 10))
 	if err != nil {
-		total = 0
+		respondError(c, http.StatusNotFound, "poem not found")
+		return
 	}
 
-	data := make([]map[string]any, len(poems))
-	for i, poem := range poems {
-		data[i] = formatPoem(&poem)
+	respondOK(c, formatPoem(poem, opts))
+}
+
+// GetPoemVariants lists the other versions of a poem, which differ from it in
+// a few characters or in title, each with a character-level diff against it
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
+func (h *PoemHandler) GetPoemVariants(c *gin.Context) {
+	lang, ok := parseLang(c)
+	if !ok {
+		return
 	}
+	repo := h.repo.WithLang(lang)
 
-	c.JSON(http.StatusOK, NewPaginationResponse(data, pagination, int64(total)))
+	id, ok := parseID(c, "id", "poem")
+	if !ok {
+		return
+	}
+	opts, ok := parsePoemOptions(c)
+	if !ok {
+		return
+	}
+
+	poem, err := repo.GetPoemByID(strconv.FormatInt(id, 10))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "poem not found")
+		return
+	}
+	variants, err := repo.GetPoemVariants(poem)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "failed to retrieve variants")
+		return
+	}
+
+	data := make([]map[string]any, len(variants))
+	for i := range variants {
+		data[i] = formatPoem(&variants[i], opts)
+		data[i]["diff"] = formatPoemDiff(poem, &variants[i])
+	}
+	respondOK(c, data)
 }
 
 // SearchPoems searches for poems by query string
+// q supports required terms, OR groups, -excluded terms, "quoted phrases" and
+// title:/content:/author: prefixes (see package search); a malformed q is a 400.
+// Results are ranked by relevance; ?sort=id orders them by poem ID instead.
+// ?cipai=念奴娇 (or ?cipai_id=) keeps ci written to that 词牌, and
+// ?gongdiao=双调 and ?qupai=沉醉东风 keep 元曲 in that 宫调 or to that 曲牌.
+// Each result carries its score, a highlighted title, a content snippet and
+// the offsets of every match.
 func (h *PoemHandler) SearchPoems(c *gin.Context) {
-	lang := parseLang(c)
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
 	repo := h.repo.WithLang(lang)
 
 	query := c.Query("q")
@@ -60,44 +155,81 @@ func (h *PoemHandler) SearchPoems(c *gin.Context) {
 	}
 
 	searchType := c.DefaultQuery("type", "all")
+	order := database.SearchOrder(c.DefaultQuery("sort", string(database.SearchOrderRelevance)))
+	if !order.IsValid() {
+		respondError(c, http.StatusBadRequest, "sort must be relevance or id")
+		return
+	}
+	opts, ok := parsePoemOptions(c)
+	if !ok {
+		return
+	}
+	var filter database.PoemFilter
+	if !parseTuneFilter(c, repo, &filter) {
+		return
+	}
 	pagination := ParsePagination(c)
 
 	// Use repository's search method instead of search engine
-	poems, total, err := repo.SearchPoems(query, searchType, pagination.Page, pagination.PageSize)
+	results, total, err := repo.SearchPoemsWithOptions(query, database.SearchOptions{
+		Type:      searchType,
+		Order:     order,
+		CiPaiIDs:  filter.CiPaiIDs,
+		GongDiaos: filter.GongDiaos,
+		QuPais:    filter.QuPais,
+		Page:      pagination.Page,
+		PageSize:  pagination.PageSize,
+	})
 	if err != nil {
+		var syntaxErr *search.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		respondError(c, http.StatusInternalServerError, "search failed")
 		return
 	}
 
-	data := make([]map[string]any, len(poems))
-	for i, poem := range poems {
-		data[i] = formatPoem(&poem)
-	}
-
-	c.JSON(http.StatusOK, NewPaginationResponse(data, pagination, total))
+	respond(c, http.StatusOK, NewPaginationResponse(formatSearchResults(results, opts), pagination, total))
 }
 
 // filterQueryKeys lists every RandomPoem filter param other than char/lang.
 // Used to reject char being combined with them (see RandomPoem doc comment).
-var filterQueryKeys = []string{"author_id", "author", "type_id", "type", "dynasty_id", "dynasty"}
+var filterQueryKeys = []string{"author_id", "author", "type_id", "type", "dynasty_id", "dynasty", "cipai_id", "cipai", "gongdiao", "qupai", "meter", "rhyme", "dataset"}
 
 // RandomPoem returns a random poem with optional filters
-// Supports ?lang=zh-Hans (default) or ?lang=zh-Hant
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
 // Supports filters: ?author=李白&type=五言绝句&type=七言绝句&dynasty=唐
 // Or by ID: ?author_id=123&type_id=456&type_id=789&dynasty_id=789
+// Supports ?cipai=念奴娇 (or ?cipai_id=), the 词牌 of a ci; aliases such as
+// 百字令 find the same tune
+// Supports ?gongdiao=双调&qupai=沉醉东风, the 宫调 and 曲牌 of a 元曲
+// Supports ?meter=strict|rescued|compliant|broken, how well the poem follows
+// the tonal templates of 近体诗 (compliant is strict or rescued)
+// Supports ?rhyme=十一尤 (or 尤, or a 部 of 词林正韵 such as 第十二部)
+// Supports ?dataset=songci, the key of the upstream dataset in datas.json
+// Every filter is repeatable; see parsePoemFilter.
 //
 // Supports 飞花令-style single-character search: ?char=春
 // char is only combinable with lang - not with author/type/dynasty filters,
 // since it selects poems via the FTS content index rather than the id-based
 // filters used elsewhere in this handler.
 func (h *PoemHandler) RandomPoem(c *gin.Context) {
-	lang := parseLang(c)
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
 	repo := h.repo.WithLang(lang)
 
+	opts, ok := parsePoemOptions(c)
+	if !ok {
+		return
+	}
+
 	if char := c.Query("char"); char != "" {
 		for _, key := range filterQueryKeys {
 			if c.Query(key) != "" {
-				respondError(c, http.StatusBadRequest, "char cannot be combined with author/type/dynasty filters")
+				respondError(c, http.StatusBadRequest, "char cannot be combined with author/type/dynasty/cipai/gongdiao/qupai/meter/rhyme/dataset filters")
 				return
 			}
 		}
@@ -112,71 +244,169 @@ func (h *PoemHandler) RandomPoem(c *gin.Context) {
 			return
 		}
 
-		c.JSON(http.StatusOK, formatPoem(poem))
+		respond(c, http.StatusOK, formatPoem(poem, opts))
+		return
+	}
+
+	filter, ok := parsePoemFilter(c, repo)
+	if !ok {
 		return
 	}
 
-	// Parse filter parameters
-	var authorID, dynastyID *int64
-	var typeIDs []int64
+	// Get a random poem with filters
+	poem, err := repo.GetRandomPoemByFilter(filter)
+	if err != nil {
+		respondError(c, http.StatusNotFound, "no poems found matching the criteria")
+		return
+	}
+
+	respond(c, http.StatusOK, formatPoem(poem, opts))
+}
+
+// DailyPoem returns the poem of the day
+// Supports ?date=2024-03-01 (default today) and ?timezone=Asia/Shanghai, the
+// IANA timezone "today" is taken in (default UTC)
+// Supports the same filters as RandomPoem, but not char.
+// The pick depends only on the day and the filters, so every client gets the
+// same poem all day, in every lang (see database.GetDailyPoemByFilter).
+func (h *PoemHandler) DailyPoem(c *gin.Context) {
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	repo := h.repo.WithLang(lang)
+
+	day, err := helpers.ParseDay(c.Query("date"), c.Query("timezone"), time.Now())
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts, ok := parsePoemOptions(c)
+	if !ok {
+		return
+	}
+	filter, ok := parsePoemFilter(c, repo)
+	if !ok {
+		return
+	}
 
-	// Parse author filter (by ID or name)
-	if authorIDStr := c.Query("author_id"); authorIDStr != "" {
-		if id, err := strconv.ParseInt(authorIDStr, 10, 64); err == nil {
-			authorID = &id
+	poem, err := repo.GetDailyPoemByFilter(day, filter)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondError(c, http.StatusNotFound, "no poems found matching the criteria")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "failed to retrieve daily poem")
+		return
+	}
+
+	result := formatPoem(poem, opts)
+	result["date"] = day
+	respond(c, http.StatusOK, result)
+}
+
+// parsePoemFilter builds a poem filter from the author/author_id,
+// dynasty/dynasty_id, type/type_id, cipai/cipai_id, gongdiao, qupai and
+// dataset query parameters. Each parameter may be repeated; values of one
+// filter are ORed and different filters are ANDed.
+// IDs take precedence over names when both are given for the same filter.
+// The meter parameter is a single classifier.MeterStatuses value; each rhyme
+// is a classifier.RhymeGroup value.
+// On failure it writes the error response and returns false.
+func parsePoemFilter(c *gin.Context, repo *database.Repository) (database.PoemFilter, bool) {
+	var filter database.PoemFilter
+	var ok bool
+
+	if filter.AuthorIDs, ok = parseFilterIDs(c, "author_id", "author", "author", repo.GetAuthorIDs); !ok {
+		return filter, false
+	}
+	if filter.DynastyIDs, ok = parseFilterIDs(c, "dynasty_id", "dynasty", "dynasty", repo.GetDynastyIDs); !ok {
+		return filter, false
+	}
+	if filter.TypeIDs, ok = parseFilterIDs(c, "type_id", "type", "poetry type", repo.GetPoetryTypeIDs); !ok {
+		return filter, false
+	}
+	if !parseTuneFilter(c, repo, &filter) {
+		return filter, false
+	}
+	filter.Datasets = c.QueryArray("dataset")
+	if meter := c.Query("meter"); meter != "" {
+		if filter.MeterStatuses, ok = classifier.MeterStatuses(meter); !ok {
+			respondError(c, http.StatusBadRequest, "meter must be strict, rescued, compliant or broken")
+			return filter, false
 		}
-	} else if authorName := c.Query("author"); authorName != "" {
-		// Look up author by name
-		author, err := repo.GetAuthorByName(authorName)
-		if err != nil {
-			respondError(c, http.StatusNotFound, "author not found")
-			return
+	}
+	for _, value := range c.QueryArray("rhyme") {
+		group, ok := classifier.RhymeGroup(value)
+		if !ok {
+			respondError(c, http.StatusBadRequest, "unknown rhyme group "+value)
+			return filter, false
 		}
-		authorID = &author.ID
+		filter.RhymeGroups = append(filter.RhymeGroups, group)
 	}
 
-	// Parse type filter (by ID or name) - supports multiple values
-	typeIDStrs := c.QueryArray("type_id")
-	typeNames := c.QueryArray("type")
+	return filter, true
+}
 
-	if len(typeIDStrs) > 0 {
-		// Parse type IDs
-		for _, idStr := range typeIDStrs {
-			if id, err := strconv.ParseInt(idStr, 10, 64); err == nil {
-				typeIDs = append(typeIDs, id)
+// parseTuneFilter reads the cipai/cipai_id, gongdiao and qupai filters into
+// filter, the ones SearchPoems takes as well. On failure it writes the error
+// response and returns false.
+func parseTuneFilter(c *gin.Context, repo *database.Repository, filter *database.PoemFilter) bool {
+	var ok bool
+	if filter.CiPaiIDs, ok = parseFilterIDs(c, "cipai_id", "cipai", "ci pai", repo.GetCiPaiIDs); !ok {
+		return false
+	}
+	filter.GongDiaos = repo.NameVariants(c.QueryArray("gongdiao"))
+	filter.QuPais = repo.NameVariants(c.QueryArray("qupai"))
+	return true
+}
+
+// parseFilterIDs reads one repeatable filter, either as numeric IDs from
+// idKey or as names from nameKey resolved in a single query via lookup.
+func parseFilterIDs(c *gin.Context, idKey, nameKey, entity string, lookup func([]string) ([]int64, error)) ([]int64, bool) {
+	if idStrs := c.QueryArray(idKey); len(idStrs) > 0 {
+		ids := make([]int64, 0, len(idStrs))
+		for _, idStr := range idStrs {
+			id, err := strconv.ParseInt(idStr, 10, 64)
+			if err != nil {
+				respondError(c, http.StatusBadRequest, "invalid "+idKey)
+				return nil, false
 			}
+			ids = append(ids, id)
 		}
-	} else if len(typeNames) > 0 {
-		// Batch lookup types by name in a single query
-		ids, err := repo.GetPoetryTypeIDs(typeNames)
-		if err != nil {
-			respondError(c, http.StatusNotFound, "poetry type not found")
-			return
-		}
-		typeIDs = ids
+		return ids, true
 	}
 
-	// Parse dynasty filter (by ID or name)
-	if dynastyIDStr := c.Query("dynasty_id"); dynastyIDStr != "" {
-		if id, err := strconv.ParseInt(dynastyIDStr, 10, 64); err == nil {
-			dynastyID = &id
-		}
-	} else if dynastyName := c.Query("dynasty"); dynastyName != "" {
-		// Look up dynasty by name
-		dynasty, err := repo.GetDynastyByName(dynastyName)
-		if err != nil {
-			respondError(c, http.StatusNotFound, "dynasty not found")
-			return
-		}
-		dynastyID = &dynasty.ID
+	names := c.QueryArray(nameKey)
+	if len(names) == 0 {
+		return nil, true
 	}
 
-	// Get a random poem with filters
-	poem, err := repo.GetRandomPoem(dynastyID, authorID, typeIDs)
+	ids, err := lookup(names)
 	if err != nil {
-		respondError(c, http.StatusNotFound, "no poems found matching the criteria")
-		return
+		respondError(c, http.StatusNotFound, entity+" not found")
+		return nil, false
+	}
+	return ids, true
+}
+
+// poemPage builds the paginated response for the poems matching filter, paged
+// by cursor when the request has one and by page number otherwise.
+func poemPage(repo *database.Repository, filter database.PoemFilter, pagination PaginationParams, opts poemOptions) (gin.H, error) {
+	page, err := pagination.dbPage(database.DecodePoemCursor)
+	if err != nil {
+		return nil, err
+	}
+
+	poems, total, adjacent, err := repo.PagePoemsByFilter(filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	var nextCursor string
+	if adjacent.After && len(poems) > 0 {
+		nextCursor = database.EncodePoemCursor(poems[len(poems)-1].ID)
 	}
 
-	c.JSON(http.StatusOK, formatPoem(poem))
+	return NewCursorPaginationResponse(formatPoems(poems, opts), pagination, int64(total), nextCursor), nil
 }
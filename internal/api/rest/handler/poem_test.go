@@ -2,8 +2,11 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -14,6 +17,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/palemoky/chinese-poetry-api/internal/database"
+	"github.com/palemoky/chinese-poetry-api/internal/pinyin"
 )
 
 // setupPoemTestRouter creates a test router with database
@@ -45,13 +49,23 @@ func createTestPoem(t *testing.T, repo *database.Repository, id int64, title, co
 	authorID, err := repo.GetOrCreateAuthor("李白", dynastyID)
 	require.NoError(t, err)
 
-	// Create poem
+	// Create poem, with the pinyin index and classification the processor would build
 	poem := &database.Poem{
-		ID:        id,
-		Title:     title,
-		Content:   datatypes.JSON([]byte(`["床前明月光","疑是地上霜","举头望明月","低头思故乡"]`)),
-		AuthorID:  &authorID,
-		DynastyID: &dynastyID,
+		ID:             id,
+		Title:          title,
+		Content:        datatypes.JSON([]byte(`["床前明月光","疑是地上霜","举头望明月","低头思故乡"]`)),
+		AuthorID:       &authorID,
+		DynastyID:      &dynastyID,
+		TitlePinyin:    pinyin.Index(title),
+		ContentPinyin:  pinyin.Index("床前明月光\n疑是地上霜\n举头望明月\n低头思故乡"),
+		TypeRule:       "structure",
+		TypeConfidence: 0.9,
+		TypeEvidence:   datatypes.JSON(`{"lines":4,"char_counts":[5,5,5,5]}`),
+		DatasetKey:     "tangsong",
+		DatasetName:    "全唐诗",
+		SourcePath:     "全唐诗/poet.tang.0.json",
+		SourceID:       "3ad6d468-7ff1-4a7b-8b24-a27d70d00ed4",
+		SourceIndex:    int(id) - 1,
 	}
 	err = repo.InsertPoem(poem)
 	require.NoError(t, err)
@@ -59,6 +73,27 @@ func createTestPoem(t *testing.T, repo *database.Repository, id int64, title, co
 	return poem
 }
 
+// createTestCi creates a ci whose 上阕 and 下阕 are kept as stanzas
+func createTestCi(t *testing.T, repo *database.Repository, id int64) *database.Poem {
+	dynastyID, err := repo.GetOrCreateDynasty("宋")
+	require.NoError(t, err)
+
+	authorID, err := repo.GetOrCreateAuthor("苏轼", dynastyID)
+	require.NoError(t, err)
+
+	poem := &database.Poem{
+		ID:          id,
+		Title:       "水调歌头",
+		Content:     datatypes.JSON([]byte(`["明月几时有？","把酒问青天。","转朱阁，低绮户，照无眠。","不应有恨，何事长向别时圆？"]`)),
+		StanzaSizes: datatypes.JSON([]byte(`[2,2]`)),
+		AuthorID:    &authorID,
+		DynastyID:   &dynastyID,
+	}
+	require.NoError(t, repo.InsertPoem(poem))
+
+	return poem
+}
+
 func TestListPoems(t *testing.T) {
 	router, repo := setupPoemTestRouter(t)
 	handler := NewPoemHandler(repo)
@@ -136,12 +171,257 @@ func TestListPoems(t *testing.T) {
 	}
 }
 
+func TestGetPoem(t *testing.T) {
+	router, repo := setupPoemTestRouter(t)
+	handler := NewPoemHandler(repo)
+
+	createTestPoem(t, repo, 1, "静夜思", "test content")
+	createTestCi(t, repo, 2)
+
+	router.GET("/poems/:id", handler.GetPoem)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		checkResponse  func(*testing.T, map[string]any)
+	}{
+		{
+			name:           "existing poem",
+			path:           "/poems/1",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				poem := resp["data"].(map[string]any)
+				assert.Equal(t, float64(1), poem["id"])
+				assert.Equal(t, "静夜思", poem["title"])
+				assert.NotEmpty(t, poem["content"])
+
+				author := poem["author"].(map[string]any)
+				assert.Equal(t, "李白", author["name"])
+
+				dynasty := poem["dynasty"].(map[string]any)
+				assert.Equal(t, "唐", dynasty["name"])
+
+				assert.NotContains(t, poem, "pinyin")
+				assert.NotContains(t, poem, "classification")
+				assert.Equal(t, []any{poem["content"]}, poem["stanzas"])
+
+				assert.Equal(t, map[string]any{
+					"dataset":      "tangsong",
+					"dataset_name": "全唐诗",
+					"file":         "全唐诗/poet.tang.0.json",
+					"record_id":    "3ad6d468-7ff1-4a7b-8b24-a27d70d00ed4",
+					"index":        float64(0),
+				}, poem["source"])
+			},
+		},
+		{
+			name:           "poem with stanzas",
+			path:           "/poems/2",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				poem := resp["data"].(map[string]any)
+				assert.Len(t, poem["content"], 4)
+				assert.Equal(t, []any{
+					[]any{"明月几时有？", "把酒问青天。"},
+					[]any{"转朱阁，低绮户，照无眠。", "不应有恨，何事长向别时圆？"},
+				}, poem["stanzas"])
+
+				source := poem["source"].(map[string]any)
+				assert.NotContains(t, source, "record_id")
+			},
+		},
+		{
+			name:           "poem with classification explained",
+			path:           "/poems/1?explain=true",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				classification := resp["data"].(map[string]any)["classification"].(map[string]any)
+				assert.Equal(t, "structure", classification["rule"])
+				assert.Equal(t, 0.9, classification["confidence"])
+				assert.Equal(t, map[string]any{"lines": float64(4), "char_counts": []any{float64(5), float64(5), float64(5), float64(5)}}, classification["evidence"])
+			},
+		},
+		{
+			name:           "invalid explain",
+			path:           "/poems/1?explain=maybe",
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Equal(t, "explain must be true or false", resp["error"])
+			},
+		},
+		{
+			name:           "poem with pinyin tone marks",
+			path:           "/poems/1?pinyin=marks",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				annotation := resp["data"].(map[string]any)["pinyin"].(map[string]any)
+				assert.Equal(t, "jìng yè sī", annotation["title"])
+
+				content := annotation["content"].([]any)
+				require.Len(t, content, 4)
+				assert.Equal(t, "chuáng qián míng yuè guāng", content[0])
+			},
+		},
+		{
+			name:           "poem with pinyin tone numbers",
+			path:           "/poems/1?pinyin=numbers",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				annotation := resp["data"].(map[string]any)["pinyin"].(map[string]any)
+				assert.Equal(t, "jing4 ye4 si1", annotation["title"])
+			},
+		},
+		{
+			name:           "invalid pinyin style",
+			path:           "/poems/1?pinyin=ipa",
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Equal(t, "pinyin must be marks or numbers", resp["error"])
+			},
+		},
+		{
+			name:           "non-existent poem",
+			path:           "/poems/999",
+			expectedStatus: http.StatusNotFound,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Equal(t, "poem not found", resp["error"])
+			},
+		},
+		{
+			name:           "invalid poem ID",
+			path:           "/poems/abc",
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Equal(t, "Invalid poem ID", resp["error"])
+			},
+		},
+		{
+			name:           "poem missing from traditional tables",
+			path:           "/poems/1?lang=zh-Hant",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "poem in Taiwan glyphs from simplified tables",
+			path:           "/poems/1?lang=zh-TW",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				poem := resp["data"].(map[string]any)
+				assert.Equal(t, "靜夜思", poem["title"])
+				assert.Equal(t, "舉頭望明月", poem["content"].([]any)[2])
+				assert.Equal(t, "李白", poem["author"].(map[string]any)["name"])
+			},
+		},
+		{
+			name:           "Shinjitai poem missing from traditional tables",
+			path:           "/poems/1?lang=ja",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unknown lang",
+			path:           "/poems/1?lang=en",
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Equal(t, "lang must be zh-Hans, zh-Hant, zh-TW, zh-HK or ja", resp["error"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.checkResponse != nil {
+				var response map[string]any
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+				tt.checkResponse(t, response)
+			}
+		})
+	}
+}
+
+func TestGetPoemVariants(t *testing.T) {
+	router, repo := setupPoemTestRouter(t)
+	handler := NewPoemHandler(repo)
+
+	// Poems 1 and 3 are versions of each other, as clustered by the processor
+	clusterID := int64(1)
+	for _, poem := range []*database.Poem{
+		{ID: 1, Title: "静夜思", Content: datatypes.JSON([]byte(`["床前明月光","疑是地上霜","举头望明月","低头思故乡"]`)), ClusterID: &clusterID},
+		{ID: 3, Title: "夜思", Content: datatypes.JSON([]byte(`["床前看月光","疑是地上霜","举头望山月","低头思故乡"]`)), ClusterID: &clusterID},
+	} {
+		require.NoError(t, repo.InsertPoem(poem))
+	}
+	createTestPoem(t, repo, 2, "春晓", "test content 2")
+
+	router.GET("/poems/:id/variants", handler.GetPoemVariants)
+
+	get := func(path string) (*httptest.ResponseRecorder, map[string]any) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w, resp
+	}
+
+	t.Run("variants with diff", func(t *testing.T) {
+		w, resp := get("/poems/1/variants")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		data := resp["data"].([]any)
+		require.Len(t, data, 1)
+		variant := data[0].(map[string]any)
+		assert.Equal(t, float64(3), variant["id"])
+		assert.Equal(t, map[string]any{
+			"title": []any{
+				map[string]any{"op": "delete", "text": "静"},
+				map[string]any{"op": "equal", "text": "夜思"},
+			},
+			"content": []any{
+				map[string]any{"op": "equal", "text": "床前"},
+				map[string]any{"op": "delete", "text": "明"},
+				map[string]any{"op": "insert", "text": "看"},
+				map[string]any{"op": "equal", "text": "月光\n疑是地上霜\n举头望"},
+				map[string]any{"op": "delete", "text": "明"},
+				map[string]any{"op": "insert", "text": "山"},
+				map[string]any{"op": "equal", "text": "月\n低头思故乡"},
+			},
+		}, variant["diff"])
+	})
+
+	t.Run("poem without variants", func(t *testing.T) {
+		w, resp := get("/poems/2/variants")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []any{}, resp["data"])
+	})
+
+	t.Run("non-existent poem", func(t *testing.T) {
+		w, resp := get("/poems/999/variants")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "poem not found", resp["error"])
+	})
+}
+
 func TestSearchPoems(t *testing.T) {
 	router, repo := setupPoemTestRouter(t)
 	handler := NewPoemHandler(repo)
 
 	// Create test poems
 	createTestPoem(t, repo, 1, "静夜思", "test content")
+	yuanID, err := repo.GetOrCreateDynasty("元")
+	require.NoError(t, err)
+	maZhiyuanID, err := repo.GetOrCreateAuthor("马致远", yuanID)
+	require.NoError(t, err)
+	require.NoError(t, repo.InsertPoem(&database.Poem{
+		ID: 2, Title: "【双调】沉醉东风·渔父", AuthorID: &maZhiyuanID, DynastyID: &yuanID,
+		GongDiao: "双调", QuPai: "沉醉东风", DatasetKey: "yuanqu", Content: datatypes.JSON([]byte(`["黄芦岸白蘋渡口"]`)),
+	}))
 
 	router.GET("/poems/search", handler.SearchPoems)
 
@@ -183,6 +463,116 @@ func TestSearchPoems(t *testing.T) {
 				assert.Equal(t, float64(10), pagination["page_size"])
 			},
 		},
+		{
+			name:           "search results carry match details",
+			query:          "?q=静夜思",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				data := resp["data"].([]any)
+				require.Len(t, data, 1)
+				poem := data[0].(map[string]any)
+				assert.Contains(t, poem, "score")
+				assert.Equal(t, "<mark>静夜思</mark>", poem["highlight"])
+				assert.Contains(t, poem, "snippet")
+
+				matches := poem["matches"].([]any)
+				require.Len(t, matches, 1)
+				assert.Equal(t, map[string]any{"field": "title", "line": float64(0), "start": float64(0), "end": float64(3)}, matches[0])
+			},
+		},
+		{
+			name:           "search sorted by id",
+			query:          "?q=静夜思&sort=id",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Len(t, resp["data"], 1)
+			},
+		},
+		{
+			name:           "search with query syntax",
+			query:          "?q=" + url.QueryEscape(`title:静夜思 -author:杜甫 "明月 光" OR 明月`),
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Len(t, resp["data"], 1)
+			},
+		},
+		{
+			name:           "excluded term filters everything",
+			query:          "?q=" + url.QueryEscape("静夜思 -李白"),
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Len(t, resp["data"], 0)
+			},
+		},
+		{
+			name:           "malformed query",
+			query:          "?q=" + url.QueryEscape(`静夜思 OR`),
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Equal(t, "invalid query at offset 4: OR must come between two terms", resp["error"])
+			},
+		},
+		{
+			name:           "pinyin search",
+			query:          "?q=" + url.QueryEscape("jing ye si") + "&type=pinyin",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				data := resp["data"].([]any)
+				require.Len(t, data, 1)
+				assert.Equal(t, "<mark>静夜思</mark>", data[0].(map[string]any)["highlight"])
+			},
+		},
+		{
+			name:           "pinyin search with a non-pinyin query",
+			query:          "?q=静夜思&type=pinyin",
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Equal(t, "invalid query at offset 0: unexpected '静' in pinyin query", resp["error"])
+			},
+		},
+		{
+			name:           "search results with pinyin",
+			query:          "?q=静夜思&pinyin=numbers",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				data := resp["data"].([]any)
+				require.Len(t, data, 1)
+				annotation := data[0].(map[string]any)["pinyin"].(map[string]any)
+				assert.Equal(t, "jing4 ye4 si1", annotation["title"])
+			},
+		},
+		{
+			name:           "search filtered by ci pai",
+			query:          "?q=静夜思&cipai=念奴娇",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Len(t, resp["data"], 0)
+			},
+		},
+		{
+			name:           "search filtered by gong diao in Taiwan glyphs",
+			query:          "?q=渔父&lang=zh-TW&gongdiao=雙調",
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Len(t, resp["data"], 1)
+			},
+		},
+		{
+			name:           "search with non-existent ci pai",
+			query:          "?q=静夜思&cipai=不存在的词牌",
+			expectedStatus: http.StatusNotFound,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Equal(t, "ci pai not found", resp["error"])
+			},
+		},
+		{
+			name:           "invalid sort",
+			query:          "?q=静夜思&sort=popularity",
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Equal(t, "sort must be relevance or id", resp["error"])
+			},
+		},
 		{
 			name:           "search without query parameter",
 			query:          "",
@@ -222,6 +612,333 @@ func TestSearchPoems(t *testing.T) {
 	}
 }
 
+func TestListPoemsWithFilters(t *testing.T) {
+	router, repo := setupPoemTestRouter(t)
+	handler := NewPoemHandler(repo)
+
+	// Poems 1-2 are 李白/唐 (created by createTestPoem)
+	createTestPoem(t, repo, 1, "静夜思", "")
+	createTestPoem(t, repo, 2, "望庐山瀑布", "")
+
+	tangID, err := repo.GetOrCreateDynasty("唐")
+	require.NoError(t, err)
+	songID, err := repo.GetOrCreateDynasty("宋")
+	require.NoError(t, err)
+	dufuID, err := repo.GetOrCreateAuthor("杜甫", tangID)
+	require.NoError(t, err)
+	sushiID, err := repo.GetOrCreateAuthor("苏轼", songID)
+	require.NoError(t, err)
+	jueju, err := repo.GetPoetryTypeID("五言绝句")
+	require.NoError(t, err)
+	nianNuJiao, err := repo.GetOrCreateCiPai("念奴娇")
+	require.NoError(t, err)
+	yuanID, err := repo.GetOrCreateDynasty("元")
+	require.NoError(t, err)
+	maZhiyuanID, err := repo.GetOrCreateAuthor("马致远", yuanID)
+	require.NoError(t, err)
+
+	strict := datatypes.JSON(`{"status":"strict","pattern":"仄起首句不入韵","tones":[],"deviations":[]}`)
+	qin := datatypes.JSON(`{"system":"pingshui","groups":["十二侵"],"positions":[{"line":2,"char":"深","group":"十二侵","out_of_rhyme":false}]}`)
+	dong := datatypes.JSON(`{"system":"pingshui","groups":["一东"],"positions":[]}`)
+	for _, poem := range []*database.Poem{
+		{ID: 3, Title: "春望", AuthorID: &dufuID, DynastyID: &tangID, TypeID: &jueju, MeterStatus: "strict", MeterDetail: strict, RhymeDetail: qin},
+		{ID: 4, Title: "题西林壁", AuthorID: &sushiID, DynastyID: &songID, TypeID: &jueju, MeterStatus: "rescued", RhymeDetail: dong},
+		{ID: 5, Title: "念奴娇·赤壁怀古", AuthorID: &sushiID, DynastyID: &songID, CiPaiID: &nianNuJiao, DatasetKey: "songci"},
+		{ID: 6, Title: "【双调】沉醉东风·渔父", AuthorID: &maZhiyuanID, DynastyID: &yuanID, GongDiao: "双调", QuPai: "沉醉东风", DatasetKey: "yuanqu"},
+	} {
+		poem.Content = datatypes.JSON([]byte(`["内容"]`))
+		require.NoError(t, repo.InsertPoem(poem))
+	}
+
+	router.GET("/poems", handler.ListPoems)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		wantIDs        []float64
+		wantError      string
+	}{
+		{
+			name:           "filter by author name",
+			query:          "?author=杜甫",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{3},
+		},
+		{
+			name:           "multiple authors are ORed",
+			query:          "?author=杜甫&author=苏轼",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{3, 4, 5},
+		},
+		{
+			name:           "filter by dynasty ID",
+			query:          "?dynasty_id=" + strconv.FormatInt(tangID, 10),
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{1, 2, 3},
+		},
+		{
+			name:           "different filters are ANDed",
+			query:          "?dynasty=唐&type=五言绝句",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{3},
+		},
+		{
+			name:           "multiple type IDs",
+			query:          "?type_id=" + strconv.FormatInt(jueju, 10) + "&type_id=999",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{3, 4},
+		},
+		{
+			name:           "ID takes precedence over name",
+			query:          "?author_id=" + strconv.FormatInt(sushiID, 10) + "&author=杜甫",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{4, 5},
+		},
+		{
+			name:           "no matches",
+			query:          "?author=苏轼&dynasty=唐",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{},
+		},
+		{
+			name:           "names in Taiwan glyphs",
+			query:          "?lang=zh-TW&author=蘇軾&dynasty=宋",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{4, 5},
+		},
+		{
+			name:           "ci pai in Hong Kong glyphs",
+			query:          "?lang=zh-HK&cipai=念奴嬌",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{5},
+		},
+		{
+			name:           "qu pai in Taiwan glyphs",
+			query:          "?lang=zh-TW&gongdiao=雙調&qupai=沉醉東風",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{6},
+		},
+		{
+			name:           "non-existent author",
+			query:          "?author=杜甫&author=不存在的作者",
+			expectedStatus: http.StatusNotFound,
+			wantError:      "author not found",
+		},
+		{
+			name:           "non-existent poetry type",
+			query:          "?type=不存在的体裁",
+			expectedStatus: http.StatusNotFound,
+			wantError:      "poetry type not found",
+		},
+		{
+			name:           "invalid dynasty ID",
+			query:          "?dynasty_id=abc",
+			expectedStatus: http.StatusBadRequest,
+			wantError:      "invalid dynasty_id",
+		},
+		{
+			name:           "filter by ci pai alias",
+			query:          "?cipai=百字令",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{5},
+		},
+		{
+			name:           "filter by ci pai ID",
+			query:          "?cipai_id=" + strconv.FormatInt(nianNuJiao, 10),
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{5},
+		},
+		{
+			name:           "non-existent ci pai",
+			query:          "?cipai=不存在的词牌",
+			expectedStatus: http.StatusNotFound,
+			wantError:      "ci pai not found",
+		},
+		{
+			name:           "filter by gong diao and qu pai",
+			query:          "?gongdiao=双调&qupai=沉醉东风&qupai=天净沙",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{6},
+		},
+		{
+			name:           "qu pai under another gong diao",
+			query:          "?gongdiao=越调&qupai=沉醉东风",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{},
+		},
+		{
+			name:           "filter by strict meter",
+			query:          "?meter=strict",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{3},
+		},
+		{
+			name:           "compliant meter is strict or rescued",
+			query:          "?type=五言绝句&meter=compliant",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{3, 4},
+		},
+		{
+			name:           "invalid meter",
+			query:          "?meter=loose",
+			expectedStatus: http.StatusBadRequest,
+			wantError:      "meter must be strict, rescued, compliant or broken",
+		},
+		{
+			name:           "filter by rhyme group",
+			query:          "?rhyme=十二侵",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{3},
+		},
+		{
+			name:           "rhyme groups by character are ORed",
+			query:          "?rhyme=侵&rhyme=东",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{3, 4},
+		},
+		{
+			name:           "unknown rhyme group",
+			query:          "?rhyme=十六尤",
+			expectedStatus: http.StatusBadRequest,
+			wantError:      "unknown rhyme group 十六尤",
+		},
+		{
+			name:           "filter by dataset",
+			query:          "?dataset=tangsong",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{1, 2},
+		},
+		{
+			name:           "multiple datasets with an author",
+			query:          "?dataset=songci&dataset=yuanqu&author=苏轼",
+			expectedStatus: http.StatusOK,
+			wantIDs:        []float64{5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/poems"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			if tt.wantError != "" {
+				assert.Equal(t, tt.wantError, response["error"])
+				return
+			}
+
+			gotIDs := []float64{}
+			for _, item := range response["data"].([]any) {
+				gotIDs = append(gotIDs, item.(map[string]any)["id"].(float64))
+			}
+			assert.Equal(t, tt.wantIDs, gotIDs)
+			assert.Equal(t, float64(len(tt.wantIDs)), response["pagination"].(map[string]any)["total"])
+		})
+	}
+
+	t.Run("meter and rhyme analyses in response", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/poems?meter=strict", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		poem := response["data"].([]any)[0].(map[string]any)
+		meter := poem["meter"].(map[string]any)
+		assert.Equal(t, "strict", meter["status"])
+		assert.Equal(t, "仄起首句不入韵", meter["pattern"])
+		rhyme := poem["rhyme"].(map[string]any)
+		assert.Equal(t, "pingshui", rhyme["system"])
+		assert.Equal(t, []any{"十二侵"}, rhyme["groups"])
+	})
+
+	t.Run("gong diao and qu pai in response", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/poems?qupai=沉醉东风", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		poem := response["data"].([]any)[0].(map[string]any)
+		assert.Equal(t, "双调", poem["gong_diao"])
+		assert.Equal(t, "沉醉东风", poem["qu_pai"])
+	})
+}
+
+func TestListPoemsCursor(t *testing.T) {
+	router, repo := setupPoemTestRouter(t)
+	handler := NewPoemHandler(repo)
+
+	for _, id := range []int64{4, 2, 8, 6, 10} {
+		createTestPoem(t, repo, id, "诗"+strconv.FormatInt(id, 10), "")
+	}
+
+	router.GET("/poems", handler.ListPoems)
+
+	get := func(t *testing.T, query string) (int, map[string]any) {
+		req := httptest.NewRequest(http.MethodGet, "/poems"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	t.Run("walk all pages by cursor", func(t *testing.T) {
+		var gotIDs []float64
+		query := "?page_size=2"
+		for {
+			code, resp := get(t, query)
+			require.Equal(t, http.StatusOK, code)
+
+			for _, item := range resp["data"].([]any) {
+				gotIDs = append(gotIDs, item.(map[string]any)["id"].(float64))
+			}
+
+			pagination := resp["pagination"].(map[string]any)
+			assert.Equal(t, float64(5), pagination["total"])
+			next, ok := pagination["next_cursor"].(string)
+			if !ok {
+				break
+			}
+			query = "?page_size=2&cursor=" + next
+		}
+		assert.Equal(t, []float64{2, 4, 6, 8, 10}, gotIDs)
+	})
+
+	t.Run("cursor pages omit page numbers", func(t *testing.T) {
+		_, first := get(t, "?page_size=2")
+		next := first["pagination"].(map[string]any)["next_cursor"].(string)
+
+		code, resp := get(t, "?page=5&page_size=2&cursor="+next)
+		require.Equal(t, http.StatusOK, code)
+
+		pagination := resp["pagination"].(map[string]any)
+		assert.NotContains(t, pagination, "page")
+		assert.NotContains(t, pagination, "total_pages")
+		assert.Len(t, resp["data"], 2)
+	})
+
+	t.Run("last offset page has no next cursor", func(t *testing.T) {
+		_, resp := get(t, "?page=3&page_size=2")
+		assert.NotContains(t, resp["pagination"], "next_cursor")
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		code, resp := get(t, "?cursor=20")
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "invalid cursor", resp["error"])
+	})
+}
+
 func TestRandomPoem(t *testing.T) {
 	router, repo := setupPoemTestRouter(t)
 	handler := NewPoemHandler(repo)
@@ -282,6 +999,15 @@ func TestRandomPoem(t *testing.T) {
 				assert.Equal(t, "author not found", resp["error"])
 			},
 		},
+		{
+			name:           "get random poem with invalid author ID",
+			query:          "?author_id=abc",
+			setupData:      true,
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp map[string]any) {
+				assert.Equal(t, "invalid author_id", resp["error"])
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -311,3 +1037,109 @@ func TestRandomPoem(t *testing.T) {
 		})
 	}
 }
+
+func TestDailyPoem(t *testing.T) {
+	router, repo := setupPoemTestRouter(t)
+	handler := NewPoemHandler(repo)
+	router.GET("/daily", handler.DailyPoem)
+
+	get := func(t *testing.T, query string) (int, map[string]any) {
+		req := httptest.NewRequest(http.MethodGet, "/daily"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp
+	}
+
+	t.Run("empty database", func(t *testing.T) {
+		code, resp := get(t, "?date=2024-03-01")
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, "no poems found matching the criteria", resp["error"])
+	})
+
+	// The same poems in both scripts, as the processor writes them, with IDs
+	// spread over the ID space like the hashes the processor gives
+	hant := repo.WithLang(database.LangHant)
+	spread := func(n int64) int64 { return n << (database.PoemIDBits - 4) }
+	for n := int64(1); n <= 10; n++ {
+		createTestPoem(t, repo, spread(n), "静夜思"+strconv.FormatInt(n, 10), "test content")
+		createTestPoem(t, hant, spread(n), "靜夜思"+strconv.FormatInt(n, 10), "test content")
+	}
+
+	t.Run("same poem all day", func(t *testing.T) {
+		code, first := get(t, "?date=2024-03-01")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "2024-03-01", first["date"])
+
+		_, again := get(t, "?date=2024-03-01")
+		assert.Equal(t, first["id"], again["id"])
+	})
+
+	t.Run("same poem in both languages", func(t *testing.T) {
+		_, hans := get(t, "?date=2024-03-01")
+		_, hant := get(t, "?date=2024-03-01&lang=zh-Hant")
+		assert.Equal(t, hans["id"], hant["id"])
+		assert.Contains(t, hant["title"], "靜夜思")
+	})
+
+	t.Run("same poem in both languages when the tables differ", func(t *testing.T) {
+		// Rows only one table has shift the count and offsets of that table
+		for n := int64(11); n <= 15; n++ {
+			createTestPoem(t, hant, spread(n)+1, "靜夜思"+strconv.FormatInt(n, 10), "test content")
+		}
+		for day := 1; day <= 28; day++ {
+			date := "?date=2024-02-" + fmt.Sprintf("%02d", day)
+			_, hans := get(t, date)
+			_, hant := get(t, date+"&lang=zh-Hant")
+			assert.Equal(t, hans["id"], hant["id"], date)
+		}
+	})
+
+	t.Run("unaffected by poems added elsewhere", func(t *testing.T) {
+		_, before := get(t, "?date=2024-03-01")
+		pick := int64(before["id"].(float64))
+		// Poems added after the pick change the count and the offset of every
+		// later poem, but not the pick
+		for n := int64(1); n <= 3; n++ {
+			createTestPoem(t, repo, pick+n, "新诗"+strconv.FormatInt(n, 10), "test content")
+		}
+		_, after := get(t, "?date=2024-03-01")
+		assert.Equal(t, before["id"], after["id"])
+	})
+
+	t.Run("changes from day to day", func(t *testing.T) {
+		ids := map[any]bool{}
+		for day := 1; day <= 28; day++ {
+			_, resp := get(t, "?date=2024-02-"+fmt.Sprintf("%02d", day))
+			ids[resp["id"]] = true
+		}
+		assert.Greater(t, len(ids), 1)
+	})
+
+	t.Run("today in timezone", func(t *testing.T) {
+		code, resp := get(t, "?timezone=Asia/Shanghai")
+		require.Equal(t, http.StatusOK, code)
+		assert.Regexp(t, `^\d{4}-\d{2}-\d{2}$`, resp["date"])
+	})
+
+	t.Run("with filter", func(t *testing.T) {
+		code, resp := get(t, "?date=2024-03-01&author=李白")
+		require.Equal(t, http.StatusOK, code)
+		author := resp["author"].(map[string]any)
+		assert.Equal(t, "李白", author["name"])
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		code, resp := get(t, "?date=2024-3-1")
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `invalid date "2024-3-1", expected YYYY-MM-DD`, resp["error"])
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		code, resp := get(t, "?timezone=Mars/Olympus")
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `invalid timezone "Mars/Olympus"`, resp["error"])
+	})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -19,9 +20,12 @@ func NewPoetryTypeHandler(repo *database.Repository) *PoetryTypeHandler {
 }
 
 // ListPoetryTypes returns a list of poetry types
-// Supports ?lang=zh-Hans (default) or ?lang=zh-Hant
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
 func (h *PoetryTypeHandler) ListPoetryTypes(c *gin.Context) {
-	lang := parseLang(c)
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
 	repo := h.repo.WithLang(lang)
 
 	types, err := repo.GetPoetryTypesWithStats()
@@ -39,9 +43,12 @@ func (h *PoetryTypeHandler) ListPoetryTypes(c *gin.Context) {
 }
 
 // GetPoetryType returns a specific poetry type by ID
-// Supports ?lang=zh-Hans (default) or ?lang=zh-Hant
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
 func (h *PoetryTypeHandler) GetPoetryType(c *gin.Context) {
-	lang := parseLang(c)
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
 	repo := h.repo.WithLang(lang)
 
 	id, ok := parseID(c, "id", "poetry type")
@@ -57,3 +64,40 @@ func (h *PoetryTypeHandler) GetPoetryType(c *gin.Context) {
 
 	respondOK(c, formatPoetryType(poetryType))
 }
+
+// ListPoetryTypePoems returns a paginated list of poems of a specific poetry type, ordered by poem ID
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
+func (h *PoetryTypeHandler) ListPoetryTypePoems(c *gin.Context) {
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	repo := h.repo.WithLang(lang)
+
+	id, ok := parseID(c, "id", "poetry type")
+	if !ok {
+		return
+	}
+
+	if _, err := repo.GetPoetryTypeByID(id); err != nil {
+		respondError(c, http.StatusNotFound, "Poetry type not found")
+		return
+	}
+
+	opts, ok := parsePoemOptions(c)
+	if !ok {
+		return
+	}
+
+	resp, err := poemPage(repo, database.PoemFilter{TypeIDs: []int64{id}}, ParsePagination(c), opts)
+	if errors.Is(err, database.ErrInvalidCursor) {
+		respondError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to fetch poems")
+		return
+	}
+
+	respond(c, http.StatusOK, resp)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -89,3 +90,67 @@ func TestGetPoetryType(t *testing.T) {
 		})
 	}
 }
+
+func TestListPoetryTypePoems(t *testing.T) {
+	router, repo := setupPoetryTypeTestRouter(t)
+	handler := NewPoetryTypeHandler(repo)
+
+	typeID, err := repo.GetPoetryTypeID("五言绝句")
+	require.NoError(t, err)
+
+	for _, id := range []int64{1, 2} {
+		poem := createTestPoem(t, repo, id, "诗"+strconv.FormatInt(id, 10), "")
+		poem.TypeID = &typeID
+		require.NoError(t, repo.UpsertPoem(poem))
+	}
+	createTestPoem(t, repo, 3, "无类型", "")
+
+	router.GET("/types/:id/poems", handler.ListPoetryTypePoems)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		wantTotal      float64
+	}{
+		{
+			name:           "poems of the type",
+			path:           "/types/" + strconv.FormatInt(typeID, 10) + "/poems",
+			expectedStatus: http.StatusOK,
+			wantTotal:      2,
+		},
+		{
+			name:           "non-existent type",
+			path:           "/types/999/poems",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid type ID",
+			path:           "/types/abc/poems",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]any
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				data := response["data"].([]any)
+				assert.Len(t, data, int(tt.wantTotal))
+				for _, item := range data {
+					poemType := item.(map[string]any)["type"].(map[string]any)
+					assert.Equal(t, "五言绝句", poemType["name"])
+				}
+				assert.Equal(t, tt.wantTotal, response["pagination"].(map[string]any)["total"])
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/palemoky/chinese-poetry-api/internal/database"
+)
+
+// QuHandler handles requests for the 宫调 and 曲牌 of 元曲
+type QuHandler struct {
+	repo *database.Repository
+}
+
+// NewQuHandler creates a new 元曲 handler
+func NewQuHandler(repo *database.Repository) *QuHandler {
+	return &QuHandler{repo: repo}
+}
+
+// ListGongDiao returns the 宫调 of the 元曲 with their 曲牌 and poem counts,
+// most used first
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
+func (h *QuHandler) ListGongDiao(c *gin.Context) {
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	repo := h.repo.WithLang(lang)
+
+	gongDiaos, err := repo.GetGongDiaosWithStats()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to fetch gong diao")
+		return
+	}
+
+	respondOK(c, gongDiaos)
+}
+
+// ListQuPai returns a paginated list of the 曲牌 of the 元曲, each with its
+// 宫调 and poem count, most used first
+// Supports ?gongdiao=双调 (repeatable) to list the 曲牌 of some 宫调 only
+// Supports ?lang=zh-Hans (default), zh-Hant, zh-TW, zh-HK or ja
+func (h *QuHandler) ListQuPai(c *gin.Context) {
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	repo := h.repo.WithLang(lang)
+	pagination := ParsePagination(c)
+
+	quPais, total, err := repo.GetQuPaisWithStats(repo.NameVariants(c.QueryArray("gongdiao")), pagination.PageSize, pagination.Offset())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Failed to fetch qu pai")
+		return
+	}
+
+	respond(c, http.StatusOK, NewPaginationResponse(quPais, pagination, int64(total)))
+}
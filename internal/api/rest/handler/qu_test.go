@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+
+	"github.com/palemoky/chinese-poetry-api/internal/database"
+)
+
+// createTestQu creates 元曲 written to the given 宫调 and 曲牌
+func createTestQu(t *testing.T, repo *database.Repository, tunes [][2]string) {
+	dynastyID, err := repo.GetOrCreateDynasty("元")
+	require.NoError(t, err)
+	authorID, err := repo.GetOrCreateAuthor("马致远", dynastyID)
+	require.NoError(t, err)
+
+	for i, tune := range tunes {
+		require.NoError(t, repo.InsertPoem(&database.Poem{
+			ID:        int64(i + 1),
+			Title:     fmt.Sprintf("【%s】%s %d", tune[0], tune[1], i),
+			Content:   datatypes.JSON([]byte(`["内容"]`)),
+			AuthorID:  &authorID,
+			DynastyID: &dynastyID,
+			GongDiao:  tune[0],
+			QuPai:     tune[1],
+		}))
+	}
+}
+
+func TestListGongDiao(t *testing.T) {
+	router, repo := setupPoemTestRouter(t)
+	handler := NewQuHandler(repo)
+
+	createTestQu(t, repo, [][2]string{{"双调", "沉醉东风"}, {"双调", "拨不断"}, {"越调", "天净沙"}})
+
+	router.GET("/gongdiao", handler.ListGongDiao)
+
+	req := httptest.NewRequest(http.MethodGet, "/gongdiao", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	data := response["data"].([]any)
+	require.Len(t, data, 2)
+	assert.Equal(t, map[string]any{"name": "双调", "qu_pai_count": float64(2), "poem_count": float64(2)}, data[0])
+}
+
+func TestListQuPai(t *testing.T) {
+	router, repo := setupPoemTestRouter(t)
+	handler := NewQuHandler(repo)
+
+	createTestQu(t, repo, [][2]string{{"双调", "沉醉东风"}, {"双调", "沉醉东风"}, {"双调", "拨不断"}, {"越调", "天净沙"}})
+
+	router.GET("/qupai", handler.ListQuPai)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantNames []any
+		wantTotal float64
+	}{
+		{
+			name:      "all 曲牌, most used first",
+			query:     "?page_size=2",
+			wantNames: []any{"沉醉东风", "拨不断"},
+			wantTotal: 3,
+		},
+		{
+			name:      "曲牌 of one 宫调",
+			query:     "?gongdiao=越调",
+			wantNames: []any{"天净沙"},
+			wantTotal: 1,
+		},
+		{
+			name:      "宫调 in Taiwan glyphs",
+			query:     "?lang=zh-TW&gongdiao=越調",
+			wantNames: []any{"天淨沙"},
+			wantTotal: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/qupai"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			names := []any{}
+			for _, item := range response["data"].([]any) {
+				names = append(names, item.(map[string]any)["name"])
+			}
+			assert.Equal(t, tt.wantNames, names)
+			assert.Equal(t, tt.wantTotal, response["pagination"].(map[string]any)["total"])
+		})
+	}
+}
@@ -0,0 +1,585 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// format is a response representation a client can ask for with ?format= or
+// the Accept header
+type format string
+
+const (
+	formatJSON     format = "json"
+	formatText     format = "text"
+	formatMarkdown format = "markdown"
+	formatCSV      format = "csv"
+	formatXML      format = "xml"
+)
+
+// formatNames maps the accepted ?format= values to formats
+var formatNames = map[string]format{
+	"json":     formatJSON,
+	"text":     formatText,
+	"txt":      formatText,
+	"markdown": formatMarkdown,
+	"md":       formatMarkdown,
+	"csv":      formatCSV,
+	"xml":      formatXML,
+}
+
+// mediaTypes maps the media types a client can accept to formats
+var mediaTypes = map[string]format{
+	"application/json": formatJSON,
+	"text/plain":       formatText,
+	"text/markdown":    formatMarkdown,
+	"text/csv":         formatCSV,
+	"application/xml":  formatXML,
+	"text/xml":         formatXML,
+}
+
+// contentTypes is the Content-Type each format is served with
+var contentTypes = map[format]string{
+	formatJSON:     "application/json; charset=utf-8",
+	formatText:     "text/plain; charset=utf-8",
+	formatMarkdown: "text/markdown; charset=utf-8",
+	formatCSV:      "text/csv; charset=utf-8",
+	formatXML:      "application/xml; charset=utf-8",
+}
+
+// negotiateFormat picks the response format: ?format= when given, otherwise
+// the supported type the Accept header prefers most. Only the types sharing
+// the highest q-value are considered, so a browser, which prefers HTML and
+// merely accepts XML, still gets JSON. Returns false for an unknown ?format=.
+func negotiateFormat(c *gin.Context) (format, bool) {
+	if name := c.Query("format"); name != "" {
+		f, ok := formatNames[strings.ToLower(name)]
+		return f, ok
+	}
+
+	var preferred []string
+	best := -1.0
+	for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		switch {
+		case q > best:
+			preferred, best = []string{mediaType}, q
+		case q == best:
+			preferred = append(preferred, mediaType)
+		}
+	}
+
+	if best > 0 {
+		for _, mediaType := range preferred {
+			if f, ok := mediaTypes[mediaType]; ok {
+				return f, true
+			}
+		}
+	}
+	return formatJSON, true
+}
+
+// respond writes obj, a JSON shape built by the format* functions, in the
+// format the client negotiated. The other formats are rendered from obj's
+// JSON encoding, so they carry the same content. A regional ?lang= variant
+// is applied to the encoded body, which holds the text of its base variant.
+func respond(c *gin.Context, status int, obj any) {
+	c.Header("Vary", "Accept")
+
+	f, ok := negotiateFormat(c)
+	if !ok {
+		status = http.StatusBadRequest
+		obj = gin.H{"error": "format must be json, text, markdown, csv or xml"}
+		f = formatJSON
+	}
+	lang := requestLang(c)
+	regional := lang.IsRegional()
+	if f == formatJSON && !regional {
+		c.JSON(status, obj)
+		return
+	}
+
+	var body []byte
+	var err error
+	if f == formatJSON {
+		body, err = json.Marshal(obj)
+	} else {
+		var v any
+		if v, err = jsonValue(obj); err == nil {
+			body, err = render(f, v)
+		}
+		if err == nil && f == formatCSV {
+			setPaginationHeaders(c, v)
+		}
+	}
+	if err == nil && regional {
+		var converted string
+		converted, err = lang.Convert(string(body))
+		body = []byte(converted)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encode response"})
+		return
+	}
+	c.Data(status, contentTypes[f], body)
+}
+
+// jsonValue returns obj as decoded from its JSON encoding: maps, lists,
+// strings, json.Numbers, bools and nils
+func jsonValue(obj any) (any, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	err = dec.Decode(&v)
+	return v, err
+}
+
+// render renders a JSON value in a format other than JSON
+func render(f format, v any) ([]byte, error) {
+	switch f {
+	case formatText:
+		return []byte(renderText(v)), nil
+	case formatMarkdown:
+		return []byte(renderMarkdown(v)), nil
+	case formatCSV:
+		return renderCSV(v)
+	case formatXML:
+		return renderXML(v)
+	}
+	return nil, fmt.Errorf("unsupported format %q", f)
+}
+
+// envelope splits a response into its items and pagination. Items are the
+// elements of data, or data itself, or the whole response when there is no
+// data, as for errors and single random poems.
+func envelope(v any) (items []any, pagination map[string]any) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return []any{v}, nil
+	}
+	pagination, _ = obj["pagination"].(map[string]any)
+
+	data, ok := obj["data"]
+	if !ok {
+		return []any{v}, nil
+	}
+	if list, ok := data.([]any); ok {
+		return list, pagination
+	}
+	return []any{data}, pagination
+}
+
+// poemView is the part of a poem the text and Markdown formats show
+type poemView struct {
+	title       string
+	byline      string
+	lines       []string
+	breaks      map[int]bool // Lines starting a stanza other than the first
+	titlePinyin string
+	linePinyin  []string
+}
+
+// asPoem returns the view of item if it is a poem, i.e. has a title and
+// content
+func asPoem(item any) (poemView, bool) {
+	obj, ok := item.(map[string]any)
+	if !ok {
+		return poemView{}, false
+	}
+	title, ok := obj["title"].(string)
+	if !ok {
+		return poemView{}, false
+	}
+	content, ok := obj["content"].([]any)
+	if !ok {
+		return poemView{}, false
+	}
+
+	p := poemView{title: title, lines: stringElems(content), breaks: map[int]bool{}}
+	if stanzas, ok := obj["stanzas"].([]any); ok {
+		line := 0
+		for i, stanza := range stanzas {
+			if i > 0 {
+				p.breaks[line] = true
+			}
+			if lines, ok := stanza.([]any); ok {
+				line += len(lines)
+			}
+		}
+	}
+	var byline []string
+	for _, key := range []string{"dynasty", "author"} {
+		if related, ok := obj[key].(map[string]any); ok {
+			if name, ok := related["name"].(string); ok && name != "" {
+				byline = append(byline, name)
+			}
+		}
+	}
+	p.byline = strings.Join(byline, " · ")
+	if annotation, ok := obj["pinyin"].(map[string]any); ok {
+		p.titlePinyin, _ = annotation["title"].(string)
+		if lines, ok := annotation["content"].([]any); ok {
+			p.linePinyin = stringElems(lines)
+		}
+	}
+	return p, true
+}
+
+// stringElems returns the string elements of list
+func stringElems(list []any) []string {
+	out := make([]string, 0, len(list))
+	for _, elem := range list {
+		if s, ok := elem.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// renderText renders a response as plain text: poems as title, byline and
+// lines with a blank line between stanzas, anything else as one "key: value"
+// line per field
+func renderText(v any) string {
+	items, pagination := envelope(v)
+
+	blocks := make([]string, 0, len(items)+1)
+	for _, item := range items {
+		var b strings.Builder
+		if p, ok := asPoem(item); ok {
+			b.WriteString(p.title + "\n")
+			if p.titlePinyin != "" {
+				b.WriteString(p.titlePinyin + "\n")
+			}
+			if p.byline != "" {
+				b.WriteString(p.byline + "\n")
+			}
+			b.WriteString("\n")
+			for i, line := range p.lines {
+				if p.breaks[i] {
+					b.WriteString("\n")
+				}
+				b.WriteString(line + "\n")
+				if i < len(p.linePinyin) {
+					b.WriteString(p.linePinyin[i] + "\n")
+				}
+			}
+		} else {
+			for _, field := range flatten(item) {
+				b.WriteString(field.key + ": " + field.value + "\n")
+			}
+		}
+		blocks = append(blocks, b.String())
+	}
+	if pagination != nil {
+		blocks = append(blocks, paginationSummary(pagination, "")+"\n")
+	}
+	return strings.Join(blocks, "\n")
+}
+
+// renderMarkdown renders a response as Markdown: poems as sections, lists of
+// anything else as a table and single objects as a list of fields
+func renderMarkdown(v any) string {
+	items, pagination := envelope(v)
+
+	var blocks []string
+	if _, isList := listData(v); isList && len(items) > 0 {
+		if _, ok := asPoem(items[0]); !ok {
+			blocks = append(blocks, markdownTable(items))
+			items = nil
+		}
+	}
+	for _, item := range items {
+		var b strings.Builder
+		if p, ok := asPoem(item); ok {
+			b.WriteString("## " + markdownEscape(p.title) + "\n\n")
+			if p.titlePinyin != "" {
+				b.WriteString("*" + markdownEscape(p.titlePinyin) + "*\n\n")
+			}
+			if p.byline != "" {
+				b.WriteString(markdownEscape(p.byline) + "\n\n")
+			}
+			for i, line := range p.lines {
+				b.WriteString(markdownEscape(line))
+				if i < len(p.linePinyin) {
+					b.WriteString("  \n*" + markdownEscape(p.linePinyin[i]) + "*")
+				}
+				b.WriteString("\n\n")
+			}
+		} else {
+			for _, field := range flatten(item) {
+				b.WriteString("- **" + markdownEscape(field.key) + "**: " + markdownEscape(field.value) + "\n")
+			}
+			b.WriteString("\n")
+		}
+		blocks = append(blocks, b.String())
+	}
+	if pagination != nil {
+		blocks = append(blocks, "*"+paginationSummary(pagination, "`")+"*\n")
+	}
+	return strings.TrimRight(strings.Join(blocks, "---\n\n"), "\n") + "\n"
+}
+
+// listData returns the data list of a list response
+func listData(v any) ([]any, bool) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	list, ok := obj["data"].([]any)
+	return list, ok
+}
+
+// markdownTable renders items as a table with a column per flattened field
+func markdownTable(items []any) string {
+	columns, rows := table(items)
+
+	var b strings.Builder
+	b.WriteString("|")
+	for _, column := range columns {
+		b.WriteString(" " + markdownEscape(column) + " |")
+	}
+	b.WriteString("\n|")
+	for range columns {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+	for _, row := range rows {
+		b.WriteString("|")
+		for _, cell := range row {
+			b.WriteString(" " + strings.ReplaceAll(markdownEscape(cell), "\n", "<br>") + " |")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// markdownReplacer escapes the characters Markdown would read as formatting
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+// markdownEscape escapes s for use as Markdown text
+func markdownEscape(s string) string {
+	return markdownReplacer.Replace(s)
+}
+
+// paginationSummary describes a page, quoting the next cursor with quote
+func paginationSummary(pagination map[string]any, quote string) string {
+	var parts []string
+	if page, ok := pagination["page"]; ok {
+		parts = append(parts, fmt.Sprintf("page %v of %v", page, pagination["total_pages"]))
+	}
+	parts = append(parts, fmt.Sprintf("%v total", pagination["total"]))
+	if cursor, ok := pagination["next_cursor"]; ok {
+		parts = append(parts, fmt.Sprintf("next cursor %s%v%s", quote, cursor, quote))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderCSV renders the items of a response as CSV, one row per item with a
+// column per flattened field
+func renderCSV(v any) ([]byte, error) {
+	items, _ := envelope(v)
+	columns, rows := table(items)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// setPaginationHeaders reports the pagination of a list response in headers,
+// since a CSV body only holds the rows
+func setPaginationHeaders(c *gin.Context, v any) {
+	_, pagination := envelope(v)
+	if pagination == nil {
+		return
+	}
+	c.Header("X-Total-Count", fmt.Sprint(pagination["total"]))
+	if cursor, ok := pagination["next_cursor"]; ok {
+		c.Header("X-Next-Cursor", fmt.Sprint(cursor))
+	}
+}
+
+// table lays items out as rows under the union of their flattened fields,
+// id first and the rest in alphabetical order
+func table(items []any) (columns []string, rows [][]string) {
+	values := make([]map[string]string, len(items))
+	seen := map[string]bool{}
+	for i, item := range items {
+		values[i] = map[string]string{}
+		for _, field := range flatten(item) {
+			values[i][field.key] = field.value
+			if !seen[field.key] {
+				seen[field.key] = true
+				columns = append(columns, field.key)
+			}
+		}
+	}
+	slices.SortFunc(columns, func(a, b string) int {
+		switch {
+		case a == b:
+			return 0
+		case a == "id":
+			return -1
+		case b == "id":
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	rows = make([][]string, len(values))
+	for i, row := range values {
+		rows[i] = make([]string, len(columns))
+		for j, column := range columns {
+			rows[i][j] = row[column]
+		}
+	}
+	return columns, rows
+}
+
+// field is a flattened leaf of a JSON value
+type field struct {
+	key   string
+	value string
+}
+
+// flatten lists the leaves of v in key order, naming nested fields with dotted
+// keys. Lists of scalars become one value per line; lists of objects, which
+// don't flatten into a fixed set of fields, stay JSON.
+func flatten(v any) []field {
+	var fields []field
+	var walk func(prefix string, v any)
+	walk = func(prefix string, v any) {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			fields = append(fields, field{key: prefix, value: scalarText(v)})
+			return
+		}
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if prefix != "" {
+				walk(prefix+"."+key, obj[key])
+			} else {
+				walk(key, obj[key])
+			}
+		}
+	}
+	walk("", v)
+	return fields
+}
+
+// scalarText renders a JSON value that flatten doesn't descend into. Lists of
+// lists, such as stanzas, are separated by blank lines.
+func scalarText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []any:
+		lines := make([]string, len(v))
+		sep := "\n"
+		for i, elem := range v {
+			switch elem.(type) {
+			case map[string]any:
+				data, _ := json.Marshal(v)
+				return string(data)
+			case []any:
+				sep = "\n\n"
+			}
+			lines[i] = scalarText(elem)
+		}
+		return strings.Join(lines, sep)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// renderXML renders a response as XML under a <response> root. Object fields
+// become elements in key order and list elements become <item> elements.
+func renderXML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := encodeXML(enc, "response", v); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// encodeXML writes v as an element called name
+func encodeXML(enc *xml.Encoder, name string, v any) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if err := encodeXML(enc, key, v[key]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, elem := range v {
+			if err := encodeXML(enc, "item", elem); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := enc.EncodeToken(xml.CharData(scalarText(v))); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		query  string
+		accept string
+		want   format
+		wantOK bool
+	}{
+		{"default", "", "", formatJSON, true},
+		{"format param", "?format=text", "", formatText, true},
+		{"format alias", "?format=md", "", formatMarkdown, true},
+		{"format param wins over accept", "?format=csv", "application/xml", formatCSV, true},
+		{"unknown format param", "?format=pdf", "", "", false},
+		{"accept plain text", "", "text/plain", formatText, true},
+		{"accept xml", "", "text/xml", formatXML, true},
+		{"accept with q-values", "", "application/json;q=0.5, text/csv;q=0.9", formatCSV, true},
+		{"browser gets json", "", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", formatJSON, true},
+		{"wildcard", "", "*/*", formatJSON, true},
+		{"unsupported type", "", "image/png", formatJSON, true},
+		{"refused type", "", "text/markdown;q=0", formatJSON, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+			if tt.accept != "" {
+				c.Request.Header.Set("Accept", tt.accept)
+			}
+
+			got, ok := negotiateFormat(c)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRespondFormats(t *testing.T) {
+	router, repo := setupPoemTestRouter(t)
+	handler := NewPoemHandler(repo)
+	router.GET("/poems", handler.ListPoems)
+	router.GET("/poems/:id", handler.GetPoem)
+
+	createTestPoem(t, repo, 1, "静夜思", "test content")
+	createTestPoem(t, repo, 2, "春晓", "test content 2")
+
+	get := func(t *testing.T, path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("json by default", func(t *testing.T) {
+		w := get(t, "/poems/1", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		assert.Equal(t, "Accept", w.Header().Get("Vary"))
+	})
+
+	t.Run("plain text", func(t *testing.T) {
+		w := get(t, "/poems/1", "text/plain")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "静夜思\n唐 · 李白\n\n床前明月光\n疑是地上霜\n举头望明月\n低头思故乡\n", w.Body.String())
+	})
+
+	t.Run("plain text with pinyin", func(t *testing.T) {
+		w := get(t, "/poems/1?format=text&pinyin=marks", "")
+		lines := strings.Split(w.Body.String(), "\n")
+		assert.Equal(t, "jìng yè sī", lines[1])
+		assert.Equal(t, "chuáng qián míng yuè guāng", lines[5])
+	})
+
+	t.Run("plain text list", func(t *testing.T) {
+		w := get(t, "/poems?format=text", "")
+		assert.Contains(t, w.Body.String(), "静夜思\n")
+		assert.Contains(t, w.Body.String(), "春晓\n")
+		assert.True(t, strings.HasSuffix(w.Body.String(), "page 1 of 1, 2 total\n"))
+	})
+
+	t.Run("plain text in Hong Kong glyphs", func(t *testing.T) {
+		w := get(t, "/poems/1?format=text&lang=zh-HK", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "靜夜思\n唐 · 李白\n\n牀前明月光\n疑是地上霜\n舉頭望明月\n低頭思故鄉\n", w.Body.String())
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		w := get(t, "/poems?format=markdown", "")
+		assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+		body := w.Body.String()
+		assert.Contains(t, body, "## 静夜思\n\n唐 · 李白\n\n床前明月光\n\n")
+		assert.Contains(t, body, "---\n\n## 春晓\n")
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		w := get(t, "/poems", "text/csv")
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "2", w.Header().Get("X-Total-Count"))
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		header := records[0]
+		assert.Equal(t, "id", header[0])
+		assert.Contains(t, header, "author.name")
+
+		row := map[string]string{}
+		for i, column := range header {
+			row[column] = records[1][i]
+		}
+		assert.Equal(t, "1", row["id"])
+		assert.Equal(t, "静夜思", row["title"])
+		assert.Equal(t, "李白", row["author.name"])
+		assert.Equal(t, "床前明月光\n疑是地上霜\n举头望明月\n低头思故乡", row["content"])
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		w := get(t, "/poems/1?format=xml", "")
+		assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+
+		var resp struct {
+			XMLName xml.Name `xml:"response"`
+			Data    struct {
+				ID      int64    `xml:"id"`
+				Title   string   `xml:"title"`
+				Content []string `xml:"content>item"`
+				Author  struct {
+					Name string `xml:"name"`
+				} `xml:"author"`
+			} `xml:"data"`
+		}
+		require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, int64(1), resp.Data.ID)
+		assert.Equal(t, "静夜思", resp.Data.Title)
+		assert.Equal(t, []string{"床前明月光", "疑是地上霜", "举头望明月", "低头思故乡"}, resp.Data.Content)
+		assert.Equal(t, "李白", resp.Data.Author.Name)
+	})
+
+	t.Run("errors are negotiated too", func(t *testing.T) {
+		w := get(t, "/poems/999?format=text", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "error: poem not found\n", w.Body.String())
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		w := get(t, "/poems/1?format=pdf", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "format must be json, text, markdown, csv or xml", resp["error"])
+	})
+
+	t.Run("plain text with stanzas", func(t *testing.T) {
+		createTestCi(t, repo, 3)
+		w := get(t, "/poems/3?format=text", "")
+		assert.Equal(t, "水调歌头\n宋 · 苏轼\n\n明月几时有？\n把酒问青天。\n\n转朱阁，低绮户，照无眠。\n不应有恨，何事长向别时圆？\n", w.Body.String())
+	})
+}
@@ -40,22 +40,39 @@ func SetupRouter(cfg *config.Config, db *database.DB, repo *database.Repository)
 		poemHandler := handler.NewPoemHandler(repo)
 		v1.GET("/poems", poemHandler.ListPoems)
 		v1.GET("/poems/random", poemHandler.RandomPoem)
+		v1.GET("/poems/daily", poemHandler.DailyPoem)
 		v1.GET("/poems/search", poemHandler.SearchPoems)
+		v1.GET("/poems/:id", poemHandler.GetPoem)
+		v1.GET("/poems/:id/variants", poemHandler.GetPoemVariants)
 
 		// Author routes
 		authorHandler := handler.NewAuthorHandler(repo)
 		v1.GET("/authors", authorHandler.ListAuthors)
 		v1.GET("/authors/:id", authorHandler.GetAuthor)
+		v1.GET("/authors/:id/poems", authorHandler.ListAuthorPoems)
 
 		// Dynasty routes
 		dynastyHandler := handler.NewDynastyHandler(repo)
 		v1.GET("/dynasties", dynastyHandler.ListDynasties)
 		v1.GET("/dynasties/:id", dynastyHandler.GetDynasty)
+		v1.GET("/dynasties/:id/poems", dynastyHandler.ListDynastyPoems)
 
 		// Poetry type routes
 		poetryTypeHandler := handler.NewPoetryTypeHandler(repo)
 		v1.GET("/types", poetryTypeHandler.ListPoetryTypes)
 		v1.GET("/types/:id", poetryTypeHandler.GetPoetryType)
+		v1.GET("/types/:id/poems", poetryTypeHandler.ListPoetryTypePoems)
+
+		// Ci pai (词牌) routes
+		ciPaiHandler := handler.NewCiPaiHandler(repo)
+		v1.GET("/cipai", ciPaiHandler.ListCiPai)
+		v1.GET("/cipai/:id", ciPaiHandler.GetCiPai)
+		v1.GET("/cipai/:id/poems", ciPaiHandler.ListCiPaiPoems)
+
+		// 元曲 宫调 and 曲牌 routes
+		quHandler := handler.NewQuHandler(repo)
+		v1.GET("/gongdiao", quHandler.ListGongDiao)
+		v1.GET("/qupai", quHandler.ListQuPai)
 	}
 
 	return router
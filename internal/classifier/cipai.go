@@ -0,0 +1,21 @@
+package classifier
+
+import (
+	"slices"
+	"strings"
+)
+
+// lostTuneNames stand in the sources for a 词牌 that has not come down to us
+var lostTuneNames = []string{"失调名", "失調名", "调名佚", "調名佚"}
+
+// CiPaiName returns the 词牌 named by the rhythmic field of a ci: the tune
+// before any subtitle, e.g. 念奴娇 for 念奴娇·赤壁怀古. It returns "" if the
+// field is empty or records the tune as lost (失调名).
+func CiPaiName(rhythmic string) string {
+	name, _, _ := strings.Cut(NormalizeText(rhythmic), "·")
+	name = strings.TrimSpace(name)
+	if slices.Contains(lostTuneNames, name) {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,27 @@
+package classifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCiPaiName(t *testing.T) {
+	tests := []struct {
+		rhythmic string
+		want     string
+	}{
+		{"念奴娇", "念奴娇"},
+		{" 水调歌头 ", "水调歌头"},
+		{"念奴娇·赤壁怀古", "念奴娇"},
+		{"失调名", ""},
+		{"失調名", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rhythmic, func(t *testing.T) {
+			assert.Equal(t, tt.want, CiPaiName(tt.rhythmic))
+		})
+	}
+}
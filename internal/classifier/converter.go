@@ -2,6 +2,7 @@ package classifier
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/liuzl/gocc"
 )
@@ -11,6 +12,10 @@ import (
 var (
 	s2t *gocc.OpenCC // Simplified to Traditional (thread-safe)
 	t2s *gocc.OpenCC // Traditional to Simplified (thread-safe)
+
+	// Regional glyph standards, used to render stored text on request
+	s2tw *gocc.OpenCC // Simplified to Taiwan standard (thread-safe)
+	s2hk *gocc.OpenCC // Simplified to Hong Kong standard (thread-safe)
 )
 
 func init() {
@@ -27,6 +32,16 @@ func init() {
 	if err != nil {
 		panic(fmt.Sprintf("failed to initialize t2s converter: %v", err))
 	}
+
+	// Initialize the regional converters
+	s2tw, err = gocc.New("s2tw")
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize s2tw converter: %v", err))
+	}
+	s2hk, err = gocc.New("s2hk")
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize s2hk converter: %v", err))
+	}
 }
 
 // ToTraditional converts simplified Chinese to traditional Chinese
@@ -39,6 +54,29 @@ func ToSimplified(text string) (string, error) {
 	return t2s.Convert(text)
 }
 
+// ToTaiwan converts simplified Chinese to traditional Chinese in the glyphs
+// standardized in Taiwan, e.g. 里 to 裡
+func ToTaiwan(text string) (string, error) {
+	return s2tw.Convert(text)
+}
+
+// ToHongKong converts simplified Chinese to traditional Chinese in the glyphs
+// standardized in Hong Kong, e.g. 里 to 裏
+func ToHongKong(text string) (string, error) {
+	return s2hk.Convert(text)
+}
+
+// ToShinjitai converts traditional Chinese to the Japanese Shinjitai forms of
+// its characters, e.g. 舊國 to 旧国. Characters without one are kept.
+func ToShinjitai(text string) (string, error) {
+	return strings.Map(func(r rune) rune {
+		if form, ok := shinjitaiForms[r]; ok {
+			return form
+		}
+		return r
+	}, text), nil
+}
+
 // ToTraditionalArray converts an array of strings to traditional Chinese
 func ToTraditionalArray(texts []string) ([]string, error) {
 	result := make([]string, len(texts))
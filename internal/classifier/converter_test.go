@@ -50,6 +50,31 @@ func TestToSimplified(t *testing.T) {
 	}
 }
 
+func TestRegionalVariants(t *testing.T) {
+	tests := []struct {
+		name    string
+		convert func(string) (string, error)
+		input   string
+		want    string
+	}{
+		{"taiwan", ToTaiwan, "说里面", "說裡面"},
+		{"taiwan poetry text", ToTaiwan, "春眠不觉晓", "春眠不覺曉"},
+		{"hong kong", ToHongKong, "说", "説"},
+		{"hong kong poetry text", ToHongKong, "春眠不觉晓", "春眠不覺曉"},
+		{"shinjitai", ToShinjitai, "國學", "国学"},
+		{"shinjitai poetry text", ToShinjitai, "舉頭望明月", "挙頭望明月"},
+		{"empty string", ToTaiwan, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.convert(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestToTraditionalArray(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -0,0 +1,277 @@
+package classifier
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Meter statuses, the result of checking a poem against the tonal templates
+// of 近体诗
+const (
+	MeterStrict  = "strict"  // Every required position follows the template
+	MeterRescued = "rescued" // Every 拗 is offset by a recognized 拗救
+	MeterBroken  = "broken"  // At least one 拗 is not rescued
+)
+
+// MeterCompliant selects poems whose meter is strict or rescued
+const MeterCompliant = "compliant"
+
+// Deviation kinds
+const (
+	DeviationAo      = "ao"       // 拗: a required position has the wrong tone
+	DeviationGuPing  = "gu_ping"  // 孤平: a 平 line keeps one 平 besides the rhyme
+	DeviationSanPing = "san_ping" // 三平尾: a line ends in three 平
+)
+
+// Deviation is a place where a poem departs from its template
+type Deviation struct {
+	Line     int    `json:"line"`     // 1-based line number
+	Position int    `json:"position"` // 1-based character position in the line
+	Kind     string `json:"kind"`
+	Rescued  bool   `json:"rescued"` // Offset by 拗救 in the same or the paired line
+}
+
+// MeterAnalysis is the result of checking a poem against the template of
+// 近体诗 it fits best
+type MeterAnalysis struct {
+	Status     string      `json:"status"`
+	Pattern    string      `json:"pattern"` // e.g. 仄起首句不入韵
+	Tones      []string    `json:"tones"`   // Tonal pattern of each line, e.g. 仄仄平平仄
+	Deviations []Deviation `json:"deviations"`
+}
+
+// lineType is one of the four line patterns of regulated verse
+type lineType int
+
+const (
+	lineA lineType = iota // 仄仄平平仄
+	lineB                 // 平平仄仄平
+	lineC                 // 平平平仄仄
+	lineD                 // 仄仄仄平平
+)
+
+// linePatterns are the five-character forms of the line types. A
+// seven-character line puts two characters of the opposite tone in front.
+var linePatterns = [...][5]Tone{
+	lineA: {ToneZe, ToneZe, TonePing, TonePing, ToneZe},
+	lineB: {TonePing, TonePing, ToneZe, ToneZe, TonePing},
+	lineC: {TonePing, TonePing, TonePing, ToneZe, ToneZe},
+	lineD: {ToneZe, ToneZe, ToneZe, TonePing, TonePing},
+}
+
+// firstLines lists the line types a poem may open with, in the order ties
+// between templates are broken: the first line is usually unrhymed in 五言
+// and rhymed in 七言
+var firstLines = map[int][]lineType{
+	WuyanChars: {lineA, lineC, lineB, lineD},
+	QiyanChars: {lineB, lineD, lineA, lineC},
+}
+
+// pattern returns the tones of a line of this type with chars characters
+func (t lineType) pattern(chars int) []Tone {
+	core := linePatterns[t]
+	if chars == WuyanChars {
+		return core[:]
+	}
+	lead := TonePing
+	if core[0] == TonePing {
+		lead = ToneZe
+	}
+	return append([]Tone{lead, lead}, core[:]...)
+}
+
+// rhymed reports whether lines of this type end in 平, as rhyming lines do
+func (t lineType) rhymed() bool {
+	return t == lineB || t == lineD
+}
+
+// template returns the line types of an n-line poem opening with first.
+// Each 对句 is opposite (对) its 出句 and rhymes; each following 出句 matches
+// (粘) the 对句 before it and does not.
+func template(first lineType, n int) []lineType {
+	types := make([]lineType, n)
+	types[0] = first
+	for i := 1; i < n; i++ {
+		prev := types[i-1]
+		switch {
+		case i%2 == 1 && (prev == lineA || prev == lineD):
+			types[i] = lineB
+		case i%2 == 1:
+			types[i] = lineD
+		case prev == lineB:
+			types[i] = lineC
+		default:
+			types[i] = lineA
+		}
+	}
+	return types
+}
+
+// AnalyzeMeter checks a poem against the tonal templates of 近体诗 and
+// reports the one it fits best. It returns nil unless the poem has the shape
+// of 近体诗: an even number of lines, at least four, all of five or all of
+// seven characters.
+func AnalyzeMeter(paragraphs []string) *MeterAnalysis {
+	lines := expandParagraphs(paragraphs)
+	if len(lines) < JuejuLines || len(lines)%2 != 0 {
+		return nil
+	}
+
+	tones := make([][]Tone, len(lines))
+	for i, line := range lines {
+		tones[i] = LineTones(removePunctuation(line))
+	}
+	chars := len(tones[0])
+	if chars != WuyanChars && chars != QiyanChars {
+		return nil
+	}
+	for _, lineTones := range tones {
+		if len(lineTones) != chars {
+			return nil
+		}
+	}
+
+	var best *MeterAnalysis
+	bestUnrescued := 0
+	for _, first := range firstLines[chars] {
+		deviations := checkTemplate(tones, template(first, len(tones)))
+		unrescued := 0
+		for _, d := range deviations {
+			if !d.Rescued {
+				unrescued++
+			}
+		}
+		if best != nil && (unrescued > bestUnrescued ||
+			unrescued == bestUnrescued && len(deviations) >= len(best.Deviations)) {
+			continue
+		}
+
+		status := MeterStrict
+		switch {
+		case unrescued > 0:
+			status = MeterBroken
+		case len(deviations) > 0:
+			status = MeterRescued
+		}
+		best = &MeterAnalysis{
+			Status:     status,
+			Pattern:    patternName(first, chars),
+			Deviations: deviations,
+		}
+		bestUnrescued = unrescued
+	}
+
+	best.Tones = make([]string, len(tones))
+	for i, lineTones := range tones {
+		var b strings.Builder
+		for _, tone := range lineTones {
+			b.WriteString(tone.String())
+		}
+		best.Tones[i] = b.String()
+	}
+	return best
+}
+
+// patternName names the template opening with first, e.g. 仄起首句不入韵
+func patternName(first lineType, chars int) string {
+	name := first.pattern(chars)[1].String() + "起"
+	if first.rhymed() {
+		return name + "首句入韵"
+	}
+	return name + "首句不入韵"
+}
+
+// checkTemplate returns the deviations of a poem's tones from the line types
+// of a template, marking those offset by 拗救
+func checkTemplate(tones [][]Tone, types []lineType) []Deviation {
+	deviations := []Deviation{}
+	for i, lineTones := range tones {
+		for _, d := range checkLine(lineTones, types[i]) {
+			d.Line = i + 1
+			d.Rescued = isRescued(d, tones, types)
+			deviations = append(deviations, d)
+		}
+	}
+	slices.SortFunc(deviations, func(a, b Deviation) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Position, b.Position))
+	})
+	return deviations
+}
+
+// checkLine returns the deviations of one line from its type. Only the
+// required positions are compared (一三五不论，二四六分明): the even ones and
+// the last. Tones the free positions take can still break a line, by leaving
+// a 孤平 in 平平仄仄平 or a 三平尾 in 仄仄仄平平. Characters of unknown or
+// either tone match anything.
+func checkLine(tones []Tone, t lineType) []Deviation {
+	chars := len(tones)
+	want := t.pattern(chars)
+	var deviations []Deviation
+	for i, tone := range tones {
+		required := i%2 == 1 || i == chars-1
+		if required && (tone == TonePing || tone == ToneZe) && tone != want[i] {
+			deviations = append(deviations, Deviation{Position: i + 1, Kind: DeviationAo})
+		}
+	}
+
+	// In 平平仄仄平 the first 平 may only turn 仄 if the third turns 平
+	// (仄平平仄平); in seven characters these are the third and the fifth
+	lead := chars - WuyanChars
+	if t == lineB {
+		ping := 0
+		for _, tone := range tones[lead : chars-1] {
+			if tone != ToneZe {
+				ping++
+			}
+		}
+		if ping < 2 {
+			deviations = append(deviations, Deviation{Position: lead + 1, Kind: DeviationGuPing})
+		}
+	}
+
+	if t == lineD && tones[chars-3] == TonePing && tones[chars-2] == TonePing && tones[chars-1] != ToneZe {
+		deviations = append(deviations, Deviation{Position: chars - 2, Kind: DeviationSanPing})
+	}
+	return deviations
+}
+
+// isRescued reports whether a 拗 is offset by one of the standard 拗救:
+//   - 平平仄平仄, the special form of 平平平仄仄 that swaps its third and
+//     fourth tones, provided the first stays 平
+//   - 仄仄平仄仄 (or 仄仄仄仄仄), rescued by a 平 in the third position of
+//     its 对句: 平平平仄平
+//
+// Positions are those of five characters; seven shift them by two.
+func isRescued(d Deviation, tones [][]Tone, types []lineType) bool {
+	if d.Kind != DeviationAo {
+		return false
+	}
+	line := tones[d.Line-1]
+	lead := len(line) - WuyanChars
+	if d.Position != lead+4 {
+		return false
+	}
+
+	switch types[d.Line-1] {
+	case lineC:
+		return line[lead] != ToneZe && line[lead+2] == ToneZe
+	case lineA:
+		// Only a 出句 is rescued by its 对句, the line after it
+		return d.Line%2 == 1 && tones[d.Line][lead+2] != ToneZe
+	}
+	return false
+}
+
+// MeterStatuses returns the meter statuses a filter value selects: one of
+// the statuses, or MeterCompliant for strict and rescued together. It
+// returns false if the value is none of these.
+func MeterStatuses(filter string) ([]string, bool) {
+	switch filter {
+	case MeterStrict, MeterRescued, MeterBroken:
+		return []string{filter}, true
+	case MeterCompliant:
+		return []string{MeterStrict, MeterRescued}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,37 @@
+package classifier
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+// FuzzAnalyzeMeter tests the AnalyzeMeter function with random inputs
+func FuzzAnalyzeMeter(f *testing.F) {
+	f.Add("白日依山尽，黄河入海流。", "欲穷千里目，更上一层楼。")
+	f.Add("朝辞白帝彩云间，千里江陵一日还。", "两岸猿声啼不住，轻舟已过万重山。")
+	f.Add("", "")
+	f.Add("test", "test")
+
+	f.Fuzz(func(t *testing.T, p1, p2 string) {
+		if !utf8.ValidString(p1) || !utf8.ValidString(p2) {
+			return
+		}
+
+		// Should not panic
+		result := AnalyzeMeter([]string{p1, p2})
+		if result == nil {
+			return
+		}
+
+		switch result.Status {
+		case MeterStrict, MeterRescued, MeterBroken:
+		default:
+			t.Errorf("AnalyzeMeter returned unknown status %q", result.Status)
+		}
+		for _, d := range result.Deviations {
+			if d.Line < 1 || d.Line > len(result.Tones) {
+				t.Errorf("deviation on line %d of %d", d.Line, len(result.Tones))
+			}
+		}
+	})
+}
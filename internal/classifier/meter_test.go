@@ -0,0 +1,163 @@
+package classifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tonesOf parses a tonal pattern such as 仄仄平平仄
+func tonesOf(pattern string) []Tone {
+	var tones []Tone
+	for _, r := range pattern {
+		switch r {
+		case '平':
+			tones = append(tones, TonePing)
+		case '仄':
+			tones = append(tones, ToneZe)
+		case '中':
+			tones = append(tones, ToneEither)
+		default:
+			tones = append(tones, ToneUnknown)
+		}
+	}
+	return tones
+}
+
+func TestAnalyzeMeter(t *testing.T) {
+	tests := []struct {
+		name       string
+		paragraphs []string
+		want       *MeterAnalysis
+	}{
+		{
+			name:       "五言绝句 仄起",
+			paragraphs: []string{"白日依山尽，黄河入海流。", "欲穷千里目，更上一层楼。"},
+			want: &MeterAnalysis{
+				Status:     MeterStrict,
+				Pattern:    "仄起首句不入韵",
+				Tones:      []string{"仄仄平平仄", "平平仄仄平", "仄平平仄仄", "中仄仄平平"},
+				Deviations: []Deviation{},
+			},
+		},
+		{
+			name:       "七言绝句 首句入韵",
+			paragraphs: []string{"朝辞白帝彩云间，千里江陵一日还。", "两岸猿声啼不住，轻舟已过万重山。"},
+			want: &MeterAnalysis{
+				Status:     MeterStrict,
+				Pattern:    "平起首句入韵",
+				Tones:      []string{"平平仄仄仄平中", "平仄平平仄仄平", "仄仄平平平仄仄", "平平仄中仄中平"},
+				Deviations: []Deviation{},
+			},
+		},
+		{
+			name:       "对句相救",
+			paragraphs: []string{"离离原上草，一岁一枯荣。", "野火烧不尽，春风吹又生。"},
+			want: &MeterAnalysis{
+				Status:     MeterRescued,
+				Pattern:    "平起首句不入韵",
+				Tones:      []string{"平平平仄仄", "仄仄仄平平", "仄仄平仄仄", "平平中仄平"},
+				Deviations: []Deviation{{Line: 3, Position: 4, Kind: DeviationAo, Rescued: true}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, AnalyzeMeter(tt.paragraphs))
+		})
+	}
+}
+
+func TestAnalyzeMeterLvshi(t *testing.T) {
+	got := AnalyzeMeter([]string{
+		"国破山河在，城春草木深。", "感时花溅泪，恨别鸟惊心。",
+		"烽火连三月，家书抵万金。", "白头搔更短，浑欲不胜簪。",
+	})
+	require.NotNil(t, got)
+	assert.Equal(t, MeterStrict, got.Status)
+	assert.Equal(t, "仄起首句不入韵", got.Pattern)
+	assert.Len(t, got.Tones, 8)
+}
+
+func TestAnalyzeMeterBroken(t *testing.T) {
+	got := AnalyzeMeter([]string{"床前明月光，疑是地上霜。", "举头望明月，低头思故乡。"})
+	require.NotNil(t, got)
+	assert.Equal(t, MeterBroken, got.Status)
+	assert.NotEmpty(t, got.Deviations)
+}
+
+func TestAnalyzeMeterShape(t *testing.T) {
+	tests := []struct {
+		name       string
+		paragraphs []string
+	}{
+		{"too few lines", []string{"白日依山尽，黄河入海流。"}},
+		{"odd number of lines", []string{"白日依山尽，黄河入海流。", "欲穷千里目，更上一层楼。", "白日依山尽。"}},
+		{"four characters", []string{"关关雎鸠，在河之洲。", "窈窕淑女，君子好逑。"}},
+		{"mixed line lengths", []string{"白日依山尽，黄河入海流。", "欲穷千里目，更上一层楼台。"}},
+		{"empty", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Nil(t, AnalyzeMeter(tt.paragraphs))
+		})
+	}
+}
+
+func TestCheckLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		tones string
+		t     lineType
+		want  []Deviation
+	}{
+		{"follows the pattern", "仄仄平平仄", lineA, nil},
+		{"free positions", "平仄仄平仄", lineA, nil},
+		{"拗 at a required position", "仄仄平仄仄", lineA, []Deviation{{Position: 4, Kind: DeviationAo}}},
+		{"wrong rhyme tone", "平平仄仄仄", lineB, []Deviation{{Position: 5, Kind: DeviationAo}}},
+		{"孤平", "仄平仄仄平", lineB, []Deviation{{Position: 1, Kind: DeviationGuPing}}},
+		{"孤平拗救", "仄平平仄平", lineB, nil},
+		{"七言孤平", "仄仄仄平仄仄平", lineB, []Deviation{{Position: 3, Kind: DeviationGuPing}}},
+		{"三平尾", "仄仄平平平", lineD, []Deviation{{Position: 3, Kind: DeviationSanPing}}},
+		{"either tone matches", "中中平平中", lineA, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, checkLine(tonesOf(tt.tones), tt.t))
+		})
+	}
+}
+
+func TestIsRescued(t *testing.T) {
+	t.Run("平平仄平仄", func(t *testing.T) {
+		tones := [][]Tone{tonesOf("平平仄平仄"), tonesOf("仄仄仄平平")}
+		d := Deviation{Line: 1, Position: 4, Kind: DeviationAo}
+		assert.True(t, isRescued(d, tones, []lineType{lineC, lineD}))
+	})
+
+	t.Run("对句 without the rescuing 平", func(t *testing.T) {
+		tones := [][]Tone{tonesOf("仄仄平仄仄"), tonesOf("平平仄仄平")}
+		d := Deviation{Line: 1, Position: 4, Kind: DeviationAo}
+		assert.False(t, isRescued(d, tones, []lineType{lineA, lineB}))
+	})
+
+	t.Run("对句 is not rescued by the next 出句", func(t *testing.T) {
+		tones := [][]Tone{tonesOf("平平仄仄平"), tonesOf("仄仄平仄仄"), tonesOf("平平平仄平")}
+		d := Deviation{Line: 2, Position: 4, Kind: DeviationAo}
+		assert.False(t, isRescued(d, tones, []lineType{lineB, lineA, lineB}))
+	})
+}
+
+func TestMeterStatuses(t *testing.T) {
+	statuses, ok := MeterStatuses(MeterStrict)
+	assert.True(t, ok)
+	assert.Equal(t, []string{MeterStrict}, statuses)
+
+	statuses, ok = MeterStatuses(MeterCompliant)
+	assert.True(t, ok)
+	assert.Equal(t, []string{MeterStrict, MeterRescued}, statuses)
+
+	_, ok = MeterStatuses("loose")
+	assert.False(t, ok)
+}
@@ -1,6 +1,7 @@
 package classifier
 
 import (
+	"slices"
 	"strings"
 	"unicode"
 )
@@ -155,3 +156,27 @@ func NormalizeAndSplitParagraphs(paragraphs []string) []string {
 	}
 	return result
 }
+
+// SplitStanzas normalizes and splits paragraphs like NormalizeAndSplitParagraphs,
+// grouping the sentences into stanzas: the 上阕 and 下阕 of a ci, the 章 of
+// 诗经. Sources hold a stanza per paragraph of several sentences, where most
+// poems have a paragraph per couplet, so paragraphs are kept as stanzas only if
+// there are several and each has more than one sentence. Otherwise the poem is
+// a single stanza. Joined, the stanzas are what NormalizeAndSplitParagraphs
+// returns.
+func SplitStanzas(paragraphs []string) [][]string {
+	var stanzas [][]string
+	split := true
+	for _, p := range paragraphs {
+		sentences := NormalizeAndSplitParagraphs([]string{p})
+		if len(sentences) == 0 {
+			continue
+		}
+		split = split && len(sentences) > 1
+		stanzas = append(stanzas, sentences)
+	}
+	if len(stanzas) > 1 && !split {
+		return [][]string{slices.Concat(stanzas...)}
+	}
+	return stanzas
+}
@@ -0,0 +1,137 @@
+package classifier
+
+import (
+	"strings"
+)
+
+// 散曲 types
+const (
+	CategoryQu   = "曲"
+	TypeXiaoling = "小令" // A single tune, including 带过曲
+	TypeTaoshu   = "套数" // A suite of tunes in one 宫调, usually closed by a 尾声
+)
+
+// gongDiaos maps the 宫调 found in 元曲 titles to the name they are filed
+// under, folding the longer 仙吕宫 style forms into the usual short ones
+var gongDiaos = map[string]string{
+	"黄钟": "黄钟", "黄钟宫": "黄钟",
+	"正宫":  "正宫",
+	"大石调": "大石调", "大石": "大石调",
+	"小石调": "小石调", "小石": "小石调",
+	"仙吕": "仙吕", "仙吕宫": "仙吕",
+	"中吕": "中吕", "中吕宫": "中吕",
+	"南吕": "南吕", "南吕宫": "南吕",
+	"双调":  "双调",
+	"越调":  "越调",
+	"商调":  "商调",
+	"商角调": "商角调",
+	"般涉调": "般涉调",
+	"高平调": "高平调",
+	"歇指调": "歇指调",
+	"宫调":  "宫调",
+	"道宫":  "道宫",
+	"角调":  "角调",
+}
+
+// quTitleSeparators end the 曲牌 in a title, before the subtitle
+const quTitleSeparators = "·・_ 　（("
+
+// ParseQuTitle returns the 宫调 and 曲牌 named by the title of a 元曲, e.g.
+// 双调 and 沉醉东风 for 【双调】沉醉东风·渔父. The 宫调 may also be written
+// as a leading segment (双调·沉醉东风), and is "" if the title has none.
+// The 宫调 is returned in simplified Chinese, the 曲牌 as written.
+func ParseQuTitle(title string) (gongDiao, quPai string) {
+	rest := NormalizeText(title)
+
+	if name, tail, ok := cutBracket(rest); ok {
+		// 【双调】沉醉东风
+		if canonical, ok := lookupGongDiao(name); ok {
+			gongDiao, rest = canonical, tail
+		}
+	} else if i := strings.IndexAny(rest, quTitleSeparators); i > 0 {
+		// 双调·沉醉东风
+		if canonical, ok := lookupGongDiao(rest[:i]); ok {
+			gongDiao, rest = canonical, rest[i:]
+		}
+	}
+
+	// 【沉醉东风】渔父
+	rest = strings.TrimLeft(rest, quTitleSeparators)
+	if name, _, ok := cutBracket(rest); ok {
+		return gongDiao, strings.TrimSpace(name)
+	}
+
+	if i := strings.IndexAny(rest, quTitleSeparators); i >= 0 {
+		rest = rest[:i]
+	}
+	return gongDiao, rest
+}
+
+// cutBracket splits 【name】tail
+func cutBracket(s string) (name, tail string, ok bool) {
+	after, ok := strings.CutPrefix(s, "【")
+	if !ok {
+		return "", "", false
+	}
+	return strings.Cut(after, "】")
+}
+
+// lookupGongDiao reports whether name is a 宫调, in either script
+func lookupGongDiao(name string) (string, bool) {
+	simplified, err := ToSimplified(strings.TrimSpace(name))
+	if err != nil {
+		return "", false
+	}
+	canonical, ok := gongDiaos[simplified]
+	return canonical, ok
+}
+
+// repeatTunes head a repeat of the tune before them (幺篇, 前腔) rather than
+// another tune, in 小令 as well as in suites
+var repeatTunes = map[string]bool{"幺": true, "幺篇": true, "么": true, "么篇": true, "前腔": true}
+
+// IsTaoshu reports whether a 元曲 is a 套数 rather than a 小令: the title
+// says so, or the text is made of at least two distinct tunes. The text of a
+// suite heads its tunes with 【曲牌】, often leaving the first, which the
+// title names, unheaded; 【尾声】 and other codas count as tunes. Repeats
+// (【幺篇】), 宫调 and the tunes a 带过曲 title joins don't, so a single
+// heading makes no suite by itself.
+func IsTaoshu(title string, paragraphs []string) bool {
+	if simplified, err := ToSimplified(title); err == nil && strings.Contains(simplified, "套数") {
+		return true
+	}
+
+	_, titleTune := ParseQuTitle(title)
+	tunes := make(map[string]bool)
+	for _, para := range paragraphs {
+		for rest := para; ; {
+			i := strings.Index(rest, "【")
+			if i < 0 {
+				break
+			}
+			name, tail, ok := cutBracket(rest[i:])
+			if !ok {
+				break
+			}
+			rest = tail
+			name = strings.TrimSpace(name)
+			if _, isGongDiao := lookupGongDiao(name); name == "" || isGongDiao || repeatTunes[name] || strings.Contains(titleTune, name) {
+				continue
+			}
+			tunes[name] = true
+		}
+	}
+
+	// The title's tune heads the first section when the text doesn't
+	opensUnheaded := len(paragraphs) > 0 && !strings.HasPrefix(strings.TrimSpace(paragraphs[0]), "【")
+	return len(tunes) >= 2 || len(tunes) == 1 && titleTune != "" && opensUnheaded
+}
+
+// classifyQu returns the type of a 元曲: 套数 or 小令. A 套数 whose text
+// does not head its tunes passes for a 小令, so 小令 is the less certain.
+func classifyQu(paragraphs []string, title string) PoetryTypeInfo {
+	if IsTaoshu(title, paragraphs) {
+		return PoetryTypeInfo{TypeName: TypeTaoshu, Category: CategoryQu, Confidence: 0.95}
+	}
+	return PoetryTypeInfo{TypeName: TypeXiaoling, Category: CategoryQu, Confidence: 0.85}
+}
@@ -0,0 +1,61 @@
+package classifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuTitle(t *testing.T) {
+	tests := []struct {
+		title        string
+		wantGongDiao string
+		wantQuPai    string
+	}{
+		{"【双调】沉醉东风", "双调", "沉醉东风"},
+		{"【双调】沉醉东风·渔父", "双调", "沉醉东风"},
+		{"【越调】天净沙_秋思", "越调", "天净沙"},
+		{"【南吕】一枝花 不伏老", "南吕", "一枝花"},
+		{"【仙吕宫】一半儿", "仙吕", "一半儿"},
+		{"【雙調】沉醉東風", "双调", "沉醉東風"},
+		{"【双调】雁儿落带得胜令", "双调", "雁儿落带得胜令"},
+		{"【双调】【沉醉东风】渔父", "双调", "沉醉东风"},
+		{"双调·沉醉东风·渔父", "双调", "沉醉东风"},
+		{"【沉醉东风】渔父", "", "沉醉东风"},
+		{"天净沙·秋思", "", "天净沙"},
+		{"山坡羊", "", "山坡羊"},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			gongDiao, quPai := ParseQuTitle(tt.title)
+			assert.Equal(t, tt.wantGongDiao, gongDiao)
+			assert.Equal(t, tt.wantQuPai, quPai)
+		})
+	}
+}
+
+func TestIsTaoshu(t *testing.T) {
+	tests := []struct {
+		name       string
+		title      string
+		paragraphs []string
+		want       bool
+	}{
+		{"single tune", "【越调】天净沙_秋思", []string{"枯藤老树昏鸦，小桥流水人家，古道西风瘦马。"}, false},
+		{"带过曲 is a 小令", "【双调】雁儿落带得胜令", []string{"云来山更佳，云去山如画。"}, false},
+		{"tunes headed in the text", "【南吕】一枝花_不伏老", []string{"攀出墙朵朵花，折临路枝枝柳。", "【梁州】我是个普天下郎君领袖。"}, true},
+		{"named in the title", "【正宫】端正好 套数", []string{"碧云天，黄花地。"}, true},
+		{"tunes and coda headed in the text", "【双调】夜行船_秋思", []string{"【夜行船】百岁光阴一梦蝶。", "【乔木查】想秦宫汉阙。", "【离亭宴煞】蛩吟罢一觉才宁贴。"}, true},
+		{"one heading in a headed text", "【中吕】山坡羊", []string{"【潼关怀古】峰峦如聚，波涛如怒。"}, false},
+		{"repeat of the tune", "【中吕】普天乐", []string{"折垂杨，几回首。", "【幺篇】柳丝长，情难收。"}, false},
+		{"带过曲 headed in the text", "【双调】雁儿落带得胜令", []string{"【雁儿落】云来山更佳。", "【得胜令】倚杖立云沙。"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTaoshu(tt.title, tt.paragraphs))
+		})
+	}
+}
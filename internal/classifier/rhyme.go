@@ -0,0 +1,247 @@
+package classifier
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Rhyme systems
+const (
+	RhymeSystemPingshui = "pingshui" // 平水韵, the rhymes of 诗
+	RhymeSystemCilin    = "cilin"    // 词林正韵, the rhymes of 词
+)
+
+// RhymePosition is a line whose last character rhymes, or should
+type RhymePosition struct {
+	Line       int    `json:"line"` // 1-based line number
+	Char       string `json:"char"`
+	Group      string `json:"group"`        // The rhyme group of the passage the line belongs to
+	OutOfRhyme bool   `json:"out_of_rhyme"` // 出韵: the character is not in Group
+}
+
+// RhymeAnalysis is the result of finding where a poem rhymes and in which
+// groups of a rhyme system
+type RhymeAnalysis struct {
+	System    string          `json:"system"`
+	Groups    []string        `json:"groups"` // In order of first use, e.g. 十一尤 or 第十二部
+	Positions []RhymePosition `json:"positions"`
+}
+
+// rhymeTable maps characters to the rhyme groups of one system
+type rhymeTable struct {
+	groups []string       // Group names, in the order ties are broken
+	index  map[rune][]int // Indices of the groups each character belongs to
+}
+
+var (
+	pingshuiTable = buildPingshuiTable()
+	cilinTable    = buildCilinTable()
+
+	// rhymeGroupNames maps each value RhymeGroup accepts to a group name
+	rhymeGroupNames = buildRhymeGroupNames()
+)
+
+// add records that the characters of chars belong to group i
+func (t *rhymeTable) add(i int, chars string) {
+	for _, r := range chars {
+		if !slices.Contains(t.index[r], i) {
+			t.index[r] = append(t.index[r], i)
+		}
+	}
+}
+
+func buildPingshuiTable() *rhymeTable {
+	t := &rhymeTable{index: make(map[rune][]int)}
+	for i, g := range pingshuiGroups {
+		t.groups = append(t.groups, g.name)
+		t.add(i, g.chars)
+	}
+	return t
+}
+
+func buildCilinTable() *rhymeTable {
+	chars := make(map[string]string, len(pingshuiGroups))
+	for _, g := range pingshuiGroups {
+		chars[g.name] = g.chars
+	}
+
+	t := &rhymeTable{index: make(map[rune][]int)}
+	for i, s := range cilinSections {
+		t.groups = append(t.groups, s.name)
+		for _, g := range s.groups {
+			if split, ok := cilinSplits[g]; ok {
+				t.add(i, split[s.name])
+			} else {
+				t.add(i, chars[g])
+			}
+		}
+	}
+	return t
+}
+
+func buildRhymeGroupNames() map[string]string {
+	names := make(map[string]string)
+	for _, g := range pingshuiGroups {
+		names[g.name] = g.name
+		// The rhyme character alone, e.g. 尤 for 十一尤
+		runes := []rune(g.name)
+		names[string(runes[len(runes)-1])] = g.name
+	}
+	for _, s := range cilinSections {
+		names[s.name] = s.name
+	}
+	return names
+}
+
+// RhymeGroup returns the name of the rhyme group value stands for: a group
+// of either system by name (十一尤, 第十二部), or a group of 平水韵 by its
+// rhyme character (尤). Traditional characters are accepted. It returns false
+// if value names no group.
+func RhymeGroup(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if simplified, err := ToSimplified(value); err == nil {
+		value = simplified
+	}
+	name, ok := rhymeGroupNames[value]
+	return name, ok
+}
+
+// AnalyzeRhyme finds the rhyming lines of a poem and the groups of system
+// they rhyme in, flagging lines out of rhyme (出韵).
+//
+// For 平水韵 the even lines must rhyme and the first may. Poems of up to eight
+// lines keep one rhyme; in longer ones the rhyme may change (换韵), and the
+// odd lines that open a new passage may rhyme too. For 词林正韵 the lines that
+// close a sentence (。！？；) must rhyme, the clauses (，) may, and the rhyme
+// may change anywhere.
+//
+// Groups are picked greedily, the one most lines rhyme in first. Each needs
+// two lines, one of them a line that must rhyme. Lines that must rhyme but
+// are in none of the groups are out of rhyme with the passage before them.
+// Characters the tables do not hold are left out. It returns nil if no two
+// lines rhyme.
+func AnalyzeRhyme(paragraphs []string, system string) *RhymeAnalysis {
+	var table *rhymeTable
+	switch system {
+	case RhymeSystemPingshui:
+		table = pingshuiTable
+	case RhymeSystemCilin:
+		table = cilinTable
+	default:
+		return nil
+	}
+
+	lines, stops := splitClauses(paragraphs)
+	// Look characters up in simplified script, the script of the tables
+	keys := lines
+	if simplified, err := ToSimplifiedArray(paragraphs); err == nil {
+		if converted, _ := splitClauses(simplified); len(converted) == len(lines) {
+			keys = converted
+		}
+	}
+
+	singleRhyme := system == RhymeSystemPingshui && len(lines) <= LvshiLines
+	required := make([]bool, len(lines))
+	optional := make([]bool, len(lines))
+	groupsOf := make([][]int, len(lines))
+	for i := range lines {
+		if system == RhymeSystemPingshui {
+			required[i] = i%2 == 1
+			optional[i] = i == 0 || !required[i] && !singleRhyme
+		} else {
+			required[i] = stops[i]
+			optional[i] = !stops[i]
+		}
+		groupsOf[i] = table.index[lastChar(keys[i])]
+	}
+
+	// Pick the groups greedily, the one most lines rhyme in first
+	groupOf := make(map[int]int)
+	for picked := 0; !singleRhyme || picked == 0; picked++ {
+		counts := make([]int, len(table.groups))
+		hasRequired := make([]bool, len(table.groups))
+		for i := range lines {
+			if _, ok := groupOf[i]; ok || !required[i] && !optional[i] {
+				continue
+			}
+			for _, g := range groupsOf[i] {
+				counts[g]++
+				hasRequired[g] = hasRequired[g] || required[i]
+			}
+		}
+		best := -1
+		for g, count := range counts {
+			if count >= 2 && hasRequired[g] && (best < 0 || count > counts[best]) {
+				best = g
+			}
+		}
+		if best < 0 {
+			break
+		}
+		for i := range lines {
+			if _, ok := groupOf[i]; !ok && (required[i] || optional[i]) && slices.Contains(groupsOf[i], best) {
+				groupOf[i] = best
+			}
+		}
+	}
+	if len(groupOf) == 0 {
+		return nil
+	}
+
+	analysis := &RhymeAnalysis{System: system, Groups: []string{}}
+	// Lines before the first that rhymes belong to the first passage
+	group := groupOf[slices.Min(slices.Collect(maps.Keys(groupOf)))]
+	for i, line := range lines {
+		g, rhymes := groupOf[i]
+		if rhymes {
+			group = g
+		} else if !required[i] || len(groupsOf[i]) == 0 {
+			continue
+		}
+
+		name := table.groups[group]
+		if rhymes && !slices.Contains(analysis.Groups, name) {
+			analysis.Groups = append(analysis.Groups, name)
+		}
+		analysis.Positions = append(analysis.Positions, RhymePosition{
+			Line:       i + 1,
+			Char:       string(lastChar(line)),
+			Group:      name,
+			OutOfRhyme: !rhymes,
+		})
+	}
+	return analysis
+}
+
+// lastChar returns the last character of a line, ignoring punctuation
+func lastChar(line string) rune {
+	runes := []rune(removePunctuation(line))
+	if len(runes) == 0 {
+		return 0
+	}
+	return runes[len(runes)-1]
+}
+
+// splitClauses splits paragraphs into lines as expandParagraphs does, and
+// reports whether each closes a sentence (。！？；or the end of a paragraph)
+// rather than a clause (，)
+func splitClauses(paragraphs []string) (lines []string, stops []bool) {
+	for _, para := range paragraphs {
+		start := 0
+		flush := func(end int, stop bool) {
+			if line := strings.TrimSpace(para[start:end]); line != "" {
+				lines = append(lines, line)
+				stops = append(stops, stop)
+			}
+		}
+		for i, r := range para {
+			if strings.ContainsRune("。！？；，", r) {
+				flush(i, r != '，')
+				start = i + len(string(r))
+			}
+		}
+		flush(len(para), true)
+	}
+	return lines, stops
+}
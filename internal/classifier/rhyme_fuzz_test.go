@@ -0,0 +1,44 @@
+package classifier
+
+import (
+	"slices"
+	"testing"
+	"unicode/utf8"
+)
+
+// FuzzAnalyzeRhyme tests the AnalyzeRhyme function with random inputs
+func FuzzAnalyzeRhyme(f *testing.F) {
+	f.Add("床前明月光，疑是地上霜。", "举头望明月，低头思故乡。", true)
+	f.Add("常记溪亭日暮，沉醉不知归路。", "争渡，争渡，惊起一滩鸥鹭。", false)
+	f.Add("", "", true)
+	f.Add("，。", "；", false)
+
+	f.Fuzz(func(t *testing.T, p1, p2 string, shi bool) {
+		if !utf8.ValidString(p1) || !utf8.ValidString(p2) {
+			return
+		}
+
+		system := RhymeSystemCilin
+		if shi {
+			system = RhymeSystemPingshui
+		}
+
+		// Should not panic
+		result := AnalyzeRhyme([]string{p1, p2}, system)
+		if result == nil {
+			return
+		}
+
+		if len(result.Groups) == 0 {
+			t.Error("AnalyzeRhyme returned an analysis without groups")
+		}
+		for _, p := range result.Positions {
+			if p.Line < 1 {
+				t.Errorf("position on line %d", p.Line)
+			}
+			if !p.OutOfRhyme && !slices.Contains(result.Groups, p.Group) {
+				t.Errorf("line %d rhymes in %s, not one of %v", p.Line, p.Group, result.Groups)
+			}
+		}
+	})
+}
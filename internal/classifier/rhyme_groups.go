@@ -0,0 +1,200 @@
+package classifier
+
+// rhymeGroup is one rhyme group of 平水韵 with the common characters it
+// holds, in simplified script. A character with readings in several groups
+// is listed in each.
+type rhymeGroup struct {
+	name  string // e.g. 一东
+	chars string
+}
+
+// pingshuiGroups are the 106 groups of 平水韵: 上平, 下平, 上声, 去声 and 入声
+// in the order of the rhyme books. Ties between groups are broken in this
+// order, so 平声 groups come first.
+var pingshuiGroups = []rhymeGroup{
+	// 上平声
+	{"一东", "东同铜桐筒童僮瞳中衷忠虫冲终忡崇嵩戎绒弓躬宫融雄熊穹穷冯风枫疯丰充隆窿空公功工攻蒙濛朦笼胧珑聋洪红鸿虹丛翁聪葱通蓬篷烘潼骢"},
+	{"二冬", "冬农宗踪淙琮钟锺龙舂松淞冲容蓉溶榕镕庸慵墉佣封胸凶汹匈雍邕痈浓秾侬脓醲重从逢缝峰锋烽蜂丰恭供共邛蛩筇茸龚颙喁纵"},
+	{"三江", "江扛杠缸釭窗邦降双泷庞逄腔撞幢桩跫"},
+	{"四支", "支枝肢移为垂吹陂碑奇宜仪皮儿离篱漓璃施知蜘驰池规危夷姨师狮姿资迟墀龟眉湄悲之芝时诗棋旗期欺基箕姬疑辞词祠丝司思斯私葵医帷维惟唯遗持随痴卮麋螭麾弥慈肌饥脂雌披嬉熙狸炊兹滋差疲茨卑亏蕤骑歧岐谁窥疵赀羁彝髭颐糜衰锥夔涯伊追缁椎罴篪萎匙脾坻骊尸綦怡尼漪累牺蚩其而治祇淇琪祺麒"},
+	{"五微", "微薇晖辉徽挥韦围帏违闱霏菲妃飞非扉肥威祈旂畿机几讥矶稀希衣依归饥欷巍晞唏圻颀沂"},
+	{"六鱼", "鱼渔初书舒居裾琚车渠余予誉舆胥狙锄疏蔬梳虚嘘徐猪闾庐驴诸除储如墟於畬蛆沮祛蘧淤与菹樗摅滁蜍"},
+	{"七虞", "虞愚娱隅无芜巫于盂衢瞿劬儒濡襦须需株诛蛛殊瑜榆愉逾渝萸臾俞腴区驱躯岖朱珠趋扶符凫雏刍敷夫肤纡输枢厨俱驹拘模谟蒲胡湖瑚糊葫蝴醐乎呼壶狐弧孤辜姑沽酤菰鸪徒途涂荼图屠奴吾梧吴租卢鲈炉芦垆颅苏酥乌污枯粗都铺禺诬竽吁蹰孚逋晡徂殂"},
+	{"八齐", "齐蹊溪鸡低堤题提啼蹄西栖犀嘶撕梯鼙迷泥妻萋凄藜黎犁篦圭闺奎携畦睽倪霓鲵稽兮醯脐跻齑鹈嵇批"},
+	{"九佳", "佳街鞋牌柴钗差崖涯偕阶皆谐骸排乖怀淮豺侪埋霾斋槐娃蛙哇洼"},
+	{"十灰", "灰恢诙魁回徊茴梅枚媒煤雷罍催摧堆陪培裴徘杯醅嵬推隈桅开哀埃台苔抬该垓才材财裁来莱徕灾猜孩胎皑腮鳃"},
+	{"十一真", "真因茵姻辛新薪晨辰臣人仁神亲申伸身宾滨邻鳞麟珍尘陈春椿津秦频苹颦嚬蘋银垠筠巾民贫淳醇纯鹑唇伦纶轮沦匀旬询恂巡驯钧均臻榛寅彬遵循甄湮荀逡莼"},
+	{"十二文", "文纹蚊雯闻云纭芸耘氛分纷芬焚坟汾群裙君军勤斤筋勋薰曛熏荤氲殷欣芹"},
+	{"十三元", "元原源园猿辕垣袁援媛爰烦繁蕃樊翻番幡萱喧暄冤鸳言轩掀藩魂浑温孙荪飧门尊樽存蹲敦墩暾屯豚村盆奔论坤昆琨鲲髡昏婚阍痕根恩吞"},
+	{"十四寒", "寒韩翰丹单殚箪郸安鞍难餐滩坛檀弹残干肝竿乾阑栏澜兰看刊丸纨完桓端湍酸团抟攒官观冠棺欢宽盘蟠磐般潘漫谩叹珊跚鸾銮峦瘢汍玕邯"},
+	{"十五删", "删关弯湾还环鬟寰圜班斑颁蛮颜奸菅攀顽山间闲艰悭潸孱潺鳏湲"},
+
+	// 下平声
+	{"一先", "先前千阡笺天坚肩贤弦烟燕莲怜田填钿年颠巅牵妍研眠渊涓捐娟边编鞭悬泉迁仙鲜钱煎然延筵蜒毡蝉婵禅缠连联篇偏翩蹁绵全诠筌痊荃悛镌宣穿川缘鸢旋璇船涎专砖圆员乾虔愆骞权拳椽传焉鞯褰搴铅舷沿癫便骈胼畋阗蠲跹湔旃嫣膻"},
+	{"二萧", "萧箫潇挑貂刁凋雕迢条跳苕调枭聊辽寥撩僚寮燎尧幺宵消霄绡销逍硝超朝潮嚣樵谯骄娇焦蕉椒礁饶烧遥摇谣瑶韶昭招飙标镳漂飘瓢剽缥苗描猫腰邀乔桥侨妖夭要翘骁浇侥鹪鸮"},
+	{"三肴", "肴巢交郊蛟鲛胶茅嘲钞抄包苞胞匏庖抛爻梢捎筲艄坳敲崤铙咆哮淆"},
+	{"四豪", "豪毫操髦刀萄桃陶淘猱糟漕槽袍挠蒿篙涛皋号鳌螯翱敖嗷獒熬遨曹遭羔高膏糕嘈搔骚缫毛滔韬牢醪逃劳叨饕褒舠"},
+	{"五歌", "歌哥多罗萝箩锣逻河何荷苛戈阿和禾波科柯轲珂陀驼佗沱跎酡鼍娥蛾鹅峨俄莪哦过磨摩魔螺娑蓑梭挲傩窠颇坡婆莎蹉搓嵯磋拖驮讹诃呵窝涡倭锅那"},
+	{"六麻", "麻花霞家茶华哗沙纱砂裟车牙芽衙蛇瓜斜邪嘉瑕遐加笳枷鸦丫桠遮叉杈差葩奢琶杷爬赊涯夸誇巴芭耶爷嗟楂查槎蟆蛙哇娃洼些拿"},
+	{"七阳", "阳杨扬羊洋徉佯香乡光昌堂棠章彰漳樟璋嫜獐张王房芳长塘妆常凉霜藏场央泱鸯秧狼郎廊榔琅床方浆觞梁粱粮量娘庄黄簧皇凰惶徨隍蝗篁仓沧苍装殇襄骧相湘箱缃厢创忘芒茫忙亡望尝偿樯墙蔷嫱枪坊囊唐糖搪螳狂强肠康冈刚纲钢匡筐眶荒遑行妨翔祥详良航杭倡伥羌将姜疆僵缰汤当铛裆昂桑丧瓤穰禳"},
+	{"八庚", "庚更羹盲横觥彭棚亨英瑛烹平评枰坪京惊荆明盟鸣荣莹兵卿生甥笙牲擎鲸黥迎行衡耕萌氓宏闳茎罂莺樱鹦泓橙争筝铮清情晴精睛菁晶旌盈楹瀛嬴营萦婴缨璎贞成城诚呈程酲声征正轻名并倾琼赓撑峥嵘狞坑铿枨"},
+	{"九青", "青经泾形刑型陉亭庭廷霆蜓停婷渟葶丁宁钉仃馨星腥醒惺俜娉灵龄铃苓伶零玲翎聆瓴囹棂蛉舲泠听厅汀冥溟暝铭瓶屏萍荧萤扃坰"},
+	{"十蒸", "蒸承丞惩陵凌绫菱冰膺鹰应蝇绳乘升胜兴缯罾凭仍兢矜征澄登灯僧增曾憎层能棱朋鹏弘肱腾滕藤誊恒崩称凝塍薨楞"},
+	{"十一尤", "尤邮优忧流旒留骝刘由油游猷悠攸犹蝣牛修羞秋楸鞦周州洲舟酬仇柔揉俦畴筹稠绸惆裯丘邱抽瘳湫遒收鸠搜馊飕驺愁休囚泅求裘球毬浮谋牟眸矛侯喉猴篌讴瓯沤鸥楼娄偻陬偷头投骰钩沟篝勾兜幽虬樛啾纠"},
+	{"十二侵", "侵寻浔林霖琳淋临针斟箴砧沉深淫心琴禽擒钦衾嵚吟今襟金音阴喑岑涔簪琛森参骎歆谌忱壬任黔"},
+	{"十三覃", "覃潭谭昙参骖南男楠喃谙庵含涵函岚蚕探贪耽龛堪戡谈郯痰甘柑泔三酣篮蓝褴婪惭担憨坍"},
+	{"十四盐", "盐檐廉帘濂镰嫌严占髯谦奁纤签瞻詹蟾炎添兼缣尖潜阎粘黏淹甜恬拈暹渐歼佥苫沾钳箝"},
+	{"十五咸", "咸缄谗馋衔岩帆衫杉监凡芟嵌掺搀巉"},
+
+	// 上声
+	{"一董", "董懂动孔总笼拢桶捅汞蠓"},
+	{"二肿", "肿种踵宠垄陇拥冗勇涌踊蛹俑恐拱巩捧奉冢耸悚竦重"},
+	{"三讲", "讲港棒蚌项"},
+	{"四纸", "纸只咫是氏豕此紫彼毁委诡髓累妓绮掎倚弛侈靡被婢俾技徙玺尔迩弭旨指视美否鄙比妣秕匕几矢雉死水止趾址沚芷市恃耻齿史使始驶里理鲤俚已以似祀巳子梓耳拟起杞喜士仕滓履垒癸揆诔蕊轨晷"},
+	{"五尾", "尾鬼苇卉虺几扆岂斐篚韪伟炜"},
+	{"六语", "语圉吕侣旅膂女去举莒巨拒距炬许处杵渚煮暑黍鼠楚础阻俎所汝与予序叙绪屿伫纻苎贮墅龃醑"},
+	{"七麌", "麌雨羽禹宇舞妩庑父府俯腐辅甫脯斧釜抚武侮鹉主取聚乳竖柱数缕矩伛补鲁橹卤虏古股贾鼓瞽苦土吐圃谱浦普虎户扈怙祜堵赌睹肚杜午五伍坞努弩祖组"},
+	{"八荠", "荠礼醴体米启陛洗邸底抵弟悌涕济"},
+	{"九蟹", "蟹解骇买洒楷摆罢矮"},
+	{"十贿", "贿悔每浼猥磊垒儡蕾罪馁改采彩海在宰载醢铠恺凯待怠殆亥乃"},
+	{"十一轸", "轸敏允引蚓尹尽忍准隼笋盾闵悯泯陨殒窘菌紧稹缜牝哂"},
+	{"十二吻", "吻粉愤忿隐谨近蕴"},
+	{"十三阮", "阮远返反阪饭偃宛婉畹苑晚本损稳很恳垦衮滚鲧阃悃忖撙畚沌遁"},
+	{"十四旱", "旱暖管满短馆缓盥碗款懒伞散诞但坦袒瓒纂卵断罕侃亶"},
+	{"十五潸", "潸眼简版板限栈柬拣产铲赧撰馔睆"},
+	{"十六铣", "铣典殄显腆辇浅遣善剪翦展转演犬免勉冕辩卷选篆扁喘舛兖衍践蹇茧"},
+	{"十七篠", "篠小表鸟了晓少绕扰沼夭窈窕杳眇渺缈袅杪缭挑皎皦缴悄剿"},
+	{"十八巧", "巧饱卯爪鲍炒拗狡绞搅挠"},
+	{"十九皓", "皓宝藻早枣老好道稻岛蚤草考昊浩保葆褓堡恼脑倒捣讨抱燥扫嫂稿缟槁祷澡"},
+	{"二十哿", "哿火舸可我左坐颇果裹朵锁琐堕妥娜跛簸祸夥"},
+	{"二十一马", "马下者也野雅瓦寡假贾把写泻舍社夏冶惹姐且"},
+	{"二十二养", "养痒象像想响飨仰掌长丈杖仗上往枉网罔纺仿访两魉赏莽蟒广荡党朗爽敞氅晃幌壤攘嚷享奖桨蒋"},
+	{"二十三梗", "梗影景境警井静靖请领岭整省颈逞骋猛永丙秉炳冷幸杏耿打"},
+	{"二十四迥", "迥炯顶鼎醒酊挺艇茗拯等肯"},
+	{"二十五有", "有酒首手口母后柳友妇斗狗久九韭玖走叟受寿守否丑纽钮阜负舅臼咎偶藕牖诱莠朽扣帚薮亩某缶"},
+	{"二十六寝", "寝饮锦品枕审甚稔沈廪凛"},
+	{"二十七感", "感览揽胆澹淡惨坎敢橄毯颔菡"},
+	{"二十八俭", "俭险检脸染冉奄掩敛琰点忝渐贬"},
+	{"二十九豏", "豏范犯减斩舰槛湛黯"},
+
+	// 去声
+	{"一送", "送梦凤洞众瓮弄贡冻栋仲中恸痛讽空控哄"},
+	{"二宋", "宋重用颂诵统纵讼种综俸供共"},
+	{"三绛", "绛降撞巷"},
+	{"四寘", "寘置事地意志治思泪吏赐字义议谊利器位戏寄睡致翠醉骑记异媚粹季肆遂穗帅避四自寺嗣使试恣次刺肄坠瑞寐翅臂被易智伪二贰备庇痹至懿"},
+	{"五未", "未味气贵费沸尉畏慰蔚魏纬胃谓渭汇讳卉毅既衣"},
+	{"六御", "御处去虑誉署据驭曙助絮著箸恕庶预豫遽翥诅疏茹"},
+	{"七遇", "遇路赂露鹭树度渡赋布步固素具数怒雾务附驻注铸住句妒蠹暮墓慕募趣娶故顾雇库互护误悟寤晤屡兔吐措诉塑傅赴讣付鹜骛"},
+	{"八霁", "霁制计势世丽岁卫济第桂荔隶替帝弟涕契滞闭系逝誓翳细婿例厉励砺蒂缀锐税蔽敝弊髻蓟继惠慧蕙憩睇袂艺毙"},
+	{"九泰", "泰会带外盖大濑赖籁蔡害最贝沛霭蔼艾奈柰太汰兑绘脍桧侩荟狯旆丐"},
+	{"十卦", "卦挂懈隘卖画派债寨晒稗拜怪坏界戒介届芥械疥快话"},
+	{"十一队", "队内辈佩背对碎晦妹昧退耐塞代戴载菜爱概慨溉赛贷态再逮黛碍"},
+	{"十二震", "震信印进润阵镇刃顺慎鬓晋闰峻骏俊烬讯迅殡衬趁舜瞬吝"},
+	{"十三问", "问闻运晕韵训粪奋忿分郡靳近"},
+	{"十四愿", "愿怨万饭献健建劝券远宪蔓贩论顿钝困闷寸逊嫩恨艮"},
+	{"十五翰", "翰岸汉难断乱叹干旦散算烂灿按案炭弹馆贯玩唤换涣焕半伴判泮冠观漫幔看赞汗悍"},
+	{"十六谏", "谏惯患宦涧雁晏谩栈绊盼慢豢"},
+	{"十七霰", "霰殿面县变箭战扇煽便片眷恋见宴燕练炼线电甸佃倩遍院选贱荐羡砚现绢卷倦传转串钏溅茜眩炫眄"},
+	{"十八啸", "啸笑照庙窍妙诏召调钓吊叫少耀要峤轿醮烧俏肖哨掉眺"},
+	{"十九效", "效校教貌较觉孝棹罩乐豹钞闹"},
+	{"二十号", "号帽报导盗操到暴告奥灶躁好耗傲冒悼涝噪"},
+	{"二十一个", "个贺佐做饿坐座卧过破和挫课唾磨货"},
+	{"二十二禡", "禡驾夜下谢榭罢夏暇霸灞嫁赦借藉炙蔗化价架稼射麝舍跨诈怕亚骂卸泻"},
+	{"二十三漾", "漾上望相将状帐浪唱让旷壮放向仗畅量酿匠障瘴葬藏丧宕抗况王妄怅荡亮谅当饷"},
+	{"二十四敬", "敬命正令政性镜盛行圣咏姓庆映病柄郑劲净竞并硬孟"},
+	{"二十五径", "径定听胜乘应兴赠邓凳磴孕称罄佞"},
+	{"二十六宥", "宥候就授售寿秀绣宿奏富兽斗漏陋覆又右佑囿幼旧臭袖岫昼骤溜瘦透凑豆窦逗构购媾够茂贸谬扣寇"},
+	{"二十七沁", "沁饮禁任荫浸鸩枕临赁"},
+	{"二十八勘", "勘暗滥担憾缆淡"},
+	{"二十九艳", "艳剑念验店占欠厌敛堑垫"},
+	{"三十陷", "陷鉴监帆泛梵忏蘸"},
+
+	// 入声
+	{"一屋", "屋木竹目服福禄熟谷肉族鹿腹菊陆轴逐牧伏宿读犊渎牍椟黩复粥肃育六缩哭幅斛戮仆畜蓄叔淑菽独卜馥沐速祝麓镞蹙筑穆睦覆秃扑郁辘簇蹴"},
+	{"二沃", "沃俗玉足曲粟烛属录辱狱绿毒局欲束鹄蜀促触续督赎浴酷瞩躅褥旭蓐"},
+	{"三觉", "觉角桷岳乐捉朔数卓琢啄剥驳邈学握幄渥浊濯擢壳确"},
+	{"四质", "质日笔出室实疾术一乙壹吉秩密率律逸佚失漆栗毕恤蜜橘溢瑟膝匹黜弼七叱卒虱悉谧轶诘戌佶栉昵窒必侄蛭秫蟀嫉"},
+	{"五物", "物佛拂屈郁乞掘讫吃绂弗诎崛勿不"},
+	{"六月", "月骨发阙越谒没伐罚卒竭窟笏钺歇蝎袜筏厥蹶曰阀殁兀突忽勃渤猝讷"},
+	{"七曷", "曷达末阔活钵脱夺褐割沫葛渴拨豁括抹秣遏挞萨跋撮掇"},
+	{"八黠", "黠札拔猾滑八察杀刹轧瞎刷刮"},
+	{"九屑", "屑节雪绝列烈结穴说血舌洁别缺裂热决铁灭折拙切悦辙诀泄咽噎彻澈哲鳖设啮劣掣蔑撇捏孑碣揭杰竭阅冽洌"},
+	{"十药", "药薄恶略作乐落阁鹤爵若约脚雀幕洛壑索郭博错跃酌托削铄度诺酪络漠寞莫烁鹊萼鄂愕谔鳄斫灼勺着著嚼却"},
+	{"十一陌", "陌石客白泽伯迹宅席策碧籍格役帛戟璧驿麦额柏魄积脉夕液册尺隙逆画百辟赤易革脊获翮屐适剧碛隔益窄核舶掷责惜僻癖跖"},
+	{"十二锡", "锡壁历枥沥击绩笛敌滴镝檄激寂觅析淅溺狄荻戚涤的吃踢剔霹"},
+	{"十三职", "职织识国德食蚀色力翼墨极息直得北黑侧饰贼刻则塞式轼域殖植敕饬匿忆亿臆抑弋测特勒逼仄穑啬棘"},
+	{"十四缉", "缉辑立集邑急入泣湿习给十拾什袭及级涩粒揖汁蛰笠执隰汲吸"},
+	{"十五合", "合塔答纳榻杂腊蜡匝阖蛤衲沓鸽踏飒拉"},
+	{"十六叶", "叶帖贴牒接猎妾蝶叠箧涉捷颊楫摄蹑谍协侠荚睫燮"},
+	{"十七洽", "洽狭峡法甲业邺匣压鸭乏怯劫胁插夹"},
+}
+
+// cilinSections are the 19 部 of 词林正韵. Each gathers 平水韵 groups whole
+// or in part; a 部 takes its 平, 上 and 去 groups together, since 词 may rhyme
+// across them.
+var cilinSections = []struct {
+	name   string
+	groups []string
+}{
+	{"第一部", []string{"一东", "二冬", "一董", "二肿", "一送", "二宋"}},
+	{"第二部", []string{"三江", "七阳", "三讲", "二十二养", "三绛", "二十三漾"}},
+	{"第三部", []string{"四支", "五微", "八齐", "十灰", "四纸", "五尾", "八荠", "十贿", "四寘", "五未", "八霁", "九泰", "十一队"}},
+	{"第四部", []string{"六鱼", "七虞", "六语", "七麌", "六御", "七遇"}},
+	{"第五部", []string{"九佳", "十灰", "九蟹", "十贿", "九泰", "十卦", "十一队"}},
+	{"第六部", []string{"十一真", "十二文", "十三元", "十一轸", "十二吻", "十三阮", "十二震", "十三问", "十四愿"}},
+	{"第七部", []string{"十三元", "十四寒", "十五删", "一先", "十三阮", "十四旱", "十五潸", "十六铣", "十四愿", "十五翰", "十六谏", "十七霰"}},
+	{"第八部", []string{"二萧", "三肴", "四豪", "十七篠", "十八巧", "十九皓", "十八啸", "十九效", "二十号"}},
+	{"第九部", []string{"五歌", "二十哿", "二十一个"}},
+	{"第十部", []string{"九佳", "六麻", "二十一马", "十卦", "二十二禡"}},
+	{"第十一部", []string{"八庚", "九青", "十蒸", "二十三梗", "二十四迥", "二十四敬", "二十五径"}},
+	{"第十二部", []string{"十一尤", "二十五有", "二十六宥"}},
+	{"第十三部", []string{"十二侵", "二十六寝", "二十七沁"}},
+	{"第十四部", []string{"十三覃", "十四盐", "十五咸", "二十七感", "二十八俭", "二十九豏", "二十八勘", "二十九艳", "三十陷"}},
+	{"第十五部", []string{"一屋", "二沃"}},
+	{"第十六部", []string{"三觉", "十药"}},
+	{"第十七部", []string{"四质", "十一陌", "十二锡", "十三职", "十四缉"}},
+	{"第十八部", []string{"五物", "六月", "七曷", "八黠", "九屑", "十六叶"}},
+	{"第十九部", []string{"十五合", "十七洽"}},
+}
+
+// cilinSplits lists, for each 平水韵 group that 词林正韵 divides between two
+// 部, the characters that belong to each. A character of a split group
+// belongs to a 部 only if it is listed there.
+var cilinSplits = map[string]map[string]string{
+	"九佳": {
+		"第五部": "街鞋牌柴钗差崖偕阶皆谐骸排乖怀淮豺侪埋霾斋槐",
+		"第十部": "佳涯娃蛙哇洼",
+	},
+	"十灰": {
+		"第三部": "灰恢诙魁回徊茴梅枚媒煤雷罍催摧堆陪培裴徘杯醅嵬推隈桅",
+		"第五部": "开哀埃台苔抬该垓才材财裁来莱徕灾猜孩胎皑腮鳃",
+	},
+	"十贿": {
+		"第三部": "贿悔每浼猥磊垒儡蕾罪馁",
+		"第五部": "改采彩海在宰载醢铠恺凯待怠殆亥乃",
+	},
+	"九泰": {
+		"第三部": "会外最贝沛兑绘脍桧侩荟狯旆",
+		"第五部": "泰带盖大濑赖籁蔡害霭蔼艾奈柰太汰丐",
+	},
+	"十卦": {
+		"第五部": "懈隘卖派债寨晒稗拜怪坏界戒介届芥械疥快",
+		"第十部": "卦挂画话",
+	},
+	"十一队": {
+		"第三部": "队内辈佩背对碎晦妹昧退",
+		"第五部": "耐塞代戴载菜爱概慨溉赛贷态再逮黛碍",
+	},
+	"十三元": {
+		"第六部": "魂浑温孙荪飧门尊樽存蹲敦墩暾屯豚村盆奔论坤昆琨鲲髡昏婚阍痕根恩吞",
+		"第七部": "元原源园猿辕垣袁援媛爰烦繁蕃樊翻番幡萱喧暄冤鸳言轩掀藩",
+	},
+	"十三阮": {
+		"第六部": "本损稳很恳垦衮滚鲧阃悃忖撙畚沌遁",
+		"第七部": "阮远返反阪饭偃宛婉畹苑晚",
+	},
+	"十四愿": {
+		"第六部": "论顿钝困闷寸逊嫩恨艮",
+		"第七部": "愿怨万饭献健建劝券远宪蔓贩",
+	},
+}
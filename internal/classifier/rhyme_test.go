@@ -0,0 +1,204 @@
+package classifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeRhyme(t *testing.T) {
+	tests := []struct {
+		name       string
+		paragraphs []string
+		system     string
+		want       *RhymeAnalysis
+	}{
+		{
+			name:       "首句入韵",
+			paragraphs: []string{"床前明月光，疑是地上霜。", "举头望明月，低头思故乡。"},
+			system:     RhymeSystemPingshui,
+			want: &RhymeAnalysis{
+				System: RhymeSystemPingshui,
+				Groups: []string{"七阳"},
+				Positions: []RhymePosition{
+					{Line: 1, Char: "光", Group: "七阳"},
+					{Line: 2, Char: "霜", Group: "七阳"},
+					{Line: 4, Char: "乡", Group: "七阳"},
+				},
+			},
+		},
+		{
+			name:       "首句不入韵",
+			paragraphs: []string{"白日依山尽，黄河入海流。", "欲穷千里目，更上一层楼。"},
+			system:     RhymeSystemPingshui,
+			want: &RhymeAnalysis{
+				System: RhymeSystemPingshui,
+				Groups: []string{"十一尤"},
+				Positions: []RhymePosition{
+					{Line: 2, Char: "流", Group: "十一尤"},
+					{Line: 4, Char: "楼", Group: "十一尤"},
+				},
+			},
+		},
+		{
+			name: "出韵",
+			paragraphs: []string{
+				"国破山河在，城春草木深。", "感时花溅泪，恨别鸟惊心。",
+				"烽火连三月，家书抵万金。", "白头搔更短，浑欲不胜冠。",
+			},
+			system: RhymeSystemPingshui,
+			want: &RhymeAnalysis{
+				System: RhymeSystemPingshui,
+				Groups: []string{"十二侵"},
+				Positions: []RhymePosition{
+					{Line: 2, Char: "深", Group: "十二侵"},
+					{Line: 4, Char: "心", Group: "十二侵"},
+					{Line: 6, Char: "金", Group: "十二侵"},
+					{Line: 8, Char: "冠", Group: "十二侵", OutOfRhyme: true},
+				},
+			},
+		},
+		{
+			name: "换韵",
+			paragraphs: []string{
+				"北风卷地白草折，胡天八月即飞雪。", "忽如一夜春风来，千树万树梨花开。",
+				"散入珠帘湿罗幕，狐裘不暖锦衾薄。", "将军角弓不得控，都护铁衣冷难着。",
+				"瀚海阑干百丈冰，愁云惨淡万里凝。",
+			},
+			system: RhymeSystemPingshui,
+			want: &RhymeAnalysis{
+				System: RhymeSystemPingshui,
+				Groups: []string{"九屑", "十灰", "十药", "十蒸"},
+				Positions: []RhymePosition{
+					{Line: 1, Char: "折", Group: "九屑"},
+					{Line: 2, Char: "雪", Group: "九屑"},
+					{Line: 3, Char: "来", Group: "十灰"},
+					{Line: 4, Char: "开", Group: "十灰"},
+					{Line: 5, Char: "幕", Group: "十药"},
+					{Line: 6, Char: "薄", Group: "十药"},
+					{Line: 8, Char: "着", Group: "十药"},
+					{Line: 9, Char: "冰", Group: "十蒸"},
+					{Line: 10, Char: "凝", Group: "十蒸"},
+				},
+			},
+		},
+		{
+			name:       "词 叠句",
+			paragraphs: []string{"常记溪亭日暮，沉醉不知归路。", "兴尽晚回舟，误入藕花深处。", "争渡，争渡，惊起一滩鸥鹭。"},
+			system:     RhymeSystemCilin,
+			want: &RhymeAnalysis{
+				System: RhymeSystemCilin,
+				Groups: []string{"第四部"},
+				Positions: []RhymePosition{
+					{Line: 1, Char: "暮", Group: "第四部"},
+					{Line: 2, Char: "路", Group: "第四部"},
+					{Line: 4, Char: "处", Group: "第四部"},
+					{Line: 5, Char: "渡", Group: "第四部"},
+					{Line: 6, Char: "渡", Group: "第四部"},
+					{Line: 7, Char: "鹭", Group: "第四部"},
+				},
+			},
+		},
+		{
+			name:       "词 换韵",
+			paragraphs: []string{"平林漠漠烟如织，寒山一带伤心碧。", "暝色入高楼，有人楼上愁。", "玉阶空伫立，宿鸟归飞急。", "何处是归程？长亭更短亭。"},
+			system:     RhymeSystemCilin,
+			want: &RhymeAnalysis{
+				System: RhymeSystemCilin,
+				Groups: []string{"第十七部", "第十二部", "第十一部"},
+				Positions: []RhymePosition{
+					{Line: 1, Char: "织", Group: "第十七部"},
+					{Line: 2, Char: "碧", Group: "第十七部"},
+					{Line: 3, Char: "楼", Group: "第十二部"},
+					{Line: 4, Char: "愁", Group: "第十二部"},
+					{Line: 5, Char: "立", Group: "第十七部"},
+					{Line: 6, Char: "急", Group: "第十七部"},
+					{Line: 7, Char: "程", Group: "第十一部"},
+					{Line: 8, Char: "亭", Group: "第十一部"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, AnalyzeRhyme(tt.paragraphs, tt.system))
+		})
+	}
+}
+
+func TestAnalyzeRhymeNoRhyme(t *testing.T) {
+	tests := []struct {
+		name       string
+		paragraphs []string
+		system     string
+	}{
+		{"empty", nil, RhymeSystemPingshui},
+		{"single line", []string{"白日依山尽"}, RhymeSystemPingshui},
+		{"no shared group", []string{"白日依山尽，黄河入海流。", "欲穷千里目，更上一层天。"}, RhymeSystemPingshui},
+		{"unknown characters", []string{"abc，xyz。", "abc，xyz。"}, RhymeSystemPingshui},
+		{"unknown system", []string{"床前明月光，疑是地上霜。", "举头望明月，低头思故乡。"}, "zhongyuan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Nil(t, AnalyzeRhyme(tt.paragraphs, tt.system))
+		})
+	}
+}
+
+func TestRhymeGroup(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"十一尤", "十一尤", true},
+		{"尤", "十一尤", true},
+		{" 一东 ", "一东", true},
+		{"第十二部", "第十二部", true},
+		{"十六尤", "", false},
+		{"第二十部", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := RhymeGroup(tt.value)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRhymeTables(t *testing.T) {
+	require.Len(t, pingshuiGroups, 106)
+	require.Len(t, cilinSections, 19)
+
+	names := make(map[string]bool)
+	chars := make(map[string]string)
+	for _, g := range pingshuiGroups {
+		assert.False(t, names[g.name], "duplicate group "+g.name)
+		names[g.name] = true
+		chars[g.name] = g.chars
+	}
+
+	// Every group belongs to a 部, and to two only if it is split between them
+	sections := make(map[string][]string)
+	for _, s := range cilinSections {
+		for _, g := range s.groups {
+			require.True(t, names[g], "unknown group "+g+" in "+s.name)
+			sections[g] = append(sections[g], s.name)
+		}
+	}
+	for name := range names {
+		split, ok := cilinSplits[name]
+		if !ok {
+			assert.Len(t, sections[name], 1, name)
+			continue
+		}
+		require.Len(t, sections[name], 2, name)
+		assert.ElementsMatch(t, []rune(chars[name]), []rune(split[sections[name][0]]+split[sections[name][1]]), name)
+	}
+}
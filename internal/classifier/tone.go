@@ -0,0 +1,98 @@
+package classifier
+
+import "github.com/palemoky/chinese-poetry-api/internal/pinyin"
+
+// Tone is the class of a character's tone in regulated verse
+type Tone int
+
+const (
+	// ToneUnknown is for characters without a reading
+	ToneUnknown Tone = iota
+	// TonePing is 平, the level tone: 阴平 and 阳平 in Mandarin
+	TonePing
+	// ToneZe is 仄, the oblique tones: 上, 去 and the 入声
+	ToneZe
+	// ToneEither is for characters read with a 平 or a 仄 tone depending on
+	// sense (看, 听, 思), which verse uses in either position
+	ToneEither
+)
+
+// String returns the character a tonal pattern writes the tone as
+func (t Tone) String() string {
+	switch t {
+	case TonePing:
+		return "平"
+	case ToneZe:
+		return "仄"
+	case ToneEither:
+		return "中"
+	default:
+		return "？"
+	}
+}
+
+// enteringTone lists the common 入声 characters that Mandarin reads with the
+// first or second tone. The 入声 is 仄, but it has merged into the other four
+// tones, so these would otherwise be taken for 平. Those now read with the
+// third or fourth tone are 仄 anyway. Both scripts are listed.
+var enteringTone = runeSet(
+	"一七八发發髮出吃喫黑喝说說拍切缺屋哭督秃禿桌捉粥叔菽淑激积積击擊接揭跌贴" +
+		"貼帖鸽鴿割搁擱郭插杀殺刷夹夾掐瞎鸭鴨压壓押挖刮剥剝泼潑摸托脱脫扑撲忽惚窟" +
+		"突凸屈曲戚析晰昔惜夕汐淅息熄悉膝锡錫吸失湿濕虱织織汁只隻逼滴踢剔劈霹约約" +
+		"曰拙薛削拨撥钵缽豁歇蝎掬鞠漆缉緝噎掖憋鳖鱉瞥捏拆塞摘揖咄掇磕拉塌搭缩縮白" +
+		"薄伯帛舶泊箔博搏膊驳駁勃渤钹鈸跋拔佛拂弗祓服福幅蝠辐輻伏茯达達答沓乏伐罚" +
+		"罰阀閥筏杂雜匝扎札闸閘察合盒阖闔盍涸核劾阂閡活滑猾急级級极極吉集疾籍辑輯" +
+		"即及汲笈戢棘亟嫉瘠杰傑节節洁潔结結捷睫截竭劫桀碣孑颊頰荚莢戛局菊橘掘决決" +
+		"诀訣绝絕觉覺爵嚼厥阙闕卓酌灼浊濁琢啄濯茁着著勺学學穴蝶叠疊迭牒碟谍諜喋毒" +
+		"独獨读讀渎瀆牍牘犊犢黩黷夺奪铎鐸踱德得敌敵笛狄荻涤滌嫡籴糴额額格隔革阁閣" +
+		"葛国國宅翟泽澤择擇责責则則贼賊折哲辙轍蛰蟄直值植殖侄执執职職十什石拾食蚀" +
+		"蝕实實识識孰塾熟赎贖秫俗昨凿鑿足族卒逐竹竺烛燭轴軸舌席习習袭襲媳檄峡峽狭" +
+		"狹侠俠匣辖轄狎黠协協胁脅挟挾撷擷别別鼻仆僕璞濮斛鹄鵠没膜壳殼咳",
+)
+
+// eitherTone lists the characters common in verse that have both a 平 and a
+// 仄 reading, in both scripts
+var eitherTone = runeSet(
+	"看听聽思忘望过過教论論醒漫令骑騎胜勝禁任应應为為重长長更相中行数數兴興将" +
+		"將分间間乘调調传傳当當量燕王衣冠观觀治缝縫吹担擔闻聞要叹嘆歎泥难難先号號" +
+		"称稱纵縱横橫供",
+)
+
+// runeSet returns the set of runes in s
+func runeSet(s string) map[rune]bool {
+	set := make(map[rune]bool)
+	for _, r := range s {
+		set[r] = true
+	}
+	return set
+}
+
+// CharTone returns the tone class of r. It is derived from the tone of the
+// character's most common Mandarin reading, corrected for the 入声 and for
+// characters with readings in both classes.
+func CharTone(r rune) Tone {
+	switch {
+	case enteringTone[r]:
+		return ToneZe
+	case eitherTone[r]:
+		return ToneEither
+	}
+	switch pinyin.Tone(r) {
+	case 1, 2:
+		return TonePing
+	case 3, 4:
+		return ToneZe
+	default:
+		return ToneUnknown
+	}
+}
+
+// LineTones returns the tone class of each character of line
+func LineTones(line string) []Tone {
+	runes := []rune(line)
+	tones := make([]Tone, len(runes))
+	for i, r := range runes {
+		tones[i] = CharTone(r)
+	}
+	return tones
+}
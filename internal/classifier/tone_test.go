@@ -0,0 +1,37 @@
+package classifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharTone(t *testing.T) {
+	tests := []struct {
+		name string
+		char rune
+		want Tone
+	}{
+		{"first tone", '山', TonePing},
+		{"second tone", '河', TonePing},
+		{"third tone", '海', ToneZe},
+		{"fourth tone", '月', ToneZe},
+		{"入声 read with the second tone", '白', ToneZe},
+		{"入声 read with the first tone", '一', ToneZe},
+		{"traditional 入声", '國', ToneZe},
+		{"both readings", '看', ToneEither},
+		{"punctuation", '，', ToneUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CharTone(tt.char))
+		})
+	}
+}
+
+func TestToneString(t *testing.T) {
+	assert.Equal(t, "平", TonePing.String())
+	assert.Equal(t, "仄", ToneZe.String())
+	assert.Equal(t, "中", ToneEither.String())
+	assert.Equal(t, "？", ToneUnknown.String())
+}
@@ -9,22 +9,42 @@ import (
 const (
 	// Categories
 	CategoryPoetry = "唐诗"
-	CategoryCi     = "宋词"
+	CategoryCi     = "宋词" // 词 of every period, 五代词 included
 	CategoryOther  = "其他"
 
 	// Specific types
-	TypeWuyanJueju = "五言绝句"
-	TypeQiyanJueju = "七言绝句"
-	TypeWuyanLvshi = "五言律诗"
-	TypeQiyanLvshi = "七言律诗"
-	TypeCi         = "宋词"
-	TypeOther      = "其他"
+	TypeWuyanJueju  = "五言绝句"
+	TypeQiyanJueju  = "七言绝句"
+	TypeWuyanLvshi  = "五言律诗"
+	TypeQiyanLvshi  = "七言律诗"
+	TypeLiuyanJueju = "六言绝句"
+	TypeLiuyanLvshi = "六言律诗"
+	TypeWuyanPailv  = "五言排律"
+	TypeQiyanPailv  = "七言排律"
+	TypeWuyanGushi  = "五言古诗"
+	TypeQiyanGushi  = "七言古诗"
+	TypeZayan       = "杂言"
+	TypeCi          = "宋词"
+	TypeOther       = "其他"
 
 	// Structure constraints
-	JuejuLines = 4
-	LvshiLines = 8
-	WuyanChars = 5
-	QiyanChars = 7
+	JuejuLines    = 4
+	LvshiLines    = 8
+	PailvMinLines = 10 // 排律 run on past the eight lines of 律诗, in couplets
+	ZayanMinLines = 4  // Fewer lines of mixed lengths are a fragment, not 杂言
+	ZayanMaxChars = 11 // Longer lines are prose rather than verse
+	WuyanChars    = 5
+	LiuyanChars   = 6
+	QiyanChars    = 7
+)
+
+// Classification rules, the step of ClassifyPoetryTypeWithDataset that
+// decided a poem's type
+const (
+	RuleDataset   = "dataset"   // The dataset maps to the type
+	RuleRhythmic  = "rhythmic"  // A 词牌 field marks a ci
+	RuleYuefu     = "yuefu"     // The title is a known 乐府 title
+	RuleStructure = "structure" // Line and character counts
 )
 
 // PoetryTypeInfo contains information about a classified poetry type
@@ -33,6 +53,41 @@ type PoetryTypeInfo struct {
 	Category     string
 	Lines        *int
 	CharsPerLine *int
+
+	// Rule is the Rule* constant naming the step that decided the type, and
+	// Confidence how far that step can be trusted, from 0 to 1: dataset
+	// mappings are certain, a 乐府 title found inside a longer one or a shape
+	// shared by several types less so.
+	Rule       string
+	Confidence float64
+	Evidence   ClassificationEvidence
+}
+
+// ClassificationEvidence is what the deciding rule went on
+type ClassificationEvidence struct {
+	Dataset      string `json:"dataset,omitempty"`       // Dataset key
+	Rhythmic     string `json:"rhythmic,omitempty"`      // 词牌 field
+	YuefuPattern string `json:"yuefu_pattern,omitempty"` // 乐府 title or marker found in the title
+	Lines        int    `json:"lines,omitempty"`         // Lines after splitting at punctuation
+	CharCounts   []int  `json:"char_counts,omitempty"`   // Characters of each line
+}
+
+// Confidence of each structural type: 绝句 and 律诗 are defined by their
+// shape alone, while an 排律 may be a 古诗 that happens to have an even
+// number of lines, and the remaining shapes are catch-alls
+var structureConfidence = map[string]float64{
+	TypeWuyanJueju:  0.9,
+	TypeQiyanJueju:  0.9,
+	TypeLiuyanJueju: 0.9,
+	TypeWuyanLvshi:  0.9,
+	TypeQiyanLvshi:  0.9,
+	TypeLiuyanLvshi: 0.9,
+	TypeWuyanPailv:  0.8,
+	TypeQiyanPailv:  0.8,
+	TypeWuyanGushi:  0.7,
+	TypeQiyanGushi:  0.7,
+	TypeZayan:       0.6,
+	TypeOther:       0.5,
 }
 
 // ClassifyPoetryType determines the type of poetry based on its structure
@@ -42,48 +97,60 @@ func ClassifyPoetryType(paragraphs []string, rhythmic string) PoetryTypeInfo {
 
 // ClassifyPoetryTypeWithDataset determines the type of poetry based on dataset source and structure
 // Priority order:
-// 1. Dataset-based direct mapping (for shijing, chuci, lunyu, mengzi, yuanqu)
+// 1. Dataset-based direct mapping (for shijing, chuci, lunyu, mengzi), and
+// 小令 or 套数 for yuanqu
 // 2. Rhythmic field check (for songci)
 // 2.5. Yuefu poem title check
 // 3. Structure analysis (for tangshi)
+//
+// The result records which of these rules decided the type, how confident
+// it is and the evidence it went on.
 func ClassifyPoetryTypeWithDataset(paragraphs []string, rhythmic string, datasetKey string, title string) PoetryTypeInfo {
 	// Priority 1: Check dataset key for direct type mapping
 	if typeInfo, ok := getTypeFromDataset(datasetKey); ok {
+		typeInfo.Rule, typeInfo.Confidence = RuleDataset, 1
+		typeInfo.Evidence = ClassificationEvidence{Dataset: datasetKey}
+		return typeInfo
+	}
+	if datasetKey == "yuanqu" {
+		typeInfo := classifyQu(paragraphs, title)
+		typeInfo.Rule = RuleDataset
+		typeInfo.Evidence = ClassificationEvidence{Dataset: datasetKey}
 		return typeInfo
 	}
 
 	// Priority 2: If it has a rhythmic field, it's ci (词)
 	if rhythmic != "" {
 		return PoetryTypeInfo{
-			TypeName: TypeCi,
-			Category: CategoryCi,
+			TypeName:   TypeCi,
+			Category:   CategoryCi,
+			Rule:       RuleRhythmic,
+			Confidence: 0.95,
+			Evidence:   ClassificationEvidence{Rhythmic: rhythmic},
 		}
 	}
 
 	// Priority 2.5: Check if it's a Yuefu poem by title
-	if title != "" && isYuefuPoem(title) {
+	if pattern, confidence := matchYuefu(title); pattern != "" {
 		return PoetryTypeInfo{
-			TypeName: "乐府诗",
-			Category: "唐诗",
+			TypeName:   "乐府诗",
+			Category:   "唐诗",
+			Rule:       RuleYuefu,
+			Confidence: confidence,
+			Evidence:   ClassificationEvidence{YuefuPattern: pattern},
 		}
 	}
 
 	// Priority 3: Structure-based classification for Tang poetry
-	if len(paragraphs) == 0 {
-		return PoetryTypeInfo{
-			TypeName: TypeOther,
-			Category: CategoryOther,
-		}
-	}
-
 	// Split merged lines (e.g., "江南有美人，别后长相忆。" → ["江南有美人", "别后长相忆"])
 	expandedLines := expandParagraphs(paragraphs)
 
-	// Check if expansion resulted in empty lines
+	// Check if there is anything left to measure
 	if len(expandedLines) == 0 {
 		return PoetryTypeInfo{
 			TypeName: TypeOther,
 			Category: CategoryOther,
+			Rule:     RuleStructure,
 		}
 	}
 
@@ -97,12 +164,22 @@ func ClassifyPoetryTypeWithDataset(paragraphs []string, rhythmic string, dataset
 		charCounts[i] = utf8.RuneCountInString(cleaned)
 	}
 
+	evidence := ClassificationEvidence{Lines: lineCount, CharCounts: charCounts}
+
 	// Check if all lines have the same character count
 	if !isUniform(charCounts) {
-		// Irregular structure
+		// Lines of mixed lengths
+		typeName, category := TypeOther, CategoryOther
+		if isZayan(charCounts) {
+			typeName, category = TypeZayan, CategoryPoetry
+		}
 		return PoetryTypeInfo{
-			TypeName: TypeOther,
-			Category: CategoryOther,
+			TypeName:   typeName,
+			Category:   category,
+			Lines:      &lineCount,
+			Rule:       RuleStructure,
+			Confidence: structureConfidence[typeName],
+			Evidence:   evidence,
 		}
 	}
 
@@ -116,6 +193,9 @@ func ClassifyPoetryTypeWithDataset(paragraphs []string, rhythmic string, dataset
 		Category:     category,
 		Lines:        &lineCount,
 		CharsPerLine: &charsPerLine,
+		Rule:         RuleStructure,
+		Confidence:   structureConfidence[typeName],
+		Evidence:     evidence,
 	}
 }
 
@@ -140,17 +220,13 @@ func getTypeFromDataset(datasetKey string) (PoetryTypeInfo, bool) {
 			TypeName: "四书五经",
 			Category: "四书五经",
 		},
-		"yuanqu": {
-			TypeName: "元曲",
-			Category: "曲",
-		},
 		"wudai-huajianji": {
 			TypeName: "五代词",
-			Category: "词",
+			Category: CategoryCi,
 		},
 		"wudai-nantang": {
 			TypeName: "五代词",
-			Category: "词",
+			Category: CategoryCi,
 		},
 		"nalanxingde": {
 			TypeName: "宋词", // 纳兰性德是清代，但词的形式与宋词相同
@@ -170,21 +246,57 @@ func getTypeFromDataset(datasetKey string) (PoetryTypeInfo, bool) {
 }
 
 // classifyByStructure classifies poetry based on line count and characters per line
+// Two or more lines of five or seven characters that are neither 绝句, 律诗
+// nor 排律 make a 古诗; six-character lines only count as 绝句 or 律诗.
 func classifyByStructure(lines, chars int) (typeName, category string) {
 	switch {
 	case lines == JuejuLines && chars == WuyanChars:
 		return TypeWuyanJueju, CategoryPoetry
+	case lines == JuejuLines && chars == LiuyanChars:
+		return TypeLiuyanJueju, CategoryPoetry
 	case lines == JuejuLines && chars == QiyanChars:
 		return TypeQiyanJueju, CategoryPoetry
 	case lines == LvshiLines && chars == WuyanChars:
 		return TypeWuyanLvshi, CategoryPoetry
+	case lines == LvshiLines && chars == LiuyanChars:
+		return TypeLiuyanLvshi, CategoryPoetry
 	case lines == LvshiLines && chars == QiyanChars:
 		return TypeQiyanLvshi, CategoryPoetry
+	case lines >= PailvMinLines && lines%2 == 0 && chars == WuyanChars:
+		return TypeWuyanPailv, CategoryPoetry
+	case lines >= PailvMinLines && lines%2 == 0 && chars == QiyanChars:
+		return TypeQiyanPailv, CategoryPoetry
+	case lines < 2:
+		// A single line is a fragment, not a poem
+		return TypeOther, CategoryOther
+	case chars == WuyanChars:
+		return TypeWuyanGushi, CategoryPoetry
+	case chars == QiyanChars:
+		return TypeQiyanGushi, CategoryPoetry
 	default:
 		return TypeOther, CategoryOther
 	}
 }
 
+// isZayan reports whether lines of mixed lengths read as 杂言 verse: enough
+// lines, none of them prose-long, and at least half of them of five or seven
+// characters, as the lines of 杂言 mostly are
+func isZayan(charCounts []int) bool {
+	if len(charCounts) < ZayanMinLines {
+		return false
+	}
+	regular := 0
+	for _, n := range charCounts {
+		if n > ZayanMaxChars {
+			return false
+		}
+		if n == WuyanChars || n == QiyanChars {
+			regular++
+		}
+	}
+	return 2*regular >= len(charCounts)
+}
+
 // isUniform checks if all integers in a slice are equal
 func isUniform(nums []int) bool {
 	if len(nums) == 0 {
@@ -250,11 +362,18 @@ func removePunctuation(text string) string {
 	return strings.TrimSpace(result)
 }
 
-// isYuefuPoem checks if a poem is a Yuefu poem based on its title
+// matchYuefu checks if a poem is a Yuefu poem based on its title, returning
+// the known title or marker it contains ("" if none) and how confident the
+// match is: a title that is exactly a known one is surer than one containing
+// it, and a generic marker such as 歌行 is the weakest sign.
 // Note: We maintain only simplified Chinese patterns and convert the input title
 // to simplified Chinese before matching. This avoids the need to maintain both
 // simplified and traditional variants, preventing inconsistencies.
-func isYuefuPoem(title string) bool {
+func matchYuefu(title string) (pattern string, confidence float64) {
+	if title == "" {
+		return "", 0
+	}
+
 	// Convert title to simplified Chinese for consistent matching
 	// If conversion fails, fall back to original title
 	simplifiedTitle, err := ToSimplified(title)
@@ -337,9 +456,14 @@ func isYuefuPoem(title string) bool {
 	}
 
 	// Check title matches using simplified title
+	for _, yuefuTitle := range yuefuTitles {
+		if simplifiedTitle == yuefuTitle {
+			return yuefuTitle, 0.9
+		}
+	}
 	for _, yuefuTitle := range yuefuTitles {
 		if strings.Contains(simplifiedTitle, yuefuTitle) {
-			return true
+			return yuefuTitle, 0.7
 		}
 	}
 
@@ -351,9 +475,9 @@ func isYuefuPoem(title string) bool {
 
 	for _, pattern := range yuefuPatterns {
 		if strings.Contains(simplifiedTitle, pattern) {
-			return true
+			return pattern, 0.6
 		}
 	}
 
-	return false
+	return "", 0
 }
@@ -42,12 +42,19 @@ func FuzzClassifyPoetryType(f *testing.F) {
 
 		// TypeName should be one of the known types
 		validTypes := map[string]bool{
-			TypeWuyanJueju: true,
-			TypeQiyanJueju: true,
-			TypeWuyanLvshi: true,
-			TypeQiyanLvshi: true,
-			TypeCi:         true,
-			TypeOther:      true,
+			TypeWuyanJueju:  true,
+			TypeQiyanJueju:  true,
+			TypeWuyanLvshi:  true,
+			TypeQiyanLvshi:  true,
+			TypeLiuyanJueju: true,
+			TypeLiuyanLvshi: true,
+			TypeWuyanPailv:  true,
+			TypeQiyanPailv:  true,
+			TypeWuyanGushi:  true,
+			TypeQiyanGushi:  true,
+			TypeZayan:       true,
+			TypeCi:          true,
+			TypeOther:       true,
 		}
 		if !validTypes[result.TypeName] {
 			t.Errorf("ClassifyPoetryType returned invalid TypeName: %q", result.TypeName)
@@ -1,6 +1,7 @@
 package classifier
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -146,18 +147,127 @@ func TestClassifyPoetryType(t *testing.T) {
 			},
 		},
 		{
-			name:       "irregular format - 不均匀行长",
+			name:       "杂言 - 长短句",
+			paragraphs: []string{"噫吁嚱，危乎高哉！", "蜀道之难，难于上青天！", "蚕丛及鱼凫，开国何茫然！", "尔来四万八千岁，不与秦塞通人烟。"},
+			rhythmic:   "",
+			want: PoetryTypeInfo{
+				TypeName: "杂言",
+				Category: "唐诗",
+				Lines:    intPtr(8),
+			},
+		},
+		{
+			name:       "单句 - 其他",
+			paragraphs: []string{"床前明月光"},
+			rhythmic:   "",
+			want: PoetryTypeInfo{
+				TypeName:     "其他",
+				Category:     "其他",
+				Lines:        intPtr(1),
+				CharsPerLine: intPtr(5),
+			},
+		},
+		{
+			name:       "不均匀的片段 - 其他",
 			paragraphs: []string{"短", "这是一首很长的句子超过十个字"},
 			rhythmic:   "",
 			want: PoetryTypeInfo{
 				TypeName: "其他",
 				Category: "其他",
+				Lines:    intPtr(2),
+			},
+		},
+		{
+			name:       "散文 - 其他",
+			paragraphs: []string{"余闻之也久，今者来观，见其山川之胜，不可胜数。", "于是为之记，以俟后之君子，庶几有所考焉。"},
+			rhythmic:   "",
+			want: PoetryTypeInfo{
+				TypeName: "其他",
+				Category: "其他",
+				Lines:    intPtr(7),
 			},
 		},
 		{
-			name:       "irregular format - 非标准行数",
+			name:       "五言古诗 - 非标准行数",
 			paragraphs: []string{"床前明月光", "疑是地上霜", "举头望明月"},
 			rhythmic:   "",
+			want: PoetryTypeInfo{
+				TypeName:     "五言古诗",
+				Category:     "唐诗",
+				Lines:        intPtr(3),
+				CharsPerLine: intPtr(5),
+			},
+		},
+		{
+			name:       "七言古诗 - 六句",
+			paragraphs: []string{"汉皇重色思倾国，御宇多年求不得。", "杨家有女初长成，养在深闺人未识。", "天生丽质难自弃，一朝选在君王侧。"},
+			rhythmic:   "",
+			want: PoetryTypeInfo{
+				TypeName:     "七言古诗",
+				Category:     "唐诗",
+				Lines:        intPtr(6),
+				CharsPerLine: intPtr(7),
+			},
+		},
+		{
+			name:       "六言绝句",
+			paragraphs: []string{"桃红复含宿雨，柳绿更带朝烟。", "花落家童未扫，莺啼山客犹眠。"},
+			rhythmic:   "",
+			want: PoetryTypeInfo{
+				TypeName:     "六言绝句",
+				Category:     "唐诗",
+				Lines:        intPtr(4),
+				CharsPerLine: intPtr(6),
+			},
+		},
+		{
+			name:       "六言律诗",
+			paragraphs: []string{"一一二二三三，四四五五六六。", "一一二二三三，四四五五六六。", "一一二二三三，四四五五六六。", "一一二二三三，四四五五六六。"},
+			rhythmic:   "",
+			want: PoetryTypeInfo{
+				TypeName:     "六言律诗",
+				Category:     "唐诗",
+				Lines:        intPtr(8),
+				CharsPerLine: intPtr(6),
+			},
+		},
+		{
+			name:       "五言排律 - 十二句",
+			paragraphs: []string{"一二三四五，一二三四五。", "一二三四五，一二三四五。", "一二三四五，一二三四五。", "一二三四五，一二三四五。", "一二三四五，一二三四五。", "一二三四五，一二三四五。"},
+			rhythmic:   "",
+			want: PoetryTypeInfo{
+				TypeName:     "五言排律",
+				Category:     "唐诗",
+				Lines:        intPtr(12),
+				CharsPerLine: intPtr(5),
+			},
+		},
+		{
+			name:       "七言排律 - 十句",
+			paragraphs: []string{"一二三四五六七，一二三四五六七。", "一二三四五六七，一二三四五六七。", "一二三四五六七，一二三四五六七。", "一二三四五六七，一二三四五六七。", "一二三四五六七，一二三四五六七。"},
+			rhythmic:   "",
+			want: PoetryTypeInfo{
+				TypeName:     "七言排律",
+				Category:     "唐诗",
+				Lines:        intPtr(10),
+				CharsPerLine: intPtr(7),
+			},
+		},
+		{
+			name:       "五言古诗 - 十一句不成排律",
+			paragraphs: []string{"一二三四五，一二三四五。", "一二三四五，一二三四五。", "一二三四五，一二三四五。", "一二三四五，一二三四五。", "一二三四五，一二三四五。", "一二三四五。"},
+			rhythmic:   "",
+			want: PoetryTypeInfo{
+				TypeName:     "五言古诗",
+				Category:     "唐诗",
+				Lines:        intPtr(11),
+				CharsPerLine: intPtr(5),
+			},
+		},
+		{
+			name:       "四言 - 其他",
+			paragraphs: []string{"关关雎鸠，在河之洲。", "窈窕淑女，君子好逑。"},
+			rhythmic:   "",
 			want: PoetryTypeInfo{
 				TypeName: "其他",
 				Category: "其他",
@@ -402,6 +512,48 @@ func TestNormalizeAndSplitParagraphs(t *testing.T) {
 	}
 }
 
+func TestSplitStanzas(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  [][]string
+	}{
+		{
+			name:  "ci halves",
+			input: []string{"明月几时有？把酒问青天。", "转朱阁，低绮户，照无眠。不应有恨，何事长向别时圆？"},
+			want:  [][]string{{"明月几时有？", "把酒问青天。"}, {"转朱阁，低绮户，照无眠。", "不应有恨，何事长向别时圆？"}},
+		},
+		{
+			name:  "paragraph per couplet",
+			input: []string{"床前明月光，疑是地上霜。", "举头望明月，低头思故乡。"},
+			want:  [][]string{{"床前明月光，疑是地上霜。", "举头望明月，低头思故乡。"}},
+		},
+		{
+			name:  "one merged paragraph",
+			input: []string{"银鞍白鼻驹，绿地障泥锦。细雨春风花落时，挥鞭直就胡姬饮。", "已是独醒客。"},
+			want:  [][]string{{"银鞍白鼻驹，绿地障泥锦。", "细雨春风花落时，挥鞭直就胡姬饮。", "已是独醒客。"}},
+		},
+		{
+			name:  "empty paragraphs skipped",
+			input: []string{"关关雎鸠，在河之洲。窈窕淑女，君子好逑。", "  ", "参差荇菜，左右流之。窈窕淑女，寤寐求之。"},
+			want:  [][]string{{"关关雎鸠，在河之洲。", "窈窕淑女，君子好逑。"}, {"参差荇菜，左右流之。", "窈窕淑女，寤寐求之。"}},
+		},
+		{
+			name:  "empty input",
+			input: []string{},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitStanzas(tt.input)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, NormalizeAndSplitParagraphs(tt.input), slices.Concat(got...))
+		})
+	}
+}
+
 func TestRemovePunctuation(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -467,6 +619,16 @@ func TestClassifyPoetryTypeWithDataset(t *testing.T) {
 				Category: "楚辞",
 			},
 		},
+		{
+			name:       "五代词 - dataset mapping, filed with the 宋词",
+			paragraphs: []string{"小山重叠金明灭，鬓云欲度香腮雪。"},
+			rhythmic:   "菩萨蛮",
+			datasetKey: "wudai-huajianji",
+			want: PoetryTypeInfo{
+				TypeName: "五代词",
+				Category: CategoryCi,
+			},
+		},
 		{
 			name:       "论语 - dataset mapping",
 			paragraphs: []string{"学而时习之，不亦说乎？"},
@@ -488,12 +650,22 @@ func TestClassifyPoetryTypeWithDataset(t *testing.T) {
 			},
 		},
 		{
-			name:       "元曲 - dataset mapping",
+			name:       "小令 - yuanqu dataset",
 			paragraphs: []string{"枯藤老树昏鸦，小桥流水人家。"},
 			rhythmic:   "",
 			datasetKey: "yuanqu",
 			want: PoetryTypeInfo{
-				TypeName: "元曲",
+				TypeName: "小令",
+				Category: "曲",
+			},
+		},
+		{
+			name:       "套数 - yuanqu dataset",
+			paragraphs: []string{"攀出墙朵朵花，折临路枝枝柳。", "【梁州】我是个普天下郎君领袖，盖世界浪子班头。", "【尾】我是个蒸不烂煮不熟捶不匾炒不爆响珰珰一粒铜豌豆。"},
+			rhythmic:   "",
+			datasetKey: "yuanqu",
+			want: PoetryTypeInfo{
+				TypeName: "套数",
 				Category: "曲",
 			},
 		},
@@ -564,3 +736,95 @@ func TestClassifyPoetryTypeWithDataset(t *testing.T) {
 		})
 	}
 }
+
+func TestClassificationExplanation(t *testing.T) {
+	tests := []struct {
+		name           string
+		paragraphs     []string
+		rhythmic       string
+		datasetKey     string
+		title          string
+		wantRule       string
+		wantConfidence float64
+		wantEvidence   ClassificationEvidence
+	}{
+		{
+			name:           "dataset mapping",
+			paragraphs:     []string{"关关雎鸠，在河之洲。"},
+			datasetKey:     "shijing",
+			wantRule:       RuleDataset,
+			wantConfidence: 1,
+			wantEvidence:   ClassificationEvidence{Dataset: "shijing"},
+		},
+		{
+			name:           "yuanqu 小令",
+			paragraphs:     []string{"枯藤老树昏鸦，小桥流水人家。"},
+			datasetKey:     "yuanqu",
+			wantRule:       RuleDataset,
+			wantConfidence: 0.85,
+			wantEvidence:   ClassificationEvidence{Dataset: "yuanqu"},
+		},
+		{
+			name:           "rhythmic field",
+			paragraphs:     []string{"明月几时有", "把酒问青天"},
+			rhythmic:       "水调歌头",
+			wantRule:       RuleRhythmic,
+			wantConfidence: 0.95,
+			wantEvidence:   ClassificationEvidence{Rhythmic: "水调歌头"},
+		},
+		{
+			name:           "yuefu title",
+			paragraphs:     []string{"君不见黄河之水天上来"},
+			title:          "将进酒",
+			wantRule:       RuleYuefu,
+			wantConfidence: 0.9,
+			wantEvidence:   ClassificationEvidence{YuefuPattern: "将进酒"},
+		},
+		{
+			name:           "yuefu title inside a longer one",
+			paragraphs:     []string{"月黑雁飞高，单于夜遁逃。"},
+			title:          "和张仆射塞下曲",
+			wantRule:       RuleYuefu,
+			wantConfidence: 0.7,
+			wantEvidence:   ClassificationEvidence{YuefuPattern: "塞下曲"},
+		},
+		{
+			name:           "yuefu marker",
+			paragraphs:     []string{"一二三四五"},
+			title:          "夜宴歌行",
+			wantRule:       RuleYuefu,
+			wantConfidence: 0.6,
+			wantEvidence:   ClassificationEvidence{YuefuPattern: "歌行"},
+		},
+		{
+			name:           "structure",
+			paragraphs:     []string{"春眠不觉晓，处处闻啼鸟。", "夜来风雨声，花落知多少。"},
+			title:          "春晓",
+			wantRule:       RuleStructure,
+			wantConfidence: 0.9,
+			wantEvidence:   ClassificationEvidence{Lines: 4, CharCounts: []int{5, 5, 5, 5}},
+		},
+		{
+			name:           "mixed line lengths",
+			paragraphs:     []string{"噫吁嚱，危乎高哉！", "蜀道之难，难于上青天！", "蚕丛及鱼凫，开国何茫然！"},
+			wantRule:       RuleStructure,
+			wantConfidence: 0.6,
+			wantEvidence:   ClassificationEvidence{Lines: 6, CharCounts: []int{3, 4, 4, 5, 5, 5}},
+		},
+		{
+			name:       "nothing to measure",
+			paragraphs: []string{},
+			wantRule:   RuleStructure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyPoetryTypeWithDataset(tt.paragraphs, tt.rhythmic, tt.datasetKey, tt.title)
+
+			assert.Equal(t, tt.wantRule, got.Rule)
+			assert.Equal(t, tt.wantConfidence, got.Confidence)
+			assert.Equal(t, tt.wantEvidence, got.Evidence)
+		})
+	}
+}
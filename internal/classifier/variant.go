@@ -0,0 +1,35 @@
+package classifier
+
+import "strings"
+
+// variantForms maps variant characters (异体字) found in the source texts to
+// the form they are folded into. Each form is the key of no entry, so folding
+// twice changes nothing. The pairs are interchangeable in classical texts,
+// and are not told apart by script conversion: 爲 and 為 are both traditional,
+// 着 and 著 both simplified. Either script's text can be folded, so the forms
+// cover both.
+var variantForms = map[rune]rune{
+	'爲': '為', '衆': '眾', '着': '著', '峯': '峰', '羣': '群',
+	'裏': '裡', '綫': '線', '鷄': '雞', '鶏': '雞', '牀': '床',
+	'迹': '跡', '蹟': '跡', '谿': '溪', '咏': '詠', '歎': '嘆',
+	'脣': '唇', '絃': '弦', '鬪': '鬥', '鬭': '鬥', '隣': '鄰',
+	'盃': '杯', '涙': '淚', '眞': '真', '靑': '青', '淸': '清',
+	'卽': '即', '旣': '既', '敎': '教', '吴': '吳', '呉': '吳',
+	'黄': '黃', '説': '說', '兑': '兌', '廻': '迴', '痴': '癡',
+	'烟': '煙', '啓': '啟', '册': '冊', '凉': '涼', '况': '況',
+	'凈': '淨', '峩': '峨', '綉': '繡', '査': '查', '絶': '絕',
+	'緑': '綠', '戸': '戶', '恒': '恆', '飮': '飲', '衞': '衛',
+}
+
+// FoldVariants replaces the variant characters in text with one form each,
+// so that texts differing only in variants compare equal, e.g. 何爲 and 何為.
+// It maps character for character, so offsets into the result hold for text.
+// The folded text is for matching and hashing, never for display.
+func FoldVariants(text string) string {
+	return strings.Map(func(r rune) rune {
+		if form, ok := variantForms[r]; ok {
+			return form
+		}
+		return r
+	}, text)
+}
@@ -0,0 +1,35 @@
+package classifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFoldVariants(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"traditional variants", "何爲衆人", "何為眾人"},
+		{"simplified variants", "看着", "看著"},
+		{"already folded", "何為眾人", "何為眾人"},
+		{"no variants", "床前明月光", "床前明月光"},
+		{"punctuation kept", "靑山，淸水。", "青山，清水。"},
+		{"empty string", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FoldVariants(tt.input))
+		})
+	}
+}
+
+func TestVariantFormsAreFolded(t *testing.T) {
+	for variant, form := range variantForms {
+		_, isVariant := variantForms[form]
+		assert.False(t, isVariant, string(variant)+" folds into "+string(form)+", which is folded again")
+	}
+}
@@ -17,6 +17,9 @@ type CachedRepository struct {
 
 	authorCache   map[string]int64
 	authorCacheMu sync.RWMutex
+
+	ciPaiCache   map[string]int64
+	ciPaiCacheMu sync.RWMutex
 }
 
 // NewCachedRepository creates a new cached repository
@@ -26,6 +29,7 @@ func NewCachedRepository(repo *Repository) *CachedRepository {
 		dynastyCache: make(map[string]int64),
 		typeCache:    make(map[string]int64),
 		authorCache:  make(map[string]int64),
+		ciPaiCache:   make(map[string]int64),
 	}
 }
 
@@ -147,6 +151,31 @@ func (r *CachedRepository) GetOrCreateAuthor(name string, dynastyID int64) (int6
 	return id, nil
 }
 
+// GetOrCreateCiPai gets or creates a 词牌 with caching
+// Aliases are cached under their own name, pointing to the tune they resolve to
+func (r *CachedRepository) GetOrCreateCiPai(name string) (int64, error) {
+	// Try to get from cache first
+	r.ciPaiCacheMu.RLock()
+	if id, ok := r.ciPaiCache[name]; ok {
+		r.ciPaiCacheMu.RUnlock()
+		return id, nil
+	}
+	r.ciPaiCacheMu.RUnlock()
+
+	// Not in cache, get from database
+	id, err := r.Repository.GetOrCreateCiPai(name)
+	if err != nil {
+		return 0, err
+	}
+
+	// Store in cache
+	r.ciPaiCacheMu.Lock()
+	r.ciPaiCache[name] = id
+	r.ciPaiCacheMu.Unlock()
+
+	return id, nil
+}
+
 // ClearCache clears all caches
 func (r *CachedRepository) ClearCache() {
 	r.dynastyCacheMu.Lock()
@@ -160,6 +189,10 @@ func (r *CachedRepository) ClearCache() {
 	r.authorCacheMu.Lock()
 	r.authorCache = make(map[string]int64)
 	r.authorCacheMu.Unlock()
+
+	r.ciPaiCacheMu.Lock()
+	r.ciPaiCache = make(map[string]int64)
+	r.ciPaiCacheMu.Unlock()
 }
 
 // GetCacheStats returns statistics about cache usage
@@ -176,9 +209,14 @@ func (r *CachedRepository) GetCacheStats() map[string]int {
 	authorCount := len(r.authorCache)
 	r.authorCacheMu.RUnlock()
 
+	r.ciPaiCacheMu.RLock()
+	ciPaiCount := len(r.ciPaiCache)
+	r.ciPaiCacheMu.RUnlock()
+
 	return map[string]int{
 		"dynasties": dynastyCount,
 		"types":     typeCount,
 		"authors":   authorCount,
+		"ci_pai":    ciPaiCount,
 	}
 }
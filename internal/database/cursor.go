@@ -0,0 +1,105 @@
+package database
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the sort key of a row in a keyset-paginated listing.
+// Poems are keyed by ID alone; authors by poem count (descending), then ID.
+type Cursor struct {
+	ID        int64
+	PoemCount int
+}
+
+// Page selects a window of an ordered listing. With After or Before set, the
+// window is found by seeking past that row's key and Offset is ignored;
+// otherwise it starts Offset rows into the listing.
+type Page struct {
+	Limit  int
+	Offset int
+	After  *Cursor
+	Before *Cursor
+	Desc   bool // Poems only: list by descending rather than ascending ID
+}
+
+// Adjacent reports whether a listing has rows next to a page of it: Before
+// its first row and After its last, in listing order
+type Adjacent struct {
+	Before bool
+	After  bool
+}
+
+// Cursor kinds, so a cursor from one listing is rejected by another
+const (
+	poemCursorKind   = "poem"
+	authorCursorKind = "author"
+)
+
+// EncodePoemCursor returns the opaque cursor of the poem with the given ID
+func EncodePoemCursor(id int64) string {
+	return encodeCursor(poemCursorKind, id)
+}
+
+// DecodePoemCursor parses a cursor produced by EncodePoemCursor
+func DecodePoemCursor(s string) (*Cursor, error) {
+	keys, err := decodeCursor(poemCursorKind, s, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &Cursor{ID: keys[0]}, nil
+}
+
+// EncodeAuthorCursor returns the opaque cursor of an author in a listing
+// ordered by poem count
+func EncodeAuthorCursor(author AuthorWithStats) string {
+	return encodeCursor(authorCursorKind, int64(author.PoemCount), author.ID)
+}
+
+// DecodeAuthorCursor parses a cursor produced by EncodeAuthorCursor
+func DecodeAuthorCursor(s string) (*Cursor, error) {
+	keys, err := decodeCursor(authorCursorKind, s, 2)
+	if err != nil {
+		return nil, err
+	}
+	return &Cursor{PoemCount: int(keys[0]), ID: keys[1]}, nil
+}
+
+// encodeCursor joins kind and keys as "kind:k1:k2" and base64url-encodes the
+// result, so clients treat cursors as opaque tokens.
+func encodeCursor(kind string, keys ...int64) string {
+	parts := make([]string, 0, len(keys)+1)
+	parts = append(parts, kind)
+	for _, key := range keys {
+		parts = append(parts, strconv.FormatInt(key, 10))
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(parts, ":")))
+}
+
+// decodeCursor reverses encodeCursor, checking the kind and the number of keys
+func decodeCursor(kind, s string, numKeys int) ([]int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != numKeys+1 || parts[0] != kind {
+		return nil, ErrInvalidCursor
+	}
+
+	keys := make([]int64, numKeys)
+	for i, part := range parts[1:] {
+		key, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || key < 0 {
+			return nil, ErrInvalidCursor
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
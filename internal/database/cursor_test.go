@@ -0,0 +1,56 @@
+package database
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoemCursorRoundTrip(t *testing.T) {
+	for _, id := range []int64{0, 1, 42, 9_000_000_000} {
+		cursor, err := DecodePoemCursor(EncodePoemCursor(id))
+		require.NoError(t, err)
+		assert.Equal(t, id, cursor.ID)
+	}
+}
+
+func TestAuthorCursorRoundTrip(t *testing.T) {
+	author := AuthorWithStats{Author: Author{ID: 7}, PoemCount: 1024}
+
+	cursor, err := DecodeAuthorCursor(EncodeAuthorCursor(author))
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), cursor.ID)
+	assert.Equal(t, 1024, cursor.PoemCount)
+}
+
+func TestDecodeCursorRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+		decode func(string) (*Cursor, error)
+	}{
+		{"empty", "", DecodePoemCursor},
+		{"not base64", "!!!", DecodePoemCursor},
+		{"raw offset", "20", DecodePoemCursor},
+		{"author cursor as poem cursor", EncodeAuthorCursor(AuthorWithStats{Author: Author{ID: 1}}), DecodePoemCursor},
+		{"poem cursor as author cursor", EncodePoemCursor(1), DecodeAuthorCursor},
+		{"non-numeric key", encodeCursorString("poem:abc"), DecodePoemCursor},
+		{"negative key", encodeCursorString("poem:-1"), DecodePoemCursor},
+		{"extra key", encodeCursorString("poem:1:2"), DecodePoemCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := tt.decode(tt.cursor)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+			assert.Nil(t, cursor)
+		})
+	}
+}
+
+// encodeCursorString encodes an arbitrary payload the way encodeCursor does
+func encodeCursorString(payload string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(payload))
+}
@@ -1,5 +1,12 @@
 package database
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/palemoky/chinese-poetry-api/internal/classifier"
+)
+
 // Lang represents the language variant for Chinese text
 type Lang string
 
@@ -8,11 +15,21 @@ const (
 	LangHans Lang = "zh-Hans"
 	// LangHant represents Traditional Chinese (zh-Hant)
 	LangHant Lang = "zh-Hant"
+	// LangTW represents Traditional Chinese as standardized in Taiwan (zh-TW)
+	LangTW Lang = "zh-TW"
+	// LangHK represents Traditional Chinese as standardized in Hong Kong (zh-HK)
+	LangHK Lang = "zh-HK"
+	// LangJa represents the text in Japanese Shinjitai glyphs (ja)
+	LangJa Lang = "ja"
 )
 
 // IsValid checks if the language variant is valid
 func (l Lang) IsValid() bool {
-	return l == LangHans || l == LangHant
+	switch l {
+	case LangHans, LangHant, LangTW, LangHK, LangJa:
+		return true
+	}
+	return false
 }
 
 // Default returns the default language (simplified Chinese)
@@ -23,21 +40,74 @@ func (l Lang) Default() Lang {
 	return LangHans
 }
 
-// ParseLang parses a string to Lang, defaulting to simplified Chinese
-func ParseLang(s string) Lang {
-	switch s {
-	case "zh-Hant", "zh_Hant", "hant", "tc", "traditional":
+// Base returns the variant whose tables hold the text of l. Only simplified
+// and traditional Chinese are stored; the regional variants are rendered from
+// the script their OpenCC profile converts from: zh-TW and zh-HK from
+// simplified (s2tw, s2hk), Shinjitai from traditional (see
+// classifier.ToShinjitai).
+func (l Lang) Base() Lang {
+	switch l {
+	case LangHant, LangJa:
 		return LangHant
 	default:
 		return LangHans
 	}
 }
 
+// IsRegional reports whether l is rendered from another variant's text
+func (l Lang) IsRegional() bool {
+	return l.IsValid() && l != l.Base()
+}
+
+// Convert renders text, read from the tables of l's base variant, in l's
+// glyph standard. Text in a stored variant is returned as is.
+func (l Lang) Convert(text string) (string, error) {
+	switch l {
+	case LangTW:
+		return classifier.ToTaiwan(text)
+	case LangHK:
+		return classifier.ToHongKong(text)
+	case LangJa:
+		return classifier.ToShinjitai(text)
+	default:
+		return text, nil
+	}
+}
+
+// ToBase converts text written in any script to the script of l's base
+// variant, so that a name typed as the variant renders it, e.g. 蘇軾 for
+// zh-TW, matches the name stored in the base tables (苏轼).
+func (l Lang) ToBase(text string) (string, error) {
+	if l.Base() == LangHant {
+		return classifier.ToTraditional(text)
+	}
+	return classifier.ToSimplified(text)
+}
+
+// langNames maps the accepted spellings of each variant, lower-cased with
+// "-" for "_" so that BCP 47 tags and the GraphQL enum values both match
+var langNames = map[string]Lang{
+	"zh-hans": LangHans, "hans": LangHans, "sc": LangHans, "simplified": LangHans,
+	"zh-hant": LangHant, "hant": LangHant, "tc": LangHant, "traditional": LangHant,
+	"zh-tw": LangTW, "tw": LangTW,
+	"zh-hk": LangHK, "hk": LangHK,
+	"ja": LangJa, "ja-jp": LangJa, "jp": LangJa, "shinjitai": LangJa,
+}
+
+// ParseLang parses a string to Lang. Unknown values are an error rather than
+// simplified Chinese, so a typo doesn't go unnoticed.
+func ParseLang(s string) (Lang, error) {
+	if lang, ok := langNames[strings.ReplaceAll(strings.ToLower(s), "_", "-")]; ok {
+		return lang, nil
+	}
+	return "", fmt.Errorf("unknown lang %q, expected zh-Hans, zh-Hant, zh-TW, zh-HK or ja", s)
+}
+
 // Table name helpers - these help construct table names with language suffix
 
 // PoemsTable returns the poems table name for the given language
 func PoemsTable(lang Lang) string {
-	if lang == LangHant {
+	if lang.Base() == LangHant {
 		return "poems_zh_hant"
 	}
 	return "poems_zh_hans"
@@ -45,7 +115,7 @@ func PoemsTable(lang Lang) string {
 
 // AuthorsTable returns the authors table name for the given language
 func AuthorsTable(lang Lang) string {
-	if lang == LangHant {
+	if lang.Base() == LangHant {
 		return "authors_zh_hant"
 	}
 	return "authors_zh_hans"
@@ -53,7 +123,7 @@ func AuthorsTable(lang Lang) string {
 
 // DynastiesTable returns the dynasties table name for the given language
 func DynastiesTable(lang Lang) string {
-	if lang == LangHant {
+	if lang.Base() == LangHant {
 		return "dynasties_zh_hant"
 	}
 	return "dynasties_zh_hans"
@@ -61,24 +131,43 @@ func DynastiesTable(lang Lang) string {
 
 // PoetryTypesTable returns the poetry_types table name for the given language
 func PoetryTypesTable(lang Lang) string {
-	if lang == LangHant {
+	if lang.Base() == LangHant {
 		return "poetry_types_zh_hant"
 	}
 	return "poetry_types_zh_hans"
 }
 
+// CiPaiTable returns the ci_pai table name for the given language
+func CiPaiTable(lang Lang) string {
+	if lang.Base() == LangHant {
+		return "ci_pai_zh_hant"
+	}
+	return "ci_pai_zh_hans"
+}
+
 // PoemsFtsTable returns the FTS5 virtual table name backing full-text search
 // for the given language's poems table
 func PoemsFtsTable(lang Lang) string {
-	if lang == LangHant {
+	if lang.Base() == LangHant {
 		return "poems_fts_zh_hant"
 	}
 	return "poems_fts_zh_hans"
 }
 
+// PoemDuplicatesTable returns the table name of the source records skipped as
+// exact duplicates of a stored poem, for the given language
+func PoemDuplicatesTable(lang Lang) string {
+	if lang.Base() == LangHant {
+		return "poem_duplicates_zh_hant"
+	}
+	return "poem_duplicates_zh_hans"
+}
+
 // Internal lowercase versions for use within this package
-func poemsTable(lang Lang) string       { return PoemsTable(lang) }
-func authorsTable(lang Lang) string     { return AuthorsTable(lang) }
-func dynastiesTable(lang Lang) string   { return DynastiesTable(lang) }
-func poetryTypesTable(lang Lang) string { return PoetryTypesTable(lang) }
-func poemsFtsTable(lang Lang) string    { return PoemsFtsTable(lang) }
+func poemsTable(lang Lang) string          { return PoemsTable(lang) }
+func authorsTable(lang Lang) string        { return AuthorsTable(lang) }
+func dynastiesTable(lang Lang) string      { return DynastiesTable(lang) }
+func poetryTypesTable(lang Lang) string    { return PoetryTypesTable(lang) }
+func ciPaiTable(lang Lang) string          { return CiPaiTable(lang) }
+func poemsFtsTable(lang Lang) string       { return PoemsFtsTable(lang) }
+func poemDuplicatesTable(lang Lang) string { return PoemDuplicatesTable(lang) }
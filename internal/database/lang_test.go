@@ -0,0 +1,85 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLang(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Lang
+		wantErr bool
+	}{
+		{"zh-Hans", LangHans, false},
+		{"zh-Hant", LangHant, false},
+		{"traditional", LangHant, false},
+		{"zh-TW", LangTW, false},
+		{"zh-HK", LangHK, false},
+		{"ja", LangJa, false},
+		{"ZH_HK", LangHK, false},
+		{"en", "", true},
+		{"zh-hant", LangHant, false},
+		{"zh-Hanz", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLang(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLangBase(t *testing.T) {
+	tests := []struct {
+		lang       Lang
+		base       Lang
+		regional   bool
+		poemsTable string
+	}{
+		{LangHans, LangHans, false, "poems_zh_hans"},
+		{LangHant, LangHant, false, "poems_zh_hant"},
+		{LangTW, LangHans, true, "poems_zh_hans"},
+		{LangHK, LangHans, true, "poems_zh_hans"},
+		{LangJa, LangHant, true, "poems_zh_hant"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.lang), func(t *testing.T) {
+			assert.Equal(t, tt.base, tt.lang.Base())
+			assert.Equal(t, tt.regional, tt.lang.IsRegional())
+			assert.Equal(t, tt.poemsTable, PoemsTable(tt.lang))
+		})
+	}
+}
+
+func TestLangConvert(t *testing.T) {
+	tests := []struct {
+		lang  Lang
+		input string
+		want  string
+	}{
+		{LangHans, "举头望明月", "举头望明月"},
+		{LangHant, "舉頭望明月", "舉頭望明月"},
+		{LangTW, "举头望明月", "舉頭望明月"},
+		{LangHK, "说", "説"},
+		{LangJa, "舉頭望明月", "挙頭望明月"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.lang), func(t *testing.T) {
+			got, err := tt.lang.Convert(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -119,6 +119,7 @@ func (db *DB) migrateTablesForLang(lang Lang) error {
 	dynastyTable := dynastiesTable(lang)
 	authorTable := authorsTable(lang)
 	poetryTypeTable := poetryTypesTable(lang)
+	ciPaiTableName := ciPaiTable(lang)
 	poemTable := poemsTable(lang)
 
 	// Create dynasties table
@@ -140,6 +141,7 @@ func (db *DB) migrateTablesForLang(lang Lang) error {
 		name TEXT NOT NULL UNIQUE,
 		dynasty_id INTEGER,
 		description TEXT,
+		poem_count INTEGER NOT NULL DEFAULT 0,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (dynasty_id) REFERENCES %s(id)
 	)`, authorTable, dynastyTable)
@@ -148,6 +150,9 @@ func (db *DB) migrateTablesForLang(lang Lang) error {
 	}
 	// Create index on dynasty_id
 	db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_dynasty ON %s(dynasty_id)", authorTable, authorTable))
+	// Composite indexes for keyset pagination by poem count, with and without a dynasty
+	db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_poem_count ON %s(poem_count DESC, id)", authorTable, authorTable))
+	db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_dynasty_poem_count ON %s(dynasty_id, poem_count DESC, id)", authorTable, authorTable))
 
 	// Create poetry_types table
 	poetryTypeSQL := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
@@ -163,37 +168,123 @@ func (db *DB) migrateTablesForLang(lang Lang) error {
 		return fmt.Errorf("failed to create %s: %w", poetryTypeTable, err)
 	}
 
+	// Create ci_pai table
+	ciPaiSQL := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		aliases TEXT,
+		chars INTEGER,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`, ciPaiTableName)
+	if err := db.Exec(ciPaiSQL).Error; err != nil {
+		return fmt.Errorf("failed to create %s: %w", ciPaiTableName, err)
+	}
+
 	// Create poems table
 	poemSQL := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
 		id INTEGER PRIMARY KEY,
 		type_id INTEGER,
+		type_rule TEXT,
+		type_confidence REAL,
+		type_evidence TEXT,
+		ci_pai_id INTEGER,
+		gong_diao TEXT,
+		qu_pai TEXT,
 		title TEXT NOT NULL,
 		content TEXT NOT NULL,
+		stanza_sizes TEXT,
 		content_hash TEXT,
+		cluster_id INTEGER,
+		source_key TEXT,
+		source_hash TEXT,
+		dataset_key TEXT,
+		dataset_name TEXT,
+		source_path TEXT,
+		source_id TEXT,
+		source_index INTEGER,
+		title_pinyin TEXT,
+		content_pinyin TEXT,
+		title_folded TEXT,
+		content_folded TEXT,
+		meter_status TEXT,
+		meter_detail TEXT,
+		rhyme_detail TEXT,
 		author_id INTEGER,
 		dynasty_id INTEGER,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (type_id) REFERENCES %s(id),
+		FOREIGN KEY (ci_pai_id) REFERENCES %s(id),
 		FOREIGN KEY (author_id) REFERENCES %s(id),
 		FOREIGN KEY (dynasty_id) REFERENCES %s(id)
-	)`, poemTable, poetryTypeTable, authorTable, dynastyTable)
+	)`, poemTable, poetryTypeTable, ciPaiTableName, authorTable, dynastyTable)
 	if err := db.Exec(poemSQL).Error; err != nil {
 		return fmt.Errorf("failed to create %s: %w", poemTable, err)
 	}
 
 	// Create indexes for poems
 	db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_type ON %s(type_id)", poemTable, poemTable))
+	db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_ci_pai ON %s(ci_pai_id)", poemTable, poemTable))
+	db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_qu_pai ON %s(gong_diao, qu_pai)", poemTable, poemTable))
 	db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_title ON %s(title)", poemTable, poemTable))
 	db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_author ON %s(author_id)", poemTable, poemTable))
 	db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_dynasty ON %s(dynasty_id)", poemTable, poemTable))
+	db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_meter ON %s(meter_status)", poemTable, poemTable))
+	db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_cluster ON %s(cluster_id)", poemTable, poemTable))
+	db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_source ON %s(source_key)", poemTable, poemTable))
+	db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_dataset ON %s(dataset_key)", poemTable, poemTable))
 	db.Exec(fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_unique ON %s(title, content_hash)", poemTable, poemTable))
 	// Composite index for efficient multi-type random selection (type_id IN ... with id range lookups)
 	db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_type_id ON %s(type_id, id)", poemTable, poemTable))
 
+	// Create poem_duplicates table: source records a build skipped as exact
+	// duplicates of a stored poem, so that updates don't take them for new
+	duplicatesTable := poemDuplicatesTable(lang)
+	duplicatesSQL := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		source_key TEXT PRIMARY KEY,
+		source_hash TEXT NOT NULL,
+		duplicate_of INTEGER NOT NULL
+	)`, duplicatesTable)
+	if err := db.Exec(duplicatesSQL).Error; err != nil {
+		return fmt.Errorf("failed to create %s: %w", duplicatesTable, err)
+	}
+
 	if err := db.migrateFtsForLang(lang); err != nil {
 		return err
 	}
 
+	if err := db.migrateAuthorCountsForLang(lang); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// migrateAuthorCountsForLang creates the triggers that keep authors.poem_count
+// equal to the number of poems by each author, so that authors can be listed
+// and paged by poem count without counting every author's poems per request.
+func (db *DB) migrateAuthorCountsForLang(lang Lang) error {
+	poemTable := poemsTable(lang)
+	authorTable := authorsTable(lang)
+
+	triggers := []struct{ name, sql string }{
+		{"insert", fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[1]s_author_count_ai AFTER INSERT ON %[1]s BEGIN
+			UPDATE %[2]s SET poem_count = poem_count + 1 WHERE id = new.author_id;
+		END`, poemTable, authorTable)},
+		{"delete", fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[1]s_author_count_ad AFTER DELETE ON %[1]s BEGIN
+			UPDATE %[2]s SET poem_count = poem_count - 1 WHERE id = old.author_id;
+		END`, poemTable, authorTable)},
+		{"update", fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[1]s_author_count_au AFTER UPDATE OF author_id ON %[1]s
+		WHEN old.author_id IS NOT new.author_id BEGIN
+			UPDATE %[2]s SET poem_count = poem_count - 1 WHERE id = old.author_id;
+			UPDATE %[2]s SET poem_count = poem_count + 1 WHERE id = new.author_id;
+		END`, poemTable, authorTable)},
+	}
+	for _, trigger := range triggers {
+		if err := db.Exec(trigger.sql).Error; err != nil {
+			return fmt.Errorf("failed to create author count %s trigger for %s: %w", trigger.name, poemTable, err)
+		}
+	}
+
 	return nil
 }
 
@@ -232,6 +323,10 @@ func (db *DB) migrateFtsForLang(lang Lang) error {
 	ftsSQL := fmt.Sprintf(`CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(
 		title,
 		content_text,
+		title_pinyin,
+		content_pinyin,
+		title_folded,
+		content_folded,
 		tokenize='trigram'
 	)`, ftsTable)
 	if err := db.Exec(ftsSQL).Error; err != nil {
@@ -247,8 +342,10 @@ func (db *DB) migrateFtsForLang(lang Lang) error {
 	const contentTextExpr = `(SELECT COALESCE(group_concat(value, ''), '') FROM json_each(%s.content))`
 
 	insertTrigger := fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[2]s_fts_ai AFTER INSERT ON %[2]s BEGIN
-		INSERT INTO %[1]s(rowid, title, content_text)
-		VALUES (new.id, new.title, `+fmt.Sprintf(contentTextExpr, "new")+`);
+		INSERT INTO %[1]s(rowid, title, content_text, title_pinyin, content_pinyin, title_folded, content_folded)
+		VALUES (new.id, new.title, `+fmt.Sprintf(contentTextExpr, "new")+`,
+			COALESCE(new.title_pinyin, ''), COALESCE(new.content_pinyin, ''),
+			COALESCE(new.title_folded, ''), COALESCE(new.content_folded, ''));
 	END`, ftsTable, poemTable)
 	if err := db.Exec(insertTrigger).Error; err != nil {
 		return fmt.Errorf("failed to create insert trigger for %s: %w", ftsTable, err)
@@ -266,8 +363,10 @@ func (db *DB) migrateFtsForLang(lang Lang) error {
 
 	updateTrigger := fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[2]s_fts_au AFTER UPDATE ON %[2]s BEGIN
 		DELETE FROM %[1]s WHERE rowid = old.id;
-		INSERT INTO %[1]s(rowid, title, content_text)
-		VALUES (new.id, new.title, `+fmt.Sprintf(contentTextExpr, "new")+`);
+		INSERT INTO %[1]s(rowid, title, content_text, title_pinyin, content_pinyin, title_folded, content_folded)
+		VALUES (new.id, new.title, `+fmt.Sprintf(contentTextExpr, "new")+`,
+			COALESCE(new.title_pinyin, ''), COALESCE(new.content_pinyin, ''),
+			COALESCE(new.title_folded, ''), COALESCE(new.content_folded, ''));
 	END`, ftsTable, poemTable)
 	if err := db.Exec(updateTrigger).Error; err != nil {
 		return fmt.Errorf("failed to create update trigger for %s: %w", ftsTable, err)
@@ -282,8 +381,10 @@ func (db *DB) migrateFtsForLang(lang Lang) error {
 	// (a derived expression, not a stored column) does not, so backfill manually
 	// with the same expression the triggers use.
 	if existingCount == 0 {
-		backfillSQL := fmt.Sprintf(`INSERT INTO %[1]s(rowid, title, content_text)
-			SELECT id, title, `+fmt.Sprintf(contentTextExpr, poemTable)+`
+		backfillSQL := fmt.Sprintf(`INSERT INTO %[1]s(rowid, title, content_text, title_pinyin, content_pinyin, title_folded, content_folded)
+			SELECT id, title, `+fmt.Sprintf(contentTextExpr, poemTable)+`,
+				COALESCE(title_pinyin, ''), COALESCE(content_pinyin, ''),
+				COALESCE(title_folded, ''), COALESCE(content_folded, '')
 			FROM %[2]s`, ftsTable, poemTable)
 		if err := db.Exec(backfillSQL).Error; err != nil {
 			return fmt.Errorf("failed to backfill %s: %w", ftsTable, err)
@@ -301,6 +402,7 @@ func (db *DB) insertInitialDataForLang(lang Lang) error {
 	// Prepare SQL - convert to traditional if needed
 	dynastiesSQL := strings.ReplaceAll(InitialDynastiesSQL, "dynasties", dynastyTable)
 	poetryTypesSQL := strings.ReplaceAll(InitialPoetryTypesSQL, "poetry_types", poetryTypeTable)
+	ciPaiSQL := strings.ReplaceAll(InitialCiPaiSQL, "ci_pai", ciPaiTable(lang))
 
 	if lang == LangHant {
 		var err error
@@ -312,6 +414,10 @@ func (db *DB) insertInitialDataForLang(lang Lang) error {
 		if err != nil {
 			return fmt.Errorf("failed to convert poetry types SQL: %w", err)
 		}
+		ciPaiSQL, err = convertSQLToTraditional(ciPaiSQL)
+		if err != nil {
+			return fmt.Errorf("failed to convert ci pai SQL: %w", err)
+		}
 	}
 
 	// Insert dynasties
@@ -324,6 +430,11 @@ func (db *DB) insertInitialDataForLang(lang Lang) error {
 		return fmt.Errorf("failed to insert poetry types: %w", err)
 	}
 
+	// Insert ci pai
+	if err := db.Exec(ciPaiSQL).Error; err != nil {
+		return fmt.Errorf("failed to insert ci pai: %w", err)
+	}
+
 	return nil
 }
 
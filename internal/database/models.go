@@ -1,6 +1,8 @@
 package database
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"gorm.io/datatypes"
@@ -52,19 +54,61 @@ func (PoetryType) TableName() string {
 	return "poetry_types"
 }
 
+// CiPai represents a 词牌, the tune a ci is written to
+type CiPai struct {
+	ID        int64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string         `gorm:"not null;uniqueIndex"     json:"name"`
+	Aliases   datatypes.JSON `gorm:"type:json"                json:"aliases,omitempty"` // JSON array of other names, e.g. 百字令 for 念奴娇
+	Chars     *int           `                                json:"chars,omitempty"`   // Characters of the standard form (正体)
+	CreatedAt time.Time      `gorm:"autoCreateTime"           json:"created_at"`
+}
+
+// TableName specifies the table name for CiPai
+func (CiPai) TableName() string {
+	return "ci_pai"
+}
+
+// PoemIDBits is the size of a poem ID, small enough for every ID to be held
+// exactly by a float64, as JSON numbers are read in JavaScript. The processor
+// derives IDs from hashes, so they are spread evenly over [1, 2^PoemIDBits).
+const PoemIDBits = 53
+
 // Poem represents a poem or ci
 type Poem struct {
-	ID          int64          `gorm:"primaryKey"                                                json:"id"` // Changed from string to int64
-	TypeID      *int64         `gorm:"index"                                                     json:"type_id,omitempty"`
-	Type        *PoetryType    `gorm:"foreignKey:TypeID"                                         json:"type,omitempty"`
-	Title       string         `gorm:"not null;index;uniqueIndex:idx_unique_poem,composite:title" json:"title"`
-	Content     datatypes.JSON `gorm:"type:json;not null"                                        json:"content"` // JSON array of paragraphs
-	ContentHash string         `gorm:"size:64;uniqueIndex:idx_unique_poem,composite:content_hash" json:"-"`      // SHA256 hash of joined text for deduplication
-	AuthorID    *int64         `gorm:"index"                                                     json:"author_id,omitempty"`
-	Author      *Author        `gorm:"foreignKey:AuthorID"                                       json:"author,omitempty"`
-	DynastyID   *int64         `gorm:"index"                                                     json:"dynasty_id,omitempty"`
-	Dynasty     *Dynasty       `gorm:"foreignKey:DynastyID"                                      json:"dynasty,omitempty"`
-	CreatedAt   time.Time      `gorm:"autoCreateTime"                                            json:"created_at"`
+	ID             int64          `gorm:"primaryKey"                                                json:"id"` // Changed from string to int64
+	TypeID         *int64         `gorm:"index"                                                     json:"type_id,omitempty"`
+	Type           *PoetryType    `gorm:"foreignKey:TypeID"                                         json:"type,omitempty"`
+	TypeRule       string         `gorm:"type:text"                                                 json:"-"`                   // classifier.Rule*, the rule that decided the type
+	TypeConfidence float64        `gorm:"type:real"                                                 json:"-"`                   // How far that rule can be trusted, from 0 to 1
+	TypeEvidence   datatypes.JSON `gorm:"type:json"                                                 json:"-"`                   // classifier.ClassificationEvidence
+	CiPaiID        *int64         `gorm:"index"                                                     json:"ci_pai_id,omitempty"` // Set for ci with a known tune
+	CiPai          *CiPai         `gorm:"foreignKey:CiPaiID"                                        json:"ci_pai,omitempty"`
+	GongDiao       string         `gorm:"index"                                                     json:"gong_diao,omitempty"` // 宫调 of a 元曲, if its title names one
+	QuPai          string         `gorm:"index"                                                     json:"qu_pai,omitempty"`    // 曲牌 of a 元曲
+	Title          string         `gorm:"not null;index;uniqueIndex:idx_unique_poem,composite:title" json:"title"`
+	Content        datatypes.JSON `gorm:"type:json;not null"                                        json:"content"` // JSON array of paragraphs
+	StanzaSizes    datatypes.JSON `gorm:"type:json"                                                 json:"-"`       // JSON array of the number of paragraphs in each stanza, empty for one stanza
+	ContentHash    string         `gorm:"size:64;uniqueIndex:idx_unique_poem,composite:content_hash" json:"-"`      // SHA256 hash of joined text, variants folded, for deduplication
+	ClusterID      *int64         `gorm:"index"                                                     json:"-"`       // Lowest poem ID among the poem's near duplicates, nil if it has none
+	SourceKey      string         `gorm:"index"                                                     json:"-"`       // loader.PoemWithMeta.SourceKey, identifying the source record across updates
+	SourceHash     string         `gorm:"size:64"                                                   json:"-"`       // loader.PoemWithMeta.SourceHash, telling when the source record changed
+	DatasetKey     string         `gorm:"index"                                                     json:"-"`       // Key of the upstream dataset in the loader's datas.json
+	DatasetName    string         `gorm:"type:text"                                                 json:"-"`       // Name of that dataset
+	SourcePath     string         `gorm:"type:text"                                                 json:"-"`       // Path of the upstream JSON file, relative to the data root
+	SourceID       string         `gorm:"type:text"                                                 json:"-"`       // Upstream id of the record, empty if it has none
+	SourceIndex    int            `gorm:"type:integer"                                              json:"-"`       // Position of the record in its file
+	TitlePinyin    string         `gorm:"type:text"                                                 json:"-"`       // Pinyin search index of the title, see pinyin.Index
+	ContentPinyin  string         `gorm:"type:text"                                                 json:"-"`       // Pinyin search index of the content
+	TitleFolded    string         `gorm:"type:text"                                                 json:"-"`       // Title folded with classifier.FoldVariants, for search
+	ContentFolded  string         `gorm:"type:text"                                                 json:"-"`       // Paragraphs joined and folded the same way
+	MeterStatus    string         `gorm:"index"                                                     json:"-"`       // classifier.MeterStrict/Rescued/Broken, empty unless shaped as 近体诗
+	MeterDetail    datatypes.JSON `gorm:"type:json"                                                 json:"-"`       // classifier.MeterAnalysis
+	RhymeDetail    datatypes.JSON `gorm:"type:json"                                                 json:"-"`       // classifier.RhymeAnalysis
+	AuthorID       *int64         `gorm:"index"                                                     json:"author_id,omitempty"`
+	Author         *Author        `gorm:"foreignKey:AuthorID"                                       json:"author,omitempty"`
+	DynastyID      *int64         `gorm:"index"                                                     json:"dynasty_id,omitempty"`
+	Dynasty        *Dynasty       `gorm:"foreignKey:DynastyID"                                      json:"dynasty,omitempty"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime"                                            json:"created_at"`
 }
 
 // TableName specifies the table name for Poem
@@ -72,6 +116,78 @@ func (Poem) TableName() string {
 	return "poems"
 }
 
+// Source is where a poem comes from in the upstream data
+type Source struct {
+	Dataset     string  `json:"dataset"`             // Dataset key in the loader's datas.json
+	DatasetName string  `json:"dataset_name"`        // Dataset name
+	File        string  `json:"file"`                // Path of the JSON file, relative to the data root
+	RecordID    *string `json:"record_id,omitempty"` // Upstream id of the record, nil if it has none
+	Index       int     `json:"index"`               // Position of the record in the file, from 0
+}
+
+// Source returns the provenance of the poem
+func (p *Poem) Source() *Source {
+	source := &Source{
+		Dataset:     p.DatasetKey,
+		DatasetName: p.DatasetName,
+		File:        p.SourcePath,
+		Index:       p.SourceIndex,
+	}
+	if p.SourceID != "" {
+		source.RecordID = &p.SourceID
+	}
+	return source
+}
+
+// Stanzas returns the paragraphs of Content grouped into stanzas by
+// StanzaSizes, or as a single stanza when the poem has no breaks
+func (p *Poem) Stanzas() ([][]string, error) {
+	var paragraphs []string
+	if err := json.Unmarshal(p.Content, &paragraphs); err != nil {
+		return nil, err
+	}
+	var sizes []int
+	if len(p.StanzaSizes) > 0 {
+		if err := json.Unmarshal(p.StanzaSizes, &sizes); err != nil {
+			return nil, err
+		}
+	}
+	if len(sizes) == 0 {
+		return [][]string{paragraphs}, nil
+	}
+
+	stanzas := make([][]string, 0, len(sizes))
+	for _, size := range sizes {
+		if size < 1 || size > len(paragraphs) {
+			return nil, fmt.Errorf("stanza sizes %v don't fit poem %d", sizes, p.ID)
+		}
+		stanzas = append(stanzas, paragraphs[:size])
+		paragraphs = paragraphs[size:]
+	}
+	if len(paragraphs) > 0 {
+		return nil, fmt.Errorf("stanza sizes %v don't fit poem %d", sizes, p.ID)
+	}
+	return stanzas, nil
+}
+
+// PoemSource is the source identity of a stored poem, see Poem.SourceKey, or
+// of a source record skipped as an exact duplicate of the poem DuplicateOf
+type PoemSource struct {
+	ID          int64
+	SourceKey   string
+	SourceHash  string
+	SourcePath  string // Where the record was when the poem was stored, empty for a duplicate
+	SourceIndex int
+	DuplicateOf int64 // ID of the stored poem the record duplicates, 0 for a stored poem
+}
+
+// AuthorDescription is the description of the author of a name in a dynasty
+type AuthorDescription struct {
+	Name        string
+	Dynasty     string
+	Description string
+}
+
 // AuthorWithStats includes statistics
 type AuthorWithStats struct {
 	Author
@@ -91,6 +207,26 @@ type PoetryTypeWithStats struct {
 	PoemCount int `json:"poem_count"`
 }
 
+// CiPaiWithStats includes statistics
+type CiPaiWithStats struct {
+	CiPai
+	PoemCount int `json:"poem_count"`
+}
+
+// QuPaiStats counts the 元曲 written to a 曲牌 under one 宫调
+type QuPaiStats struct {
+	Name      string `json:"name"`
+	GongDiao  string `json:"gong_diao"`
+	PoemCount int    `json:"poem_count"`
+}
+
+// GongDiaoStats counts the 曲牌 and 元曲 under a 宫调
+type GongDiaoStats struct {
+	Name       string `json:"name"`
+	QuPaiCount int    `json:"qu_pai_count"`
+	PoemCount  int    `json:"poem_count"`
+}
+
 // Statistics holds overall statistics
 type Statistics struct {
 	TotalPoems     int                   `json:"total_poems"`
@@ -119,6 +255,12 @@ type PoemConnection struct {
 type PoemEdge struct {
 	Node   Poem   `json:"node"`
 	Cursor string `json:"cursor"`
+
+	// Set on search results only
+	Score     *float64      `json:"score,omitempty"`
+	Highlight *string       `json:"highlight,omitempty"`
+	Snippet   *string       `json:"snippet,omitempty"`
+	Matches   []MatchOffset `json:"matches,omitempty"`
 }
 
 // AuthorConnection represents a paginated list of authors
@@ -8,21 +8,34 @@ import (
 type RepositoryInterface interface {
 	GetOrCreateDynasty(name string) (int64, error)
 	GetOrCreateAuthor(name string, dynastyID int64) (int64, error)
+	UpdateAuthorDescriptions(descriptions []AuthorDescription) (int64, error)
 	GetPoetryTypeID(name string) (int64, error)
 	GetPoetryTypeIDs(names []string) ([]int64, error)
+	GetOrCreateCiPai(name string) (int64, error)
 	InsertPoem(poem *Poem) error
 	BatchInsertPoems(poems []*Poem, batchSize int) error
 	BatchInsertPoemsWithTransaction(poems []*Poem, transactionSize, batchSize int, progress *mpb.Progress) error
 	UpsertPoem(poem *Poem) error
+	ListPoemSources() ([]PoemSource, error)
+	RecordDuplicates(poems []*Poem) (int, error)
+	SavePoems(poems []*Poem) ([]PoemSource, error)
+	DeletePoems(ids []int64) error
+	DeleteDuplicates(sourceKeys []string) error
+	DeleteUnusedAuthors() (int64, error)
+	ListPoemTexts(afterID int64, limit int) ([]*Poem, error)
+	UpdateSourcePositions(sources []PoemSource) error
+	UpdateClusterIDs(poems []*Poem) error
 	GetPoemByID(id string) (*Poem, error)
 	CountPoems() (int, error)
 	CountAuthors() (int, error)
 	GetStatistics() (*Statistics, error)
 	ListPoems(limit, offset int) ([]Poem, error)
 	ListPoemsWithFilter(limit, offset int, dynastyID, authorID, typeID *int64) ([]Poem, int, error)
+	ListPoemsByFilter(filter PoemFilter, limit, offset int) ([]Poem, int, error)
 	ListAuthorPoems(authorID int64, limit, offset int) ([]Poem, int, error)
 	ListAuthorsWithFilter(limit, offset int, dynastyID *int64) ([]AuthorWithStats, int, error)
 	SearchPoems(query string, searchType string, page, pageSize int) ([]Poem, int64, error)
+	SearchPoemsWithOptions(query string, opts SearchOptions) ([]SearchResult, int64, error)
 }
 
 // Repository handles database operations
@@ -36,23 +49,27 @@ func NewRepository(db *DB) *Repository {
 	return &Repository{db: db, lang: LangHans}
 }
 
-// NewRepositoryWithLang creates a new repository for a specific language variant
+// NewRepositoryWithLang creates a new repository for a specific language variant.
+// A regional variant reads the tables of its base variant (see Lang.Base); its
+// results are converted with Lang.Convert by the caller.
 func NewRepositoryWithLang(db *DB, lang Lang) *Repository {
-	return &Repository{db: db, lang: lang}
+	return &Repository{db: db, lang: lang.Base()}
 }
 
 // WithLang returns a new Repository instance with the specified language variant.
 // This allows runtime language switching without modifying the original repository.
 func (r *Repository) WithLang(lang Lang) *Repository {
-	return &Repository{db: r.db, lang: lang}
+	return &Repository{db: r.db, lang: lang.Base()}
 }
 
 // Table name helpers for this repository's language
-func (r *Repository) poemsTable() string       { return PoemsTable(r.lang) }
-func (r *Repository) authorsTable() string     { return AuthorsTable(r.lang) }
-func (r *Repository) dynastiesTable() string   { return DynastiesTable(r.lang) }
-func (r *Repository) poetryTypesTable() string { return PoetryTypesTable(r.lang) }
-func (r *Repository) poemsFtsTable() string    { return PoemsFtsTable(r.lang) }
+func (r *Repository) poemsTable() string          { return PoemsTable(r.lang) }
+func (r *Repository) authorsTable() string        { return AuthorsTable(r.lang) }
+func (r *Repository) dynastiesTable() string      { return DynastiesTable(r.lang) }
+func (r *Repository) poetryTypesTable() string    { return PoetryTypesTable(r.lang) }
+func (r *Repository) ciPaiTable() string          { return CiPaiTable(r.lang) }
+func (r *Repository) poemsFtsTable() string       { return PoemsFtsTable(r.lang) }
+func (r *Repository) poemDuplicatesTable() string { return PoemDuplicatesTable(r.lang) }
 
 // Public accessors for external packages (e.g., search engine)
 func (r *Repository) DB() *DB                { return r.db }
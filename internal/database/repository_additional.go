@@ -1,21 +1,21 @@
 package database
 
+import (
+	"errors"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
 // Additional repository methods for REST API handlers
 
 // GetAuthorsWithStats returns authors with their poem counts
 func (r *Repository) GetAuthorsWithStats(limit, offset int) ([]AuthorWithStats, error) {
-	authorTable := r.authorsTable()
-	poemTable := r.poemsTable()
-	dynastyTable := r.dynastiesTable()
-
 	var authors []AuthorWithStats
 
-	// Pre-aggregate poem counts by author_id, then join authors for pagination.
-	err := r.db.Table(authorTable).
-		Select(authorTable + ".*, COUNT(" + poemTable + ".id) AS poem_count").
-		Joins("LEFT JOIN " + poemTable + " ON " + authorTable + ".id = " + poemTable + ".author_id").
-		Group(authorTable + ".id").
-		Order("poem_count DESC").
+	// Poem counts are stored with the authors (see migrateAuthorCountsForLang)
+	err := r.db.Table(r.authorsTable()).
+		Order("poem_count DESC, id ASC").
 		Limit(limit).
 		Offset(offset).
 		Find(&authors).Error
@@ -23,7 +23,12 @@ func (r *Repository) GetAuthorsWithStats(limit, offset int) ([]AuthorWithStats,
 		return nil, err
 	}
 
-	// Load dynasty for each author
+	r.loadAuthorDynasties(authors)
+	return authors, nil
+}
+
+// loadAuthorDynasties fills in the Dynasty of each author with one query
+func (r *Repository) loadAuthorDynasties(authors []AuthorWithStats) {
 	dynastyIDs := make(map[int64]bool)
 	for _, a := range authors {
 		if a.DynastyID != nil {
@@ -31,29 +36,29 @@ func (r *Repository) GetAuthorsWithStats(limit, offset int) ([]AuthorWithStats,
 		}
 	}
 
-	if len(dynastyIDs) > 0 {
-		ids := make([]int64, 0, len(dynastyIDs))
-		for id := range dynastyIDs {
-			ids = append(ids, id)
-		}
-		var dynasties []Dynasty
-		r.db.Table(dynastyTable).Where("id IN ?", ids).Find(&dynasties)
+	if len(dynastyIDs) == 0 {
+		return
+	}
 
-		dynastyMap := make(map[int64]*Dynasty)
-		for i := range dynasties {
-			dynastyMap[dynasties[i].ID] = &dynasties[i]
-		}
+	ids := make([]int64, 0, len(dynastyIDs))
+	for id := range dynastyIDs {
+		ids = append(ids, id)
+	}
+	var dynasties []Dynasty
+	r.db.Table(r.dynastiesTable()).Where("id IN ?", ids).Find(&dynasties)
+
+	dynastyMap := make(map[int64]*Dynasty)
+	for i := range dynasties {
+		dynastyMap[dynasties[i].ID] = &dynasties[i]
+	}
 
-		for i := range authors {
-			if authors[i].DynastyID != nil {
-				if d, ok := dynastyMap[*authors[i].DynastyID]; ok {
-					authors[i].Dynasty = d
-				}
+	for i := range authors {
+		if authors[i].DynastyID != nil {
+			if d, ok := dynastyMap[*authors[i].DynastyID]; ok {
+				authors[i].Dynasty = d
 			}
 		}
 	}
-
-	return authors, nil
 }
 
 // GetAuthorByID returns an author by ID
@@ -94,21 +99,15 @@ func (r *Repository) GetAuthorByName(name string) (*Author, error) {
 	return &author, nil
 }
 
-// GetPoemsByAuthor returns poems by a specific author
-func (r *Repository) GetPoemsByAuthor(authorID int64, limit, offset int) ([]Poem, error) {
-	var poems []Poem
-	err := r.db.Table(r.poemsTable()).
-		Where("author_id = ?", authorID).
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&poems).Error
-	if err != nil {
-		return nil, err
-	}
+// GetAuthorIDs gets IDs for multiple authors by name in a single query
+// Returns error if any of the requested authors are not found
+func (r *Repository) GetAuthorIDs(names []string) ([]int64, error) {
+	return r.lookupIDsByName(r.authorsTable(), names)
+}
 
-	r.loadPoemRelations(poems)
-	return poems, nil
+// GetPoemsByAuthor returns a page of poems by a specific author along with the total count.
+func (r *Repository) GetPoemsByAuthor(authorID int64, limit, offset int) ([]Poem, int, error) {
+	return r.ListPoemsByFilter(PoemFilter{AuthorIDs: []int64{authorID}}, limit, offset)
 }
 
 // GetDynastiesWithStats returns dynasties with their poem and author counts
@@ -144,24 +143,19 @@ func (r *Repository) GetDynastyByName(name string) (*Dynasty, error) {
 	return &dynasty, err
 }
 
-// GetPoemsByDynasty returns poems from a specific dynasty
-func (r *Repository) GetPoemsByDynasty(dynastyID int64, limit, offset int) ([]Poem, error) {
-	var poems []Poem
-	err := r.db.Table(r.poemsTable()).
-		Where("dynasty_id = ?", dynastyID).
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&poems).Error
-	if err != nil {
-		return nil, err
-	}
+// GetDynastyIDs gets IDs for multiple dynasties by name in a single query
+// Returns error if any of the requested dynasties are not found
+func (r *Repository) GetDynastyIDs(names []string) ([]int64, error) {
+	return r.lookupIDsByName(r.dynastiesTable(), names)
+}
 
-	r.loadPoemRelations(poems)
-	return poems, nil
+// GetPoemsByDynasty returns a page of poems from a specific dynasty along with the total count.
+func (r *Repository) GetPoemsByDynasty(dynastyID int64, limit, offset int) ([]Poem, int, error) {
+	return r.ListPoemsByFilter(PoemFilter{DynastyIDs: []int64{dynastyID}}, limit, offset)
 }
 
-// GetPoetryTypesWithStats returns poetry types with their poem counts
+// GetPoetryTypesWithStats returns poetry types with their poem counts. A type
+// counts the poems of its subtypes too, as filtering by it does.
 func (r *Repository) GetPoetryTypesWithStats() ([]PoetryTypeWithStats, error) {
 	typeTable := r.poetryTypesTable()
 	poemTable := r.poemsTable()
@@ -173,8 +167,22 @@ func (r *Repository) GetPoetryTypesWithStats() ([]PoetryTypeWithStats, error) {
 		Select(typeTable + ".*, (SELECT COUNT(*) FROM " + poemTable + " WHERE " + poemTable + ".type_id = " + typeTable + ".id) as poem_count").
 		Order("poem_count DESC").
 		Find(&types).Error
+	if err != nil {
+		return nil, err
+	}
 
-	return types, err
+	counts := make(map[int64]int, len(types))
+	for _, t := range types {
+		counts[t.ID] = t.PoemCount
+	}
+	for i := range types {
+		for _, subtype := range poetryTypeSubtypes[types[i].ID] {
+			types[i].PoemCount += counts[subtype]
+		}
+	}
+	slices.SortStableFunc(types, func(a, b PoetryTypeWithStats) int { return b.PoemCount - a.PoemCount })
+
+	return types, nil
 }
 
 // GetPoetryTypeByID returns a poetry type by ID
@@ -184,19 +192,125 @@ func (r *Repository) GetPoetryTypeByID(id int64) (*PoetryType, error) {
 	return &poetryType, err
 }
 
-// GetPoemsByType returns poems of a specific type
-func (r *Repository) GetPoemsByType(typeID int64, limit, offset int) ([]Poem, error) {
-	var poems []Poem
-	err := r.db.Table(r.poemsTable()).
-		Where("type_id = ?", typeID).
-		Order("created_at DESC").
+// GetPoemsByType returns a page of poems of a specific type along with the total count.
+func (r *Repository) GetPoemsByType(typeID int64, limit, offset int) ([]Poem, int, error) {
+	return r.ListPoemsByFilter(PoemFilter{TypeIDs: []int64{typeID}}, limit, offset)
+}
+
+// GetCiPaisWithStats returns a page of 词牌 with their poem counts, most used
+// first, along with the total count
+func (r *Repository) GetCiPaisWithStats(limit, offset int) ([]CiPaiWithStats, int, error) {
+	var total int64
+	if err := r.db.Table(r.ciPaiTable()).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var ciPais []CiPaiWithStats
+	err := r.ciPaiWithStats().
+		Order("poem_count DESC, id ASC").
 		Limit(limit).
 		Offset(offset).
-		Find(&poems).Error
+		Find(&ciPais).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ciPais, int(total), nil
+}
+
+// GetCiPaiWithStats returns a 词牌 by ID with its poem count
+func (r *Repository) GetCiPaiWithStats(id int64) (*CiPaiWithStats, error) {
+	var ciPai CiPaiWithStats
+	err := r.ciPaiWithStats().Where("id = ?", id).First(&ciPai).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ciPai, nil
+}
+
+// ciPaiWithStats selects 词牌 along with the number of poems written to each
+func (r *Repository) ciPaiWithStats() *gorm.DB {
+	ciPaiTable := r.ciPaiTable()
+	poemTable := r.poemsTable()
+
+	// Use subquery for better performance on large datasets
+	return r.db.Table(ciPaiTable).
+		Select(ciPaiTable + ".*, (SELECT COUNT(*) FROM " + poemTable + " WHERE " + poemTable + ".ci_pai_id = " + ciPaiTable + ".id) as poem_count")
+}
+
+// GetCiPaiByName returns a 词牌 by its name or one of its aliases, so that
+// 百字令 finds 念奴娇
+func (r *Repository) GetCiPaiByName(name string) (*CiPai, error) {
+	var ciPai CiPai
+	err := r.db.Table(r.ciPaiTable()).
+		Where("name = ? OR EXISTS (SELECT 1 FROM json_each(aliases) WHERE value = ?)", name, name).
+		First(&ciPai).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ciPai, nil
+}
+
+// GetCiPaiIDs gets IDs for multiple 词牌 by name or alias, as given or
+// converted to the script of the tables (see Lang.ToBase)
+// Returns error if any of the requested tunes are not found
+func (r *Repository) GetCiPaiIDs(names []string) ([]int64, error) {
+	ids := make([]int64, len(names))
+	for i, name := range names {
+		ciPai, err := r.GetCiPaiByName(name)
+		if base := r.baseName(name); errors.Is(err, gorm.ErrRecordNotFound) && base != name {
+			ciPai, err = r.GetCiPaiByName(base)
+		}
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = ciPai.ID
+	}
+	return ids, nil
+}
+
+// GetGongDiaosWithStats returns the 宫调 of the 元曲 with their 曲牌 and poem
+// counts, most used first
+func (r *Repository) GetGongDiaosWithStats() ([]GongDiaoStats, error) {
+	var gongDiaos []GongDiaoStats
+	err := r.db.Table(r.poemsTable()).
+		Select("gong_diao AS name, COUNT(DISTINCT qu_pai) AS qu_pai_count, COUNT(*) AS poem_count").
+		Where("gong_diao <> ''").
+		Group("gong_diao").
+		Order("poem_count DESC, name ASC").
+		Find(&gongDiaos).Error
 	if err != nil {
 		return nil, err
 	}
+	return gongDiaos, nil
+}
+
+// GetQuPaisWithStats returns a page of the 曲牌 of the 元曲, each with the
+// 宫调 it is written under and its poem count, most used first.
+// A 曲牌 found under several 宫调 is listed once for each; gongDiaos, if
+// given, keeps only those under one of them.
+func (r *Repository) GetQuPaisWithStats(gongDiaos []string, limit, offset int) ([]QuPaiStats, int, error) {
+	q := r.db.Table(r.poemsTable()).
+		Select("qu_pai AS name, gong_diao, COUNT(*) AS poem_count").
+		Where("qu_pai <> ''").
+		Group("gong_diao, qu_pai")
+	if len(gongDiaos) > 0 {
+		q = q.Where("gong_diao IN ?", gongDiaos)
+	}
+
+	var total int64
+	if err := r.db.Table("(?) AS qu_pai", q).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var quPais []QuPaiStats
+	err := q.Order("poem_count DESC, gong_diao ASC, name ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&quPais).Error
+	if err != nil {
+		return nil, 0, err
+	}
 
-	r.loadPoemRelations(poems)
-	return poems, nil
+	return quPais, int(total), nil
 }
@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -152,16 +153,25 @@ func TestGetPoemsByAuthor(t *testing.T) {
 		limit   int
 		offset  int
 		wantLen int
+		wantIDs []int64
 	}{
-		{"get all poems", 10, 0, 5},
-		{"get with pagination", 2, 0, 2},
+		{"get all poems", 10, 0, 5, []int64{10, 11, 12, 13, 14}},
+		{"get with pagination", 2, 0, 2, []int64{10, 11}},
+		{"get second page", 2, 2, 2, []int64{12, 13}},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			poems, err := repo.GetPoemsByAuthor(authorID, tt.limit, tt.offset)
+			poems, total, err := repo.GetPoemsByAuthor(authorID, tt.limit, tt.offset)
 			require.NoError(t, err)
 			assert.Len(t, poems, tt.wantLen)
+			assert.Equal(t, 5, total)
+
+			ids := make([]int64, len(poems))
+			for i, p := range poems {
+				ids[i] = p.ID
+			}
+			assert.Equal(t, tt.wantIDs, ids, "poems should be ordered by ID")
 		})
 	}
 }
@@ -235,6 +245,51 @@ func TestGetDynastyByID(t *testing.T) {
 	}
 }
 
+func TestGetPoemsByDynasty(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	tangID, _ := repo.GetOrCreateDynasty("唐")
+	songID, _ := repo.GetOrCreateDynasty("宋")
+	authorID, _ := repo.GetOrCreateAuthor("李白", tangID)
+
+	// Insert out of ID order to make sure results are sorted by ID, not insertion order
+	for i, id := range []int64{23, 21, 22} {
+		content := []byte(fmt.Sprintf(`["唐诗内容%d"]`, i))
+		_ = createTestPoem(repo, &Poem{
+			ID:          id,
+			Title:       fmt.Sprintf("唐诗%d", i),
+			Content:     datatypes.JSON(content),
+			ContentHash: calculateTestHash(content),
+			AuthorID:    &authorID,
+			DynastyID:   &tangID,
+		})
+	}
+	content := []byte(`["宋词内容"]`)
+	_ = createTestPoem(repo, &Poem{
+		ID:          30,
+		Title:       "宋词",
+		Content:     datatypes.JSON(content),
+		ContentHash: calculateTestHash(content),
+		AuthorID:    &authorID,
+		DynastyID:   &songID,
+	})
+
+	poems, total, err := repo.GetPoemsByDynasty(tangID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, poems, 3)
+	assert.Equal(t, int64(21), poems[0].ID)
+	assert.Equal(t, int64(22), poems[1].ID)
+	assert.Equal(t, int64(23), poems[2].ID)
+	assert.NotNil(t, poems[0].Author)
+
+	poems, total, err = repo.GetPoemsByDynasty(songID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, poems, 1)
+}
+
 func TestGetPoetryTypesWithStats(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewRepository(db)
@@ -277,6 +332,34 @@ func TestGetPoetryTypesWithStats(t *testing.T) {
 
 	require.NotNil(t, foundType)
 	assert.Greater(t, foundType.PoemCount, 0)
+
+	t.Run("a type counts the poems of its subtypes", func(t *testing.T) {
+		for _, pt := range []*PoetryType{{ID: 30, Name: "元曲"}, {ID: 31, Name: "小令"}, {ID: 32, Name: "套数"}} {
+			pt.Category = "曲"
+			require.NoError(t, createTestPoetryType(repo, pt))
+		}
+		for i, typeID := range []int64{31, 32, 32} {
+			content := []byte(`["曲` + strconv.Itoa(i) + `"]`)
+			require.NoError(t, createTestPoem(repo, &Poem{
+				ID:          int64(40 + i),
+				Title:       "曲",
+				Content:     datatypes.JSON(content),
+				ContentHash: calculateTestHash(content),
+				TypeID:      &typeID,
+			}))
+		}
+
+		types, err := repo.GetPoetryTypesWithStats()
+		require.NoError(t, err)
+		counts := map[int64]int{}
+		for _, pt := range types {
+			counts[pt.ID] = pt.PoemCount
+		}
+		assert.Equal(t, 1, counts[31])
+		assert.Equal(t, 2, counts[32])
+		assert.Equal(t, 3, counts[30])
+		assert.Equal(t, int64(30), types[0].ID, "ordered by the counts with subtypes")
+	})
 }
 
 func TestGetPoetryTypeByID(t *testing.T) {
@@ -367,9 +450,109 @@ func TestGetPoemsByType(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			poems, err := repo.GetPoemsByType(typeID, tt.limit, tt.offset)
+			poems, total, err := repo.GetPoemsByType(typeID, tt.limit, tt.offset)
 			require.NoError(t, err)
 			assert.Len(t, poems, tt.wantLen)
+			assert.Equal(t, 3, total)
 		})
 	}
 }
+
+func TestGetCiPaisWithStats(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	dynastyID, _ := repo.GetOrCreateDynasty("宋")
+	authorID, _ := repo.GetOrCreateAuthor("苏轼", dynastyID)
+	shuidiaoID, err := repo.GetOrCreateCiPai("水调歌头")
+	require.NoError(t, err)
+	niannujiaoID, err := repo.GetOrCreateCiPai("念奴娇")
+	require.NoError(t, err)
+
+	for i, title := range []string{"水调歌头·明月几时有", "水调歌头·落日绣帘卷"} {
+		content := []byte(fmt.Sprintf(`["测试内容%d"]`, i))
+		_ = createTestPoem(repo, &Poem{
+			ID:          int64(50 + i),
+			Title:       title,
+			Content:     datatypes.JSON(content),
+			ContentHash: calculateTestHash(content),
+			AuthorID:    &authorID,
+			DynastyID:   &dynastyID,
+			CiPaiID:     &shuidiaoID,
+		})
+	}
+
+	ciPais, total, err := repo.GetCiPaisWithStats(10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, ciPais, 2)
+	assert.Equal(t, shuidiaoID, ciPais[0].ID, "Most used tune first")
+	assert.Equal(t, 2, ciPais[0].PoemCount)
+	assert.Equal(t, 0, ciPais[1].PoemCount)
+
+	ciPai, err := repo.GetCiPaiWithStats(niannujiaoID)
+	require.NoError(t, err)
+	assert.Equal(t, "念奴娇", ciPai.Name)
+
+	poems, count, err := repo.ListPoemsByFilter(PoemFilter{CiPaiIDs: []int64{shuidiaoID}}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	require.Len(t, poems, 2)
+	require.NotNil(t, poems[0].CiPai)
+	assert.Equal(t, "水调歌头", poems[0].CiPai.Name)
+}
+
+func TestGetQuPaisWithStats(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	dynastyID, _ := repo.GetOrCreateDynasty("元")
+	authorID, _ := repo.GetOrCreateAuthor("马致远", dynastyID)
+
+	for i, qu := range []struct{ gongDiao, quPai string }{
+		{"双调", "沉醉东风"},
+		{"双调", "沉醉东风"},
+		{"双调", "拨不断"},
+		{"越调", "天净沙"},
+		{"", "山坡羊"},
+	} {
+		content := []byte(fmt.Sprintf(`["测试内容%d"]`, i))
+		_ = createTestPoem(repo, &Poem{
+			ID:          int64(60 + i),
+			Title:       qu.quPai,
+			Content:     datatypes.JSON(content),
+			ContentHash: calculateTestHash(content),
+			AuthorID:    &authorID,
+			DynastyID:   &dynastyID,
+			GongDiao:    qu.gongDiao,
+			QuPai:       qu.quPai,
+		})
+	}
+
+	gongDiaos, err := repo.GetGongDiaosWithStats()
+	require.NoError(t, err)
+	assert.Equal(t, []GongDiaoStats{
+		{Name: "双调", QuPaiCount: 2, PoemCount: 3},
+		{Name: "越调", QuPaiCount: 1, PoemCount: 1},
+	}, gongDiaos)
+
+	quPais, total, err := repo.GetQuPaisWithStats(nil, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, []QuPaiStats{
+		{Name: "沉醉东风", GongDiao: "双调", PoemCount: 2},
+		{Name: "山坡羊", GongDiao: "", PoemCount: 1},
+	}, quPais, "Most used tune first")
+
+	quPais, total, err = repo.GetQuPaisWithStats([]string{"双调"}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, quPais, 2)
+	assert.Equal(t, "拨不断", quPais[1].Name)
+
+	poems, count, err := repo.ListPoemsByFilter(PoemFilter{GongDiaos: []string{"双调"}, QuPais: []string{"沉醉东风", "天净沙"}}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	require.Len(t, poems, 2)
+	assert.Equal(t, "沉醉东风", poems[0].QuPai)
+}
@@ -1,11 +1,17 @@
 package database
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/datatypes"
+
+	"github.com/palemoky/chinese-poetry-api/internal/classifier"
+	"github.com/palemoky/chinese-poetry-api/internal/pinyin"
+	"github.com/palemoky/chinese-poetry-api/internal/search"
 )
 
 func TestInsertPoem(t *testing.T) {
@@ -153,6 +159,212 @@ func TestListPoemsWithFilter(t *testing.T) {
 	})
 }
 
+func TestListPoemsByFilter(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	tangID, _ := repo.GetOrCreateDynasty("唐")
+	songID, _ := repo.GetOrCreateDynasty("宋")
+	yuanID, _ := repo.GetOrCreateDynasty("元")
+	libaiID, _ := repo.GetOrCreateAuthor("李白", tangID)
+	dumuID, _ := repo.GetOrCreateAuthor("杜牧", tangID)
+	sushiID, _ := repo.GetOrCreateAuthor("苏轼", songID)
+	jueju, lushi := int64(10), int64(11)
+	xiaoling, taoshu := int64(31), int64(32)
+
+	poems := []*Poem{
+		{ID: 4, Title: "宋诗1", Content: datatypes.JSON([]byte(`["内容"]`)), AuthorID: &sushiID, DynastyID: &songID, TypeID: &jueju, DatasetKey: "songshi"},
+		{ID: 1, Title: "唐诗1", Content: datatypes.JSON([]byte(`["内容"]`)), AuthorID: &libaiID, DynastyID: &tangID, TypeID: &jueju, DatasetKey: "tangsong"},
+		{ID: 3, Title: "唐诗3", Content: datatypes.JSON([]byte(`["内容"]`)), AuthorID: &dumuID, DynastyID: &tangID, TypeID: &lushi, DatasetKey: "tangsong"},
+		{ID: 2, Title: "唐诗2", Content: datatypes.JSON([]byte(`["内容"]`)), AuthorID: &libaiID, DynastyID: &tangID, TypeID: &lushi, DatasetKey: "tangsong"},
+		{ID: 5, Title: "小令", Content: datatypes.JSON([]byte(`["内容"]`)), DynastyID: &yuanID, TypeID: &xiaoling, DatasetKey: "yuanqu"},
+		{ID: 6, Title: "套数", Content: datatypes.JSON([]byte(`["内容"]`)), DynastyID: &yuanID, TypeID: &taoshu, DatasetKey: "yuanqu"},
+	}
+	for _, poem := range poems {
+		require.NoError(t, repo.InsertPoem(poem))
+	}
+
+	tests := []struct {
+		name    string
+		filter  PoemFilter
+		wantIDs []int64
+	}{
+		{"no filters", PoemFilter{}, []int64{1, 2, 3, 4, 5, 6}},
+		{"single author", PoemFilter{AuthorIDs: []int64{libaiID}}, []int64{1, 2}},
+		{"authors are ORed", PoemFilter{AuthorIDs: []int64{libaiID, sushiID}}, []int64{1, 2, 4}},
+		{"dynasties are ORed", PoemFilter{DynastyIDs: []int64{songID, tangID}}, []int64{1, 2, 3, 4}},
+		{"types are ORed", PoemFilter{TypeIDs: []int64{jueju, lushi}}, []int64{1, 2, 3, 4}},
+		{"filters are ANDed", PoemFilter{DynastyIDs: []int64{tangID}, TypeIDs: []int64{lushi}}, []int64{2, 3}},
+		{"ORed and ANDed", PoemFilter{AuthorIDs: []int64{dumuID, sushiID}, TypeIDs: []int64{jueju}}, []int64{4}},
+		{"no matches", PoemFilter{DynastyIDs: []int64{yuanID}, TypeIDs: []int64{jueju}}, nil},
+		{"元曲 matches 小令 and 套数", PoemFilter{TypeIDs: []int64{30}}, []int64{5, 6}},
+		{"subtype alone", PoemFilter{TypeIDs: []int64{taoshu}}, []int64{6}},
+		{"single dataset", PoemFilter{Datasets: []string{"tangsong"}}, []int64{1, 2, 3}},
+		{"datasets are ORed", PoemFilter{Datasets: []string{"songshi", "tangsong"}, AuthorIDs: []int64{dumuID, sushiID}}, []int64{3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, count, err := repo.ListPoemsByFilter(tt.filter, 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.wantIDs), count)
+
+			var gotIDs []int64
+			for _, poem := range result {
+				gotIDs = append(gotIDs, poem.ID)
+			}
+			assert.Equal(t, tt.wantIDs, gotIDs)
+		})
+	}
+}
+
+func TestPagePoemsByFilter(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	tangID, _ := repo.GetOrCreateDynasty("唐")
+	songID, _ := repo.GetOrCreateDynasty("宋")
+	for _, poem := range []*Poem{
+		{ID: 5, Title: "宋诗", Content: datatypes.JSON([]byte(`["内容"]`)), DynastyID: &songID},
+		{ID: 1, Title: "唐诗1", Content: datatypes.JSON([]byte(`["内容"]`)), DynastyID: &tangID},
+		{ID: 3, Title: "唐诗3", Content: datatypes.JSON([]byte(`["内容"]`)), DynastyID: &tangID},
+		{ID: 7, Title: "唐诗7", Content: datatypes.JSON([]byte(`["内容"]`)), DynastyID: &tangID},
+		{ID: 9, Title: "唐诗9", Content: datatypes.JSON([]byte(`["内容"]`)), DynastyID: &tangID},
+	} {
+		require.NoError(t, repo.InsertPoem(poem))
+	}
+	tang := PoemFilter{DynastyIDs: []int64{tangID}}
+
+	tests := []struct {
+		name         string
+		filter       PoemFilter
+		page         Page
+		wantIDs      []int64
+		wantAdjacent Adjacent
+	}{
+		{"first page by offset", tang, Page{Limit: 2}, []int64{1, 3}, Adjacent{After: true}},
+		{"last page by offset", tang, Page{Limit: 2, Offset: 2}, []int64{7, 9}, Adjacent{Before: true}},
+		{"after cursor", tang, Page{Limit: 2, After: &Cursor{ID: 1}}, []int64{3, 7}, Adjacent{Before: true, After: true}},
+		{"after cursor of a filtered-out poem", tang, Page{Limit: 2, After: &Cursor{ID: 5}}, []int64{7, 9}, Adjacent{Before: true}},
+		{"after cursor ahead of the first poem", tang, Page{Limit: 2, After: &Cursor{ID: 0}}, []int64{1, 3}, Adjacent{After: true}},
+		{"before cursor", tang, Page{Limit: 2, Before: &Cursor{ID: 9}}, []int64{3, 7}, Adjacent{Before: true, After: true}},
+		{"before cursor at start", tang, Page{Limit: 2, Before: &Cursor{ID: 3}}, []int64{1}, Adjacent{After: true}},
+		{"after last poem", PoemFilter{}, Page{Limit: 2, After: &Cursor{ID: 9}}, nil, Adjacent{Before: true}},
+		{"cursor takes precedence over offset", PoemFilter{}, Page{Limit: 1, Offset: 3, After: &Cursor{ID: 3}}, []int64{5}, Adjacent{Before: true, After: true}},
+		{"descending", tang, Page{Limit: 2, Desc: true}, []int64{9, 7}, Adjacent{After: true}},
+		{"descending after cursor", tang, Page{Limit: 2, Desc: true, After: &Cursor{ID: 7}}, []int64{3, 1}, Adjacent{Before: true}},
+		{"descending before cursor", tang, Page{Limit: 2, Desc: true, Before: &Cursor{ID: 3}}, []int64{9, 7}, Adjacent{After: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poems, total, adjacent, err := repo.PagePoemsByFilter(tt.filter, tt.page)
+			require.NoError(t, err)
+
+			var gotIDs []int64
+			for _, poem := range poems {
+				gotIDs = append(gotIDs, poem.ID)
+			}
+			assert.Equal(t, tt.wantIDs, gotIDs)
+			assert.Equal(t, tt.wantAdjacent, adjacent)
+
+			wantTotal := 5
+			if len(tt.filter.DynastyIDs) > 0 {
+				wantTotal = 4
+			}
+			assert.Equal(t, wantTotal, total)
+		})
+	}
+}
+
+func TestPageAuthors(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	tangID, _ := repo.GetOrCreateDynasty("唐")
+	songID, _ := repo.GetOrCreateDynasty("宋")
+	libaiID, _ := repo.GetOrCreateAuthor("李白", tangID)
+	dumuID, _ := repo.GetOrCreateAuthor("杜牧", tangID)
+	sushiID, _ := repo.GetOrCreateAuthor("苏轼", songID)
+	wangweiID, _ := repo.GetOrCreateAuthor("王维", tangID)
+
+	// Poem counts: 李白 3, 杜牧 1, 苏轼 1, 王维 0
+	poems := []*Poem{
+		{ID: 1, Title: "诗1", Content: datatypes.JSON([]byte(`["内容"]`)), AuthorID: &libaiID},
+		{ID: 2, Title: "诗2", Content: datatypes.JSON([]byte(`["内容"]`)), AuthorID: &libaiID},
+		{ID: 3, Title: "诗3", Content: datatypes.JSON([]byte(`["内容"]`)), AuthorID: &libaiID},
+		{ID: 4, Title: "诗4", Content: datatypes.JSON([]byte(`["内容"]`)), AuthorID: &dumuID},
+		{ID: 5, Title: "诗5", Content: datatypes.JSON([]byte(`["内容"]`)), AuthorID: &sushiID},
+	}
+	for _, poem := range poems {
+		require.NoError(t, repo.InsertPoem(poem))
+	}
+
+	names := func(authors []AuthorWithStats) []string {
+		var got []string
+		for _, a := range authors {
+			got = append(got, a.Name)
+		}
+		return got
+	}
+
+	t.Run("walk forward by cursor", func(t *testing.T) {
+		var walked []string
+		page := Page{Limit: 2}
+		for {
+			authors, total, adjacent, err := repo.PageAuthors(nil, page)
+			require.NoError(t, err)
+			assert.Equal(t, 4, total)
+			assert.Equal(t, page.After != nil, adjacent.Before)
+			walked = append(walked, names(authors)...)
+			if !adjacent.After {
+				break
+			}
+			last := authors[len(authors)-1]
+			page.After = &Cursor{ID: last.ID, PoemCount: last.PoemCount}
+		}
+		// Ties on poem count are broken by ID
+		assert.Equal(t, []string{"李白", "杜牧", "苏轼", "王维"}, walked)
+	})
+
+	t.Run("before cursor", func(t *testing.T) {
+		authors, _, adjacent, err := repo.PageAuthors(nil, Page{Limit: 2, Before: &Cursor{ID: wangweiID, PoemCount: 0}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"杜牧", "苏轼"}, names(authors))
+		assert.Equal(t, Adjacent{Before: true, After: true}, adjacent)
+	})
+
+	t.Run("dynasty filter with dynasties loaded", func(t *testing.T) {
+		authors, total, adjacent, err := repo.PageAuthors(&tangID, Page{Limit: 10, After: &Cursor{ID: libaiID, PoemCount: 3}})
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		assert.Equal(t, Adjacent{Before: true}, adjacent)
+		assert.Equal(t, []string{"杜牧", "王维"}, names(authors))
+		require.NotNil(t, authors[0].Dynasty)
+		assert.Equal(t, "唐", authors[0].Dynasty.Name)
+	})
+
+	t.Run("after cursor ahead of the first author", func(t *testing.T) {
+		authors, _, adjacent, err := repo.PageAuthors(nil, Page{Limit: 1, After: &Cursor{ID: 0, PoemCount: 10}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"李白"}, names(authors))
+		assert.Equal(t, Adjacent{After: true}, adjacent)
+	})
+
+	t.Run("poem counts follow deletes and reassignments", func(t *testing.T) {
+		require.NoError(t, repo.DeletePoems([]int64{1}))
+		require.NoError(t, db.Table(repo.poemsTable()).Where("id = ?", 2).Update("author_id", wangweiID).Error)
+
+		authors, _, _, err := repo.PageAuthors(nil, Page{Limit: 10})
+		require.NoError(t, err)
+		counts := make(map[string]int)
+		for _, a := range authors {
+			counts[a.Name] = a.PoemCount
+		}
+		assert.Equal(t, map[string]int{"李白": 1, "杜牧": 1, "苏轼": 1, "王维": 1}, counts)
+	})
+}
+
 func TestListAuthorPoems(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewRepository(db)
@@ -203,13 +415,13 @@ func TestListAuthorsWithFilter(t *testing.T) {
 	_ = repo.InsertPoem(&Poem{ID: 31, Title: "诗2", Content: datatypes.JSON([]byte(`["内容"]`)), AuthorID: &dumuID, DynastyID: &tangID})
 	_ = repo.InsertPoem(&Poem{ID: 32, Title: "诗3", Content: datatypes.JSON([]byte(`["内容"]`)), AuthorID: &sushiID, DynastyID: &songID})
 
-	// Note: filter by dynasty test is commented out due to SQL ambiguity bug in ListAuthorsWithFilter
-	// t.Run("filter by dynasty", func(t *testing.T) {
-	// 	authors, count, err := repo.ListAuthorsWithFilter(10, 0, &tangID)
-	// 	require.NoError(t, err)
-	// 	assert.Equal(t, 2, count)
-	// 	assert.Len(t, authors, 2)
-	// })
+	t.Run("filter by dynasty", func(t *testing.T) {
+		authors, count, err := repo.ListAuthorsWithFilter(10, 0, &tangID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Len(t, authors, 2)
+		assert.Equal(t, 1, authors[0].PoemCount)
+	})
 
 	t.Run("no filter", func(t *testing.T) {
 		authors, count, err := repo.ListAuthorsWithFilter(10, 0, nil)
@@ -281,3 +493,591 @@ func TestSearchPoems(t *testing.T) {
 		assert.Equal(t, int64(0), total)
 	})
 }
+
+func TestSearchPoemsWithOptions(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	dynastyID, _ := repo.GetOrCreateDynasty("唐")
+	authorID, _ := repo.GetOrCreateAuthor("李白", dynastyID)
+
+	poems := []*Poem{
+		{ID: 50, Title: "静夜思", Content: datatypes.JSON([]byte(`["床前明月光","疑是地上霜"]`)), AuthorID: &authorID, DynastyID: &dynastyID},
+		{ID: 51, Title: "望庐山瀑布", Content: datatypes.JSON([]byte(`["日照香炉生紫烟"]`)), AuthorID: &authorID, DynastyID: &dynastyID},
+		{ID: 52, Title: "明月光", Content: datatypes.JSON([]byte(`["朝辞白帝彩云间"]`)), AuthorID: &authorID, DynastyID: &dynastyID},
+	}
+	for _, poem := range poems {
+		require.NoError(t, repo.InsertPoem(poem))
+	}
+
+	ids := func(results []SearchResult) []int64 {
+		out := make([]int64, len(results))
+		for i, r := range results {
+			out[i] = r.ID
+		}
+		return out
+	}
+
+	t.Run("ranks title matches first", func(t *testing.T) {
+		for _, query := range []string{"明月光", "明月"} {
+			results, total, err := repo.SearchPoemsWithOptions(query, SearchOptions{Type: "all"})
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), total)
+			assert.Equal(t, []int64{52, 50}, ids(results), query)
+			assert.Greater(t, results[0].Score, results[1].Score, query)
+		}
+	})
+
+	t.Run("sort by id", func(t *testing.T) {
+		results, _, err := repo.SearchPoemsWithOptions("明月光", SearchOptions{Order: SearchOrderID})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{50, 52}, ids(results))
+	})
+
+	t.Run("highlights and snippets", func(t *testing.T) {
+		for _, query := range []string{"明月光", "明月"} {
+			results, _, err := repo.SearchPoemsWithOptions(query, SearchOptions{})
+			require.NoError(t, err)
+			require.Len(t, results, 2)
+
+			marked := HighlightOpen + query + HighlightClose
+			assert.Contains(t, results[0].Highlight, marked, query)
+			assert.Contains(t, results[1].Snippet, marked, query)
+			assert.Equal(t, "静夜思", results[1].Highlight, query)
+		}
+	})
+
+	t.Run("match offsets", func(t *testing.T) {
+		results, _, err := repo.SearchPoemsWithOptions("明月", SearchOptions{})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		assert.Equal(t, []MatchOffset{{Field: "title", Line: 0, Start: 0, End: 2}}, results[0].Matches)
+		assert.Equal(t, []MatchOffset{{Field: "content", Line: 0, Start: 2, End: 4}}, results[1].Matches)
+	})
+
+	t.Run("author search", func(t *testing.T) {
+		results, total, err := repo.SearchPoemsWithOptions("李白", SearchOptions{Type: "author"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, []int64{50, 51, 52}, ids(results))
+		assert.Equal(t, []MatchOffset{{Field: "author", Line: 0, Start: 0, End: 2}}, results[0].Matches)
+	})
+}
+
+func TestSearchPoemsQuerySyntax(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	dynastyID, _ := repo.GetOrCreateDynasty("唐")
+	liBai, _ := repo.GetOrCreateAuthor("李白", dynastyID)
+	duFu, _ := repo.GetOrCreateAuthor("杜甫", dynastyID)
+
+	poems := []*Poem{
+		{ID: 60, Title: "静夜思", Content: datatypes.JSON([]byte(`["床前明月光","疑是地上霜"]`)), AuthorID: &liBai, DynastyID: &dynastyID},
+		{ID: 61, Title: "月夜", Content: datatypes.JSON([]byte(`["今夜鄜州月","闺中只独看"]`)), AuthorID: &duFu, DynastyID: &dynastyID},
+		{ID: 62, Title: "春望", Content: datatypes.JSON([]byte(`["国破山河在","城春草木深"]`)), AuthorID: &duFu, DynastyID: &dynastyID},
+		{ID: 63, Title: "无名", Content: datatypes.JSON([]byte(`["明月 清风"]`))},
+		{ID: 64, Title: "十成", Content: datatypes.JSON([]byte(`["十成_百%"]`))},
+	}
+	for _, poem := range poems {
+		require.NoError(t, repo.InsertPoem(poem))
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		searchType string
+		want       []int64
+	}{
+		{"required terms", "月 杜甫", "all", []int64{61}},
+		{"OR group", "春望 OR 静夜思", "all", []int64{60, 62}},
+		{"excluded term", "月 -杜甫", "all", []int64{60, 63}},
+		{"excluded field", "月 -author:杜甫", "all", []int64{60, 63}},
+		{"title prefix", "title:月", "all", []int64{61}},
+		{"content prefix overrides type", "content:明月", "title", []int64{60, 63}},
+		{"author prefix", "author:杜甫", "all", []int64{61, 62}},
+		{"quoted phrase", `"明月 清风"`, "all", []int64{63}},
+		{"unprefixed terms follow type", "月", "title", []int64{61}},
+		{"percent sign is literal", "%", "all", []int64{64}},
+		{"underscore is literal", "_", "all", []int64{64}},
+		{"backslash is literal", `\`, "all", []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, total, err := repo.SearchPoemsWithOptions(tt.query, SearchOptions{Type: tt.searchType, Order: SearchOrderID})
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.want)), total)
+
+			got := make([]int64, len(results))
+			for i, r := range results {
+				got[i] = r.ID
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("match offsets cover every term", func(t *testing.T) {
+		results, _, err := repo.SearchPoemsWithOptions("明月 OR 霜 author:李白", SearchOptions{})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, []MatchOffset{
+			{Field: "content", Line: 0, Start: 2, End: 4},
+			{Field: "content", Line: 1, Start: 4, End: 5},
+			{Field: "author", Line: 0, Start: 0, End: 2},
+		}, results[0].Matches)
+		assert.Equal(t, "床前<mark>明月</mark>光疑是地上<mark>霜</mark>", results[0].Snippet)
+	})
+
+	t.Run("malformed query", func(t *testing.T) {
+		_, _, err := repo.SearchPoemsWithOptions(`"明月`, SearchOptions{})
+		var syntaxErr *search.SyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+	})
+}
+
+func TestSearchPoemsAcrossScripts(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.migrateTablesForLang(LangHant))
+	hans := NewRepository(db)
+	hant := hans.WithLang(LangHant)
+
+	// Both scripts of each poem share its ID, as the processor writes them
+	for _, repo := range []*Repository{hans, hant} {
+		dynastyID, _ := repo.GetOrCreateDynasty("唐")
+		poems := map[Lang][]*Poem{
+			LangHans: {
+				{ID: 70, Title: "静夜思", Content: datatypes.JSON([]byte(`["床前明月光","疑是地上霜"]`)), DynastyID: &dynastyID},
+				{ID: 71, Title: "秋浦歌", Content: datatypes.JSON([]byte(`["白发三千丈","缘愁似个长"]`)), DynastyID: &dynastyID},
+			},
+			LangHant: {
+				{ID: 70, Title: "靜夜思", Content: datatypes.JSON([]byte(`["床前明月光","疑是地上霜"]`)), DynastyID: &dynastyID},
+				{ID: 71, Title: "秋浦歌", Content: datatypes.JSON([]byte(`["白髮三千丈","緣愁似個長"]`)), DynastyID: &dynastyID},
+			},
+		}[repo.lang]
+		for _, poem := range poems {
+			require.NoError(t, repo.InsertPoem(poem))
+		}
+	}
+
+	tests := []struct {
+		name  string
+		repo  *Repository
+		query string
+		want  []int64
+	}{
+		{"traditional query in simplified table", hans, "靜夜思", []int64{70}},
+		{"simplified query in traditional table", hant, "静夜思", []int64{70}},
+		{"same script", hant, "靜夜思", []int64{70}},
+		{"one-to-many character", hant, "发", []int64{71}},
+		{"excluded term in other script", hant, "三千丈 -白发", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, total, err := tt.repo.SearchPoemsWithOptions(tt.query, SearchOptions{Order: SearchOrderID})
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.want)), total)
+
+			var got []int64
+			for _, r := range results {
+				got = append(got, r.ID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("matches are located in the table's script", func(t *testing.T) {
+		results, _, err := hant.SearchPoemsWithOptions("缘愁 发", SearchOptions{})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, []MatchOffset{
+			{Field: "content", Line: 0, Start: 1, End: 2},
+			{Field: "content", Line: 1, Start: 0, End: 2},
+		}, results[0].Matches)
+		assert.Equal(t, "白<mark>髮</mark>三千丈<mark>緣愁</mark>似個長", results[0].Snippet)
+	})
+}
+
+func TestSearchPoemsAcrossVariants(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.migrateTablesForLang(LangHant))
+	repo := NewRepository(db).WithLang(LangHant)
+
+	// Folded columns are filled as the processor fills them
+	dynastyID, _ := repo.GetOrCreateDynasty("唐")
+	for id, poem := range map[int64][2]string{
+		80: {"何爲", `["衆鳥高飛盡","孤雲獨去閒"]`},
+		81: {"為誰", `["青山橫北郭","白水繞東城"]`},
+	} {
+		var paragraphs []string
+		require.NoError(t, json.Unmarshal([]byte(poem[1]), &paragraphs))
+		require.NoError(t, repo.InsertPoem(&Poem{
+			ID:            id,
+			Title:         poem[0],
+			Content:       datatypes.JSON([]byte(poem[1])),
+			TitleFolded:   classifier.FoldVariants(poem[0]),
+			ContentFolded: classifier.FoldVariants(strings.Join(paragraphs, "")),
+			DynastyID:     &dynastyID,
+		}))
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []int64
+	}{
+		{"variant in text", "爲", []int64{80, 81}},
+		{"folded form in text", "為", []int64{80, 81}},
+		{"variant in content", "眾鳥", []int64{80}},
+		{"field-scoped", "title:爲", []int64{80, 81}},
+		{"excluded variant", "孤雲 -眾", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, total, err := repo.SearchPoemsWithOptions(tt.query, SearchOptions{Order: SearchOrderID})
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.want)), total)
+
+			var got []int64
+			for _, r := range results {
+				got = append(got, r.ID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("original text is displayed", func(t *testing.T) {
+		results, _, err := repo.SearchPoemsWithOptions("眾鳥", SearchOptions{})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "何爲", results[0].Title)
+		assert.Equal(t, "<mark>衆鳥</mark>高飛盡孤雲獨去閒", results[0].Snippet)
+	})
+}
+
+func TestSearchPoemsPinyin(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	dynastyID, _ := repo.GetOrCreateDynasty("唐")
+	texts := []struct {
+		id      int64
+		title   string
+		content []string
+	}{
+		{80, "静夜思", []string{"床前明月光，", "疑是地上霜。"}},
+		{81, "子夜吴歌", []string{"长安一片月，", "万户捣衣声。"}},
+	}
+	for _, text := range texts {
+		content, _ := json.Marshal(text.content)
+		require.NoError(t, repo.InsertPoem(&Poem{
+			ID:            text.id,
+			Title:         text.title,
+			Content:       datatypes.JSON(content),
+			DynastyID:     &dynastyID,
+			TitlePinyin:   pinyin.Index(text.title),
+			ContentPinyin: pinyin.Index(strings.Join(text.content, "\n")),
+		}))
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []int64
+	}{
+		{"without separators", "jingyesi", []int64{80}},
+		{"with spaces", "jing ye si", []int64{80}},
+		{"with tone marks", "jìng yè sī", []int64{80}},
+		{"with tone numbers", "ming2 yue4", []int64{80}},
+		{"common reading of a polyphone", "chang an", []int64{81}},
+		{"other reading of a polyphone", "zhang'an", []int64{81}},
+		{"doesn't span punctuation", "guangyi", nil},
+		{"starts at a syllable", "huang", nil},
+		{"can't start inside one", "ing", nil},
+		{"partial last syllable", "mingyu", []int64{80}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, total, err := repo.SearchPoemsWithOptions(tt.query, SearchOptions{Type: "pinyin", Order: SearchOrderID})
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.want)), total)
+
+			var got []int64
+			for _, r := range results {
+				got = append(got, r.ID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("matches are located in the text", func(t *testing.T) {
+		results, _, err := repo.SearchPoemsWithOptions("jingyesi", SearchOptions{Type: "pinyin"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Greater(t, results[0].Score, 0.0)
+		assert.Equal(t, "<mark>静夜思</mark>", results[0].Highlight)
+		assert.Equal(t, []MatchOffset{{Field: "title", Line: 0, Start: 0, End: 3}}, results[0].Matches)
+
+		results, _, err = repo.SearchPoemsWithOptions("mingyue", SearchOptions{Type: "pinyin"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "床前<mark>明月</mark>光，疑是地上霜。", results[0].Snippet)
+		assert.Equal(t, []MatchOffset{{Field: "content", Line: 0, Start: 2, End: 4}}, results[0].Matches)
+	})
+
+	t.Run("not pinyin", func(t *testing.T) {
+		_, _, err := repo.SearchPoemsWithOptions("静夜思", SearchOptions{Type: "pinyin"})
+		var syntaxErr *search.SyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+	})
+}
+
+func TestPoemStanzas(t *testing.T) {
+	content := datatypes.JSON([]byte(`["明月几时有？","把酒问青天。","转朱阁，低绮户，照无眠。"]`))
+
+	tests := []struct {
+		name    string
+		sizes   string
+		want    [][]string
+		wantErr bool
+	}{
+		{"no breaks", "", [][]string{{"明月几时有？", "把酒问青天。", "转朱阁，低绮户，照无眠。"}}, false},
+		{"two stanzas", "[2,1]", [][]string{{"明月几时有？", "把酒问青天。"}, {"转朱阁，低绮户，照无眠。"}}, false},
+		{"sizes too short", "[2]", nil, true},
+		{"sizes too long", "[2,2]", nil, true},
+		{"empty stanza", "[0,3]", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poem := &Poem{Content: content}
+			if tt.sizes != "" {
+				poem.StanzaSizes = datatypes.JSON([]byte(tt.sizes))
+			}
+
+			got, err := poem.Stanzas()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPoemSource(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	require.NoError(t, repo.InsertPoem(&Poem{
+		ID: 1, Title: "静夜思", Content: datatypes.JSON([]byte(`["床前明月光"]`)),
+		DatasetKey: "tangsong", DatasetName: "全唐诗", SourcePath: "全唐诗/poet.tang.0.json",
+		SourceID: "3ad6d468-7ff1-4a7b-8b24-a27d70d00ed4", SourceIndex: 12,
+	}))
+	require.NoError(t, repo.InsertPoem(&Poem{
+		ID: 2, Title: "关雎", Content: datatypes.JSON([]byte(`["关关雎鸠"]`)),
+		DatasetKey: "shijing", DatasetName: "诗经", SourcePath: "诗经/shijing.json",
+	}))
+
+	poem, err := repo.GetPoemByID("1")
+	require.NoError(t, err)
+	recordID := "3ad6d468-7ff1-4a7b-8b24-a27d70d00ed4"
+	assert.Equal(t, &Source{
+		Dataset:     "tangsong",
+		DatasetName: "全唐诗",
+		File:        "全唐诗/poet.tang.0.json",
+		RecordID:    &recordID,
+		Index:       12,
+	}, poem.Source())
+
+	// Records without an upstream id are traced by their index alone
+	poem, err = repo.GetPoemByID("2")
+	require.NoError(t, err)
+	assert.Nil(t, poem.Source().RecordID)
+	assert.Equal(t, 0, poem.Source().Index)
+}
+
+func TestGetPoemVariants(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	dynastyID, _ := repo.GetOrCreateDynasty("唐")
+	authorID, _ := repo.GetOrCreateAuthor("李白", dynastyID)
+	clusterID := int64(20)
+	for _, poem := range []*Poem{
+		{ID: 20, Title: "静夜思", Content: datatypes.JSON([]byte(`["床前明月光"]`)), ClusterID: &clusterID},
+		{ID: 21, Title: "春晓", Content: datatypes.JSON([]byte(`["春眠不觉晓"]`))},
+		{ID: 22, Title: "夜思", Content: datatypes.JSON([]byte(`["床前看月光"]`)), ClusterID: &clusterID, AuthorID: &authorID},
+		{ID: 23, Title: "夜思其二", Content: datatypes.JSON([]byte(`["床前明月光。"]`)), ClusterID: &clusterID},
+	} {
+		require.NoError(t, repo.InsertPoem(poem))
+	}
+
+	poem, err := repo.GetPoemByID("20")
+	require.NoError(t, err)
+	variants, err := repo.GetPoemVariants(poem)
+	require.NoError(t, err)
+	require.Len(t, variants, 2)
+	assert.Equal(t, int64(22), variants[0].ID)
+	assert.Equal(t, int64(23), variants[1].ID)
+	require.NotNil(t, variants[0].Author)
+	assert.Equal(t, "李白", variants[0].Author.Name)
+
+	poem, err = repo.GetPoemByID("21")
+	require.NoError(t, err)
+	variants, err = repo.GetPoemVariants(poem)
+	require.NoError(t, err)
+	assert.Empty(t, variants)
+}
+
+func TestIncrementalWrites(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	dynastyID, _ := repo.GetOrCreateDynasty("唐")
+	libai, _ := repo.GetOrCreateAuthor("李白", dynastyID)
+	mengHaoran, _ := repo.GetOrCreateAuthor("孟浩然", dynastyID)
+	for _, poem := range []*Poem{
+		{ID: 1, Title: "静夜思", Content: datatypes.JSON([]byte(`["床前明月光"]`)), AuthorID: &libai, SourceKey: "tang:a", SourceHash: "1", SourcePath: "tang/poet.tang.0.json", SourceIndex: 3},
+		{ID: 2, Title: "春晓", Content: datatypes.JSON([]byte(`["春眠不觉晓"]`)), AuthorID: &mengHaoran, SourceKey: "tang:b", SourceHash: "2"},
+		{ID: 3, Title: "夜思", Content: datatypes.JSON([]byte(`["床前看月光"]`)), AuthorID: &libai, SourceKey: "tang:c", SourceHash: "3"},
+	} {
+		require.NoError(t, repo.InsertPoem(poem))
+	}
+
+	sources, err := repo.ListPoemSources()
+	require.NoError(t, err)
+	assert.Equal(t, []PoemSource{
+		{ID: 1, SourceKey: "tang:a", SourceHash: "1", SourcePath: "tang/poet.tang.0.json", SourceIndex: 3},
+		{ID: 2, SourceKey: "tang:b", SourceHash: "2"},
+		{ID: 3, SourceKey: "tang:c", SourceHash: "3"},
+	}, sources)
+
+	// Deleting a poem that doesn't exist is fine
+	require.NoError(t, repo.DeletePoems([]int64{2, 99}))
+	count, err := repo.CountPoems()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	deleted, err := repo.DeleteUnusedAuthors()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	_, err = repo.GetAuthorByID(mengHaoran)
+	assert.Error(t, err)
+
+	poems, err := repo.ListPoemTexts(0, 10)
+	require.NoError(t, err)
+	require.Len(t, poems, 2)
+	page, err := repo.ListPoemTexts(1, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, int64(3), page[0].ID)
+	assert.Nil(t, poems[0].ClusterID)
+	clusterID := int64(1)
+	poems[0].ClusterID, poems[1].ClusterID = &clusterID, &clusterID
+	require.NoError(t, repo.UpdateClusterIDs(poems))
+
+	poem, err := repo.GetPoemByID("3")
+	require.NoError(t, err)
+	require.NotNil(t, poem.ClusterID)
+	assert.Equal(t, clusterID, *poem.ClusterID)
+	assert.Equal(t, "夜思", poem.Title)
+
+	require.NoError(t, repo.UpdateSourcePositions([]PoemSource{{ID: 1, SourcePath: "tang/poet.tang.0.json", SourceIndex: 4}}))
+	poem, err = repo.GetPoemByID("1")
+	require.NoError(t, err)
+	assert.Equal(t, 4, poem.SourceIndex)
+	assert.Equal(t, "1", poem.SourceHash)
+}
+
+func TestSavePoems(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	poem := func(id int64, title, content, key, hash string) *Poem {
+		return &Poem{
+			ID:          id,
+			Title:       title,
+			Content:     datatypes.JSON([]byte(`["` + content + `"]`)),
+			ContentHash: content,
+			SourceKey:   key,
+			SourceHash:  hash,
+		}
+	}
+	require.NoError(t, repo.BatchInsertPoems([]*Poem{
+		poem(1, "静夜思", "床前明月光", "tang:a", "1"),
+		poem(2, "春晓", "春眠不觉晓", "tang:b", "2"),
+		poem(3, "静夜思", "床前明月光", "tang:c", "3"),
+	}, 10))
+	recorded, err := repo.RecordDuplicates([]*Poem{poem(2, "春晓", "春眠不觉晓", "tang:b", "2"), poem(3, "静夜思", "床前明月光", "tang:c", "3")})
+	require.NoError(t, err)
+	assert.Equal(t, 1, recorded)
+
+	sources, err := repo.ListPoemSources()
+	require.NoError(t, err)
+	assert.Equal(t, []PoemSource{
+		{ID: 1, SourceKey: "tang:a", SourceHash: "1"},
+		{ID: 2, SourceKey: "tang:b", SourceHash: "2"},
+		{SourceKey: "tang:c", SourceHash: "3", DuplicateOf: 1},
+	}, sources)
+
+	unsaved, err := repo.SavePoems([]*Poem{
+		poem(2, "春晓", "春眠不觉晓处处闻啼鸟", "tang:b", "2b"), // Changed
+		poem(1, "春晓", "春眠不觉晓处处闻啼鸟", "tang:a", "1b"), // Changed into a duplicate of 2
+		poem(4, "春晓", "春眠不觉晓处处闻啼鸟", "tang:d", "4"),  // Added as a duplicate of 2
+		poem(3, "静夜思", "床前看月光", "tang:c", "3b"),     // A duplicate no longer
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []PoemSource{
+		{ID: 1, SourceKey: "tang:a", SourceHash: "1b", DuplicateOf: 2},
+		{SourceKey: "tang:d", SourceHash: "4", DuplicateOf: 2},
+	}, unsaved)
+
+	stored, err := repo.GetPoemByID("1")
+	require.NoError(t, err)
+	assert.Equal(t, "静夜思", stored.Title)
+	stored, err = repo.GetPoemByID("2")
+	require.NoError(t, err)
+	assert.JSONEq(t, `["春眠不觉晓处处闻啼鸟"]`, string(stored.Content))
+
+	sources, err = repo.ListPoemSources()
+	require.NoError(t, err)
+	assert.Equal(t, []PoemSource{
+		{ID: 1, SourceKey: "tang:a", SourceHash: "1"},
+		{ID: 2, SourceKey: "tang:b", SourceHash: "2b"},
+		{ID: 3, SourceKey: "tang:c", SourceHash: "3b"},
+		{SourceKey: "tang:d", SourceHash: "4", DuplicateOf: 2},
+	}, sources)
+
+	require.NoError(t, repo.DeleteDuplicates([]string{"tang:d"}))
+	sources, err = repo.ListPoemSources()
+	require.NoError(t, err)
+	assert.Len(t, sources, 3)
+}
+
+func TestUpdateAuthorDescriptions(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	tang, _ := repo.GetOrCreateDynasty("唐")
+	song, _ := repo.GetOrCreateDynasty("宋")
+	libai, _ := repo.GetOrCreateAuthor("李白", tang)
+	suShi, _ := repo.GetOrCreateAuthor("苏轼", song)
+
+	updated, err := repo.UpdateAuthorDescriptions([]AuthorDescription{
+		{Name: "李白", Dynasty: "唐", Description: "李白字太白"},
+		{Name: "苏轼", Dynasty: "唐", Description: "另一位苏轼"}, // Wrong dynasty
+		{Name: "王维", Dynasty: "唐", Description: "王维字摩诘"}, // Unknown author
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated)
+
+	author, err := repo.GetAuthorByID(libai)
+	require.NoError(t, err)
+	require.NotNil(t, author.Description)
+	assert.Equal(t, "李白字太白", *author.Description)
+
+	author, err = repo.GetAuthorByID(suShi)
+	require.NoError(t, err)
+	assert.Nil(t, author.Description)
+}
@@ -2,7 +2,9 @@ package database
 
 import (
 	"crypto/rand"
+	"hash/fnv"
 	"math/big"
+	"slices"
 	"strconv"
 
 	"gorm.io/gorm"
@@ -53,10 +55,37 @@ func (r *Repository) GetPoemByID(id string) (*Poem, error) {
 		}
 	}
 
+	// Load ci pai
+	if poem.CiPaiID != nil {
+		var ciPai CiPai
+		if err := r.db.Table(r.ciPaiTable()).First(&ciPai, *poem.CiPaiID).Error; err == nil {
+			poem.CiPai = &ciPai
+		}
+	}
+
 	return &poem, nil
 }
 
-// loadPoemRelations loads Author, Dynasty, and Type for a slice of poems
+// GetPoemVariants returns the other versions of poem, the poems clustered with
+// it as near duplicates when the data was built, ordered by ID
+func (r *Repository) GetPoemVariants(poem *Poem) ([]Poem, error) {
+	if poem.ClusterID == nil {
+		return []Poem{}, nil
+	}
+
+	var poems []Poem
+	err := r.db.Table(r.poemsTable()).
+		Where("cluster_id = ? AND id != ?", *poem.ClusterID, poem.ID).
+		Order("id").
+		Find(&poems).Error
+	if err != nil {
+		return nil, err
+	}
+	r.loadPoemRelations(poems)
+	return poems, nil
+}
+
+// loadPoemRelations loads Author, Dynasty, Type and CiPai for a slice of poems
 func (r *Repository) loadPoemRelations(poems []Poem) {
 	if len(poems) == 0 {
 		return
@@ -66,6 +95,7 @@ func (r *Repository) loadPoemRelations(poems []Poem) {
 	authorIDs := make(map[int64]bool)
 	dynastyIDs := make(map[int64]bool)
 	typeIDs := make(map[int64]bool)
+	ciPaiIDs := make(map[int64]bool)
 
 	for _, p := range poems {
 		if p.AuthorID != nil {
@@ -77,6 +107,9 @@ func (r *Repository) loadPoemRelations(poems []Poem) {
 		if p.TypeID != nil {
 			typeIDs[*p.TypeID] = true
 		}
+		if p.CiPaiID != nil {
+			ciPaiIDs[*p.CiPaiID] = true
+		}
 	}
 
 	// Load authors
@@ -125,6 +158,20 @@ func (r *Repository) loadPoemRelations(poems []Poem) {
 		}
 	}
 
+	// Load ci pai
+	ciPais := make(map[int64]*CiPai)
+	if len(ciPaiIDs) > 0 {
+		ids := make([]int64, 0, len(ciPaiIDs))
+		for id := range ciPaiIDs {
+			ids = append(ids, id)
+		}
+		var ciPaiList []CiPai
+		r.db.Table(r.ciPaiTable()).Where("id IN ?", ids).Find(&ciPaiList)
+		for i := range ciPaiList {
+			ciPais[ciPaiList[i].ID] = &ciPaiList[i]
+		}
+	}
+
 	// Assign relations to poems
 	for i := range poems {
 		if poems[i].AuthorID != nil {
@@ -147,6 +194,11 @@ func (r *Repository) loadPoemRelations(poems []Poem) {
 				poems[i].Type = ptype
 			}
 		}
+		if poems[i].CiPaiID != nil {
+			if ciPai, ok := ciPais[*poems[i].CiPaiID]; ok {
+				poems[i].CiPai = ciPai
+			}
+		}
 	}
 }
 
@@ -165,32 +217,158 @@ func (r *Repository) ListPoems(limit, offset int) ([]Poem, error) {
 	return poems, nil
 }
 
+// PoemFilter narrows a poem query by dynasty, author, poetry type, 词牌, 宫调,
+// 曲牌, meter status (see classifier.MeterStatuses), rhyme group (see
+// classifier.RhymeGroup) and upstream dataset.
+// Values within one field are ORed together; non-empty fields are ANDed.
+type PoemFilter struct {
+	DynastyIDs    []int64
+	AuthorIDs     []int64
+	TypeIDs       []int64
+	CiPaiIDs      []int64
+	GongDiaos     []string
+	QuPais        []string
+	MeterStatuses []string
+	RhymeGroups   []string
+	Datasets      []string
+}
+
+// apply adds the filter's WHERE clauses to q. A type matches its subtypes
+// too (see poetryTypeSubtypes).
+func (f PoemFilter) apply(q *gorm.DB) *gorm.DB {
+	if len(f.DynastyIDs) > 0 {
+		q = q.Where("dynasty_id IN ?", f.DynastyIDs)
+	}
+	if len(f.AuthorIDs) > 0 {
+		q = q.Where("author_id IN ?", f.AuthorIDs)
+	}
+	if len(f.TypeIDs) > 0 {
+		q = q.Where("type_id IN ?", withSubtypes(f.TypeIDs))
+	}
+	if len(f.CiPaiIDs) > 0 {
+		q = q.Where("ci_pai_id IN ?", f.CiPaiIDs)
+	}
+	if len(f.GongDiaos) > 0 {
+		q = q.Where("gong_diao IN ?", f.GongDiaos)
+	}
+	if len(f.QuPais) > 0 {
+		q = q.Where("qu_pai IN ?", f.QuPais)
+	}
+	if len(f.MeterStatuses) > 0 {
+		q = q.Where("meter_status IN ?", f.MeterStatuses)
+	}
+	if len(f.RhymeGroups) > 0 {
+		q = q.Where("EXISTS (SELECT 1 FROM json_each(rhyme_detail, '$.groups') WHERE value IN ?)", f.RhymeGroups)
+	}
+	if len(f.Datasets) > 0 {
+		q = q.Where("dataset_key IN ?", f.Datasets)
+	}
+	return q
+}
+
 // ListPoemsWithFilter returns a paginated list of poems with optional filters
 func (r *Repository) ListPoemsWithFilter(limit, offset int, dynastyID, authorID, typeID *int64) ([]Poem, int, error) {
-	query := r.db.Table(r.poemsTable())
-
-	// Apply filters
+	var filter PoemFilter
 	if dynastyID != nil {
-		query = query.Where("dynasty_id = ?", *dynastyID)
+		filter.DynastyIDs = []int64{*dynastyID}
 	}
 	if authorID != nil {
-		query = query.Where("author_id = ?", *authorID)
+		filter.AuthorIDs = []int64{*authorID}
 	}
 	if typeID != nil {
-		query = query.Where("type_id = ?", *typeID)
+		filter.TypeIDs = []int64{*typeID}
+	}
+
+	return r.listPoems(filter, "id DESC", limit, offset)
+}
+
+// ListPoemsByFilter returns a page of poems matching filter along with the total count.
+// Poems are ordered by ID. IDs are hashes of the poems' sources, so the order
+// follows neither the corpus nor time, but it is the same in every request and
+// every build, which keeps pages stable.
+func (r *Repository) ListPoemsByFilter(filter PoemFilter, limit, offset int) ([]Poem, int, error) {
+	return r.listPoems(filter, "id ASC", limit, offset)
+}
+
+// PagePoemsByFilter returns a window of the poems matching filter, in ID order
+// (descending for page.Desc), along with the total count. Cursor pages seek on
+// the primary key, so their cost does not grow with depth. adjacent reports
+// whether further poems exist on either side of the window.
+func (r *Repository) PagePoemsByFilter(filter PoemFilter, page Page) (poems []Poem, total int, adjacent Adjacent, err error) {
+	var totalCount int64
+	if err := filter.apply(r.db.Table(r.poemsTable())).Count(&totalCount).Error; err != nil {
+		return nil, 0, adjacent, err
+	}
+
+	// Comparisons and orders that move forward and back along the listing
+	forward, back := ">", "<"
+	forwardOrder, backOrder := "id ASC", "id DESC"
+	if page.Desc {
+		forward, back = back, forward
+		forwardOrder, backOrder = backOrder, forwardOrder
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	query := filter.apply(r.db.Table(r.poemsTable())).Limit(page.Limit + 1)
+	switch {
+	case page.Before != nil:
+		query = query.Where("id "+back+" ?", page.Before.ID).Order(backOrder)
+	case page.After != nil:
+		query = query.Where("id "+forward+" ?", page.After.ID).Order(forwardOrder)
+	default:
+		query = query.Offset(page.Offset).Order(forwardOrder)
+	}
+	if err := query.Find(&poems).Error; err != nil {
+		return nil, 0, adjacent, err
+	}
+
+	more := len(poems) > page.Limit
+	if more {
+		poems = poems[:page.Limit]
+	}
+
+	// The other side of a cursor has poems if any match at or past it
+	switch {
+	case page.Before != nil:
+		slices.Reverse(poems)
+		adjacent.Before = more
+		adjacent.After, err = exists(filter.apply(r.db.Table(r.poemsTable())).Where("id "+forward+"= ?", page.Before.ID))
+	case page.After != nil:
+		adjacent.After = more
+		adjacent.Before, err = exists(filter.apply(r.db.Table(r.poemsTable())).Where("id "+back+"= ?", page.After.ID))
+	default:
+		adjacent.After = more
+		adjacent.Before = page.Offset > 0 && totalCount > 0
+	}
+	if err != nil {
+		return nil, 0, adjacent, err
 	}
 
+	r.loadPoemRelations(poems)
+	return poems, int(totalCount), adjacent, nil
+}
+
+// exists reports whether query matches any row
+func exists(query *gorm.DB) (bool, error) {
+	var ids []int64
+	err := query.Limit(1).Pluck("id", &ids).Error
+	return len(ids) > 0, err
+}
+
+// listPoems returns a page of poems matching filter in the given order, plus
+// the total number of matches.
+func (r *Repository) listPoems(filter PoemFilter, order string, limit, offset int) ([]Poem, int, error) {
 	// Get total count
 	var totalCount int64
-	if err := query.Count(&totalCount).Error; err != nil {
+	if err := filter.apply(r.db.Table(r.poemsTable())).Count(&totalCount).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get paginated results
 	var poems []Poem
-	err := query.
+	err := filter.apply(r.db.Table(r.poemsTable())).
+		Order(order).
 		Limit(limit).Offset(offset).
-		Order("id DESC").
 		Find(&poems).Error
 	if err != nil {
 		return nil, 0, err
@@ -203,27 +381,26 @@ func (r *Repository) ListPoemsWithFilter(limit, offset int, dynastyID, authorID,
 
 // GetRandomPoem returns a random poem with optional filters
 // Supports filtering by multiple poetry types (OR logic)
-// Uses COUNT + random OFFSET for uniform distribution across filtered results
 func (r *Repository) GetRandomPoem(dynastyID, authorID *int64, typeIDs []int64) (*Poem, error) {
-	poemTable := r.poemsTable()
-
-	// Helper to apply filters to a query
-	applyFilters := func(q *gorm.DB) *gorm.DB {
-		if dynastyID != nil {
-			q = q.Where("dynasty_id = ?", *dynastyID)
-		}
-		if authorID != nil {
-			q = q.Where("author_id = ?", *authorID)
-		}
-		if len(typeIDs) > 0 {
-			q = q.Where("type_id IN ?", typeIDs)
-		}
-		return q
+	filter := PoemFilter{TypeIDs: typeIDs}
+	if dynastyID != nil {
+		filter.DynastyIDs = []int64{*dynastyID}
 	}
+	if authorID != nil {
+		filter.AuthorIDs = []int64{*authorID}
+	}
+
+	return r.GetRandomPoemByFilter(filter)
+}
+
+// GetRandomPoemByFilter returns a random poem matching filter
+// Uses COUNT + random OFFSET for uniform distribution across filtered results
+func (r *Repository) GetRandomPoemByFilter(filter PoemFilter) (*Poem, error) {
+	poemTable := r.poemsTable()
 
 	// Count matching poems
 	var count int64
-	if err := applyFilters(r.db.Table(poemTable)).Count(&count).Error; err != nil || count == 0 {
+	if err := filter.apply(r.db.Table(poemTable)).Count(&count).Error; err != nil || count == 0 {
 		return nil, gorm.ErrRecordNotFound
 	}
 
@@ -236,7 +413,7 @@ func (r *Repository) GetRandomPoem(dynastyID, authorID *int64, typeIDs []int64)
 
 	// Fetch the poem at the random offset
 	var poem Poem
-	err = applyFilters(r.db.Table(poemTable)).Order("id ASC").Offset(offset).Limit(1).First(&poem).Error
+	err = filter.apply(r.db.Table(poemTable)).Order("id ASC").Offset(offset).Limit(1).First(&poem).Error
 	if err != nil {
 		return nil, err
 	}
@@ -245,6 +422,50 @@ func (r *Repository) GetRandomPoem(dynastyID, authorID *int64, typeIDs []int64)
 	return r.GetPoemByID(strconv.FormatInt(poem.ID, 10))
 }
 
+// GetDailyPoemByFilter returns the poem of the day for day (YYYY-MM-DD)
+// among the poems matching filter. The pick is a hash of day rather than a
+// random draw: the first poem whose ID is at or after the point the hash gives
+// in the ID space, wrapping around to the lowest ID. Every request for the
+// same day and filter gets the same poem, and as poem IDs are hashes too, a
+// poem added or removed by an incremental update only changes the pick if it
+// is the pick or lands between the point and the pick.
+//
+// The pick is made in the simplified table and then loaded in this
+// repository's language, so both languages get the same poem even if their
+// tables differ. filter is evaluated against this repository's tables, whose
+// author and dynasty IDs it holds.
+func (r *Repository) GetDailyPoemByFilter(day string, filter PoemFilter) (*Poem, error) {
+	h := fnv.New64a()
+	h.Write([]byte(day))
+	point := int64(h.Sum64() & (1<<PoemIDBits - 1))
+
+	candidates := func() *gorm.DB {
+		hansTable := poemsTable(LangHans)
+		q := r.db.Table(hansTable)
+		if r.lang == LangHans {
+			return filter.apply(q)
+		}
+		matching := filter.apply(r.db.Table(r.poemsTable() + " AS matching")).
+			Select("1").Where("matching.id = " + hansTable + ".id")
+		return q.Where("EXISTS (?)", matching)
+	}
+
+	var ids []int64
+	err := candidates().Where("id >= ?", point).Order("id ASC").Limit(1).Pluck("id", &ids).Error
+	if err == nil && len(ids) == 0 {
+		err = candidates().Order("id ASC").Limit(1).Pluck("id", &ids).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	// Load the full poem by ID with all relations
+	return r.GetPoemByID(strconv.FormatInt(ids[0], 10))
+}
+
 // GetRandomPoemByChar returns a random poem whose content contains the given
 // character (for 飞花令-style games). Unlike GetRandomPoem, this is intentionally
 // not combinable with author/type/dynasty filters: the FTS join it uses to locate
@@ -255,11 +476,11 @@ func (r *Repository) GetRandomPoem(dynastyID, authorID *int64, typeIDs []int64)
 func (r *Repository) GetRandomPoemByChar(char string) (*Poem, error) {
 	poemTable := r.poemsTable()
 	ftsTable := r.poemsFtsTable()
-	pattern := "%" + char + "%"
+	condition, pattern := containsCondition(ftsTable+".content_text", char)
 
 	matches := func(q *gorm.DB) *gorm.DB {
 		return q.Joins("JOIN "+ftsTable+" ON "+ftsTable+".rowid = "+poemTable+".id").
-			Where(ftsTable+".content_text LIKE ?", pattern)
+			Where(condition, pattern)
 	}
 
 	// Count matching poems
@@ -310,106 +531,3 @@ func (r *Repository) ListAuthorPoems(authorID int64, limit, offset int) ([]Poem,
 	r.loadPoemRelations(poems)
 	return poems, int(totalCount), nil
 }
-
-// SearchPoems searches for poems using the FTS5 trigram index built over title
-// and content (see migrateFtsForLang). The trigram tokenizer lets LIKE '%...%'
-// queries run against the FTS index instead of scanning the poems table, while
-// keeping the same substring-match semantics (including single/double-character
-// CJK queries, which classic FTS5 MATCH can't handle).
-// searchType can be: "all", "title", "content", "author"
-func (r *Repository) SearchPoems(query string, searchType string, page, pageSize int) ([]Poem, int64, error) {
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 {
-		pageSize = 20
-	}
-
-	offset := (page - 1) * pageSize
-	pattern := "%" + query + "%"
-	poemTable := r.poemsTable()
-	authorTable := r.authorsTable()
-	ftsTable := r.poemsFtsTable()
-	ftsJoin := "JOIN " + ftsTable + " ON " + ftsTable + ".rowid = " + poemTable + ".id"
-
-	var poems []Poem
-	var total int64
-
-	switch searchType {
-	case "title":
-		// Search in title only, via the FTS trigram index
-		r.db.Table(poemTable).
-			Joins(ftsJoin).
-			Where(ftsTable+".title LIKE ?", pattern).
-			Count(&total)
-		err := r.db.Table(poemTable).
-			Select(poemTable+".*").
-			Joins(ftsJoin).
-			Where(ftsTable+".title LIKE ?", pattern).
-			Order(poemTable + ".id").
-			Limit(pageSize).Offset(offset).
-			Find(&poems).Error
-		if err != nil {
-			return nil, 0, err
-		}
-
-	case "content":
-		// Search in content only, via the FTS trigram index
-		r.db.Table(poemTable).
-			Joins(ftsJoin).
-			Where(ftsTable+".content_text LIKE ?", pattern).
-			Count(&total)
-		err := r.db.Table(poemTable).
-			Select(poemTable+".*").
-			Joins(ftsJoin).
-			Where(ftsTable+".content_text LIKE ?", pattern).
-			Order(poemTable + ".id").
-			Limit(pageSize).Offset(offset).
-			Find(&poems).Error
-		if err != nil {
-			return nil, 0, err
-		}
-
-	case "author":
-		// Search in author name (small table, plain LIKE is fast enough)
-		r.db.Table(poemTable).
-			Joins("JOIN "+authorTable+" ON "+poemTable+".author_id = "+authorTable+".id").
-			Where(authorTable+".name LIKE ?", pattern).
-			Count(&total)
-		err := r.db.Table(poemTable).
-			Select(poemTable+".*").
-			Joins("JOIN "+authorTable+" ON "+poemTable+".author_id = "+authorTable+".id").
-			Where(authorTable+".name LIKE ?", pattern).
-			Order(poemTable + ".id").
-			Limit(pageSize).Offset(offset).
-			Find(&poems).Error
-		if err != nil {
-			return nil, 0, err
-		}
-
-	default: // "all"
-		// Search in title, content (via FTS) and author name
-		r.db.Table(poemTable).
-			Joins(ftsJoin).
-			Joins("LEFT JOIN "+authorTable+" ON "+poemTable+".author_id = "+authorTable+".id").
-			Where(ftsTable+".title LIKE ? OR "+ftsTable+".content_text LIKE ? OR "+authorTable+".name LIKE ?",
-				pattern, pattern, pattern).
-			Count(&total)
-		err := r.db.Table(poemTable).
-			Select(poemTable+".*").
-			Joins(ftsJoin).
-			Joins("LEFT JOIN "+authorTable+" ON "+poemTable+".author_id = "+authorTable+".id").
-			Where(ftsTable+".title LIKE ? OR "+ftsTable+".content_text LIKE ? OR "+authorTable+".name LIKE ?",
-				pattern, pattern, pattern).
-			Order(poemTable + ".id").
-			Limit(pageSize).Offset(offset).
-			Find(&poems).Error
-		if err != nil {
-			return nil, 0, err
-		}
-	}
-
-	// Load relations
-	r.loadPoemRelations(poems)
-	return poems, total, nil
-}
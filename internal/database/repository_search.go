@@ -0,0 +1,762 @@
+package database
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"github.com/palemoky/chinese-poetry-api/internal/classifier"
+	"github.com/palemoky/chinese-poetry-api/internal/pinyin"
+	"github.com/palemoky/chinese-poetry-api/internal/search"
+)
+
+// Full-text search methods for the Repository
+
+// SearchOrder selects how search results are ordered
+type SearchOrder string
+
+const (
+	// SearchOrderRelevance orders results by score, best match first (default)
+	SearchOrderRelevance SearchOrder = "relevance"
+	// SearchOrderID orders results by poem ID
+	SearchOrderID SearchOrder = "id"
+)
+
+// IsValid reports whether o is a known search order
+func (o SearchOrder) IsValid() bool {
+	switch o {
+	case SearchOrderRelevance, SearchOrderID:
+		return true
+	}
+	return false
+}
+
+// Markers wrapped around matched text in SearchResult highlights and snippets
+const (
+	HighlightOpen  = "<mark>"
+	HighlightClose = "</mark>"
+)
+
+// snippetRunes is the approximate length of a content snippet
+const snippetRunes = 16
+
+// SearchOptions controls a full-text search
+type SearchOptions struct {
+	Type      string      // Fields searched by unprefixed terms: "all" (default), "title", "content", "author" or "pinyin"
+	Order     SearchOrder // SearchOrderRelevance (default) or SearchOrderID
+	CiPaiIDs  []int64     // Keep poems written to one of these 词牌
+	GongDiaos []string    // Keep 元曲 in one of these 宫调
+	QuPais    []string    // Keep 元曲 written to one of these 曲牌
+	Page      int
+	PageSize  int
+}
+
+// MatchOffset locates one occurrence of the query in a poem. Offsets count
+// runes, not bytes; End is exclusive.
+type MatchOffset struct {
+	Field string `json:"field"` // "title", "content" or "author"
+	Line  int    `json:"line"`  // Paragraph index for content matches, 0 otherwise
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// SearchResult is a poem found by a search, with why it matched
+type SearchResult struct {
+	Poem
+	Score     float64       `json:"score"`     // Higher is better; only comparable within one search
+	Highlight string        `json:"highlight"` // Title with matches wrapped in HighlightOpen/HighlightClose
+	Snippet   string        `json:"snippet"`   // Fragment of the content around the best match
+	Matches   []MatchOffset `json:"matches"`
+}
+
+// SearchPoems searches for poems using the FTS5 trigram index built over title
+// and content (see migrateFtsForLang), ranked by relevance.
+// searchType can be: "all", "title", "content", "author"
+// See SearchPoemsWithOptions for scores, highlights and match offsets.
+func (r *Repository) SearchPoems(query string, searchType string, page, pageSize int) ([]Poem, int64, error) {
+	results, total, err := r.SearchPoemsWithOptions(query, SearchOptions{
+		Type:     searchType,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	poems := make([]Poem, len(results))
+	for i := range results {
+		poems[i] = results[i].Poem
+	}
+	return poems, total, nil
+}
+
+// SearchPoemsWithOptions searches for poems using the FTS5 trigram index built
+// over title and content (see migrateFtsForLang). The trigram tokenizer lets
+// LIKE '%...%' queries run against the FTS index instead of scanning the poems
+// table, while keeping the same substring-match semantics (including
+// single/double-character CJK queries, which classic FTS5 MATCH can't handle).
+//
+// query uses the syntax of search.Parse: required terms, OR groups, -excluded
+// terms, quoted phrases and title:/content:/author: prefixes. Terms without a
+// prefix are matched against the fields opts.Type covers. A malformed query
+// returns a *search.SyntaxError.
+//
+// Terms are matched whichever script they were typed in (see termVariants).
+//
+// With opts.Type "pinyin" the whole query is instead read as pinyin (see
+// search.ParsePinyin) and matched against the pinyin index of the title and
+// content that the processor builds (see pinyin.Index).
+//
+// LIKE decides which poems match; the score only orders them. When every
+// title/content term has three or more characters, poems are scored with
+// FTS5's bm25(), weighting title hits above content hits. Shorter terms can't
+// be run through MATCH with the trigram tokenizer, so they are scored by
+// counting occurrences with the same weights. Queries with only author terms
+// aren't scored and always come back in ID order.
+func (r *Repository) SearchPoemsWithOptions(query string, opts SearchOptions) ([]SearchResult, int64, error) {
+	parsed, err := parseSearchQuery(query, opts.Type)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page, pageSize := opts.Page, opts.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+	poemTable := r.poemsTable()
+	authorTable := r.authorsTable()
+	ftsTable := r.poemsFtsTable()
+	ftsJoin := "JOIN " + ftsTable + " ON " + ftsTable + ".rowid = " + poemTable + ".id"
+	authorJoin := "LEFT JOIN " + authorTable + " ON " + poemTable + ".author_id = " + authorTable + ".id"
+	condition, args := r.searchCondition(parsed, opts.Type)
+
+	filter := func(q *gorm.DB) *gorm.DB {
+		// Title and content via the FTS trigram index, author name with plain
+		// LIKE (small table, fast enough)
+		q = q.Joins(ftsJoin).Joins(authorJoin)
+		if r.lang == LangHant {
+			// Poems share IDs across languages, so the simplified index of the
+			// same poem can be joined to match folded terms (see searchCondition)
+			q = q.Joins("LEFT JOIN " + poemsFtsTable(LangHans) + " AS " + foldFtsAlias + " ON " + foldFtsAlias + ".rowid = " + poemTable + ".id")
+		}
+		if len(opts.CiPaiIDs) > 0 {
+			q = q.Where(poemTable+".ci_pai_id IN ?", opts.CiPaiIDs)
+		}
+		if len(opts.GongDiaos) > 0 {
+			q = q.Where(poemTable+".gong_diao IN ?", opts.GongDiaos)
+		}
+		if len(opts.QuPais) > 0 {
+			q = q.Where(poemTable+".qu_pai IN ?", opts.QuPais)
+		}
+		return q.Where(condition, args...)
+	}
+
+	var total int64
+	if err := filter(r.db.Table(poemTable)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		Poem
+		Score float64 `gorm:"column:score"`
+	}
+	terms := r.rankedTerms(parsed, opts.Type)
+	q := filter(r.db.Table(poemTable))
+	if len(terms) == 0 {
+		q = q.Select(poemTable + ".*, 0 AS score").Order(poemTable + ".id")
+	} else {
+		q = q.Select(poemTable+".*, COALESCE(search_rank.score, 0) AS score").
+			Joins("LEFT JOIN (?) AS search_rank ON search_rank.rank_id = "+poemTable+".id", r.searchRank(terms))
+		if opts.Order == SearchOrderID {
+			q = q.Order(poemTable + ".id")
+		} else {
+			q = q.Order("score DESC, " + poemTable + ".id")
+		}
+	}
+	if err := q.Limit(pageSize).Offset(offset).Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	poems := make([]Poem, len(rows))
+	for i := range rows {
+		poems[i] = rows[i].Poem
+	}
+	r.loadPoemRelations(poems)
+
+	fragments, err := r.searchFragments(terms, poems)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]SearchResult, len(poems))
+	for i := range poems {
+		frag, ok := fragments[poems[i].ID]
+		if !ok {
+			frag = fallbackFragments(terms, &poems[i])
+		}
+		results[i] = SearchResult{
+			Poem:      poems[i],
+			Score:     rows[i].Score,
+			Highlight: frag.Highlight,
+			Snippet:   frag.Snippet,
+			Matches:   matchOffsets(parsed, opts.Type, &poems[i]),
+		}
+	}
+	return results, total, nil
+}
+
+// Pseudo-fields matching a term against the pinyin index of a poem
+const (
+	fieldTitlePinyin   search.Field = "title_pinyin"
+	fieldContentPinyin search.Field = "content_pinyin"
+)
+
+// pinyinFields maps each pinyin pseudo-field to the field it indexes
+var pinyinFields = map[search.Field]search.Field{
+	fieldTitlePinyin:   search.FieldTitle,
+	fieldContentPinyin: search.FieldContent,
+}
+
+// Pseudo-fields matching a term against the title and content of a poem with
+// their variant characters folded (see classifier.FoldVariants)
+const (
+	fieldTitleFolded   search.Field = "title_folded"
+	fieldContentFolded search.Field = "content_folded"
+)
+
+// foldedFields maps the title and content fields to their folded pseudo-fields
+var foldedFields = map[search.Field]search.Field{
+	search.FieldTitle:   fieldTitleFolded,
+	search.FieldContent: fieldContentFolded,
+}
+
+// parseSearchQuery parses query with search.Parse, or for pinyin searches as
+// a single pinyin term
+func parseSearchQuery(query, searchType string) (*search.Query, error) {
+	if searchType != "pinyin" {
+		return search.Parse(query)
+	}
+	normalized, err := search.ParsePinyin(query)
+	if err != nil {
+		return nil, err
+	}
+	return &search.Query{Clauses: []search.Clause{{Terms: []search.Term{{Text: normalized}}}}}, nil
+}
+
+// termFields returns the fields a term is matched against: its own field
+// prefix, or else every field searchType covers
+func termFields(term search.Term, searchType string) []search.Field {
+	if term.Field != "" {
+		return []search.Field{term.Field}
+	}
+	switch searchType {
+	case "pinyin":
+		return []search.Field{fieldTitlePinyin, fieldContentPinyin}
+	case "title":
+		return []search.Field{search.FieldTitle}
+	case "content":
+		return []search.Field{search.FieldContent}
+	case "author":
+		return []search.Field{search.FieldAuthor}
+	default: // "all"
+		return []search.Field{search.FieldTitle, search.FieldContent, search.FieldAuthor}
+	}
+}
+
+// foldFtsAlias names the simplified FTS table joined into traditional searches
+const foldFtsAlias = "search_fold"
+
+// termVariants returns the spellings of a term to match against this
+// repository's tables: the term converted to their script, so that 靜夜思
+// finds 静夜思 in simplified tables and vice versa, plus the term as typed
+// when that differs, in case the conversion picked another character.
+func (r *Repository) termVariants(text string) []string {
+	convert := classifier.ToSimplified
+	if r.lang == LangHant {
+		convert = classifier.ToTraditional
+	}
+	converted, err := convert(text)
+	if err != nil || converted == "" || converted == text {
+		return []string{text}
+	}
+	return []string{converted, text}
+}
+
+// fieldVariants returns the spellings of a term to match against field: for
+// the pinyin pseudo-fields the ways it can be split into syllables (see
+// pinyin.Spellings), which anchor it on syllable boundaries, and otherwise
+// its termVariants
+func (r *Repository) fieldVariants(text string, field search.Field) []string {
+	if _, isPinyin := pinyinFields[field]; isPinyin {
+		return pinyin.Spellings(text)
+	}
+	return r.termVariants(text)
+}
+
+// searchColumn returns the column a search field is matched against. Author
+// names are coalesced so that poems without an author don't turn an excluded
+// author term into NULL.
+func (r *Repository) searchColumn(field search.Field) string {
+	switch field {
+	case search.FieldTitle:
+		return r.poemsFtsTable() + ".title"
+	case search.FieldContent:
+		return r.poemsFtsTable() + ".content_text"
+	case fieldTitlePinyin, fieldContentPinyin, fieldTitleFolded, fieldContentFolded:
+		return r.poemsFtsTable() + "." + string(field)
+	default:
+		return "COALESCE(" + r.authorsTable() + ".name, '')"
+	}
+}
+
+// likeWildcards escapes the characters LIKE treats specially
+var likeWildcards = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// containsCondition returns the LIKE condition matching column values that
+// contain text, with the pattern to bind to it. Wildcards in text are matched
+// literally. SQLite doesn't hand LIKE with an ESCAPE clause to the trigram
+// index, so the clause is only added when there is something to escape.
+func containsCondition(column, text string) (string, string) {
+	escaped := likeWildcards.Replace(text)
+	if escaped == text {
+		return column + " LIKE ?", "%" + text + "%"
+	}
+	return column + ` LIKE ? ESCAPE '\'`, "%" + escaped + "%"
+}
+
+// searchCondition builds the WHERE condition matching a parsed query: clauses
+// are ANDed, the terms of a clause ORed across all their fields and variants.
+//
+// Converting simplified to traditional is one-to-many (发 is 發 or 髮), so a
+// converted term can miss traditional text. Traditional searches therefore
+// also match the term folded to simplified against the simplified index of
+// the same poem, where every traditional spelling has collapsed into one.
+// Variant characters within a script (爲 and 為) are matched through the
+// title and content as folded by classifier.FoldVariants.
+func (r *Repository) searchCondition(q *search.Query, searchType string) (string, []any) {
+	var clauses []string
+	var args []any
+	for _, clause := range q.Clauses {
+		var alternatives []string
+		for _, term := range clause.Terms {
+			for _, field := range termFields(term, searchType) {
+				column := r.searchColumn(field)
+				folded, foldable := foldedFields[field]
+				add := func(column, text string) {
+					condition, pattern := containsCondition(column, text)
+					alternatives = append(alternatives, condition)
+					args = append(args, pattern)
+				}
+				for _, variant := range r.fieldVariants(term.Text, field) {
+					add(column, variant)
+					if foldable {
+						add(r.searchColumn(folded), classifier.FoldVariants(variant))
+					}
+				}
+				if foldable && r.lang == LangHant {
+					add(foldFtsAlias+column[strings.LastIndex(column, "."):], foldScript(term.Text))
+				}
+			}
+		}
+		if len(alternatives) == 0 {
+			// A pinyin term that can't be split into syllables matches nothing
+			alternatives = []string{"0"}
+		}
+		condition := "(" + strings.Join(alternatives, " OR ") + ")"
+		if clause.Exclude {
+			condition = "NOT COALESCE(" + condition + ", 0)"
+		}
+		clauses = append(clauses, condition)
+	}
+	return strings.Join(clauses, " AND "), args
+}
+
+// rankedTerm is a query term that is scored through the FTS index
+type rankedTerm struct {
+	text     string
+	variants []string // Spellings matched in the index, see fieldVariants
+	title    bool     // Term is matched against the title
+	content  bool     // Term is matched against the content
+	pinyin   bool     // Term is matched against the pinyin of the above
+}
+
+// termSpelling is a spelling of a ranked term, with the FTS columns holding
+// the title and content it is looked for in
+type termSpelling struct {
+	text    string
+	title   string
+	content string
+}
+
+// spellings returns the spellings of a term with their columns: its variants
+// in the text or pinyin columns and, with fold, the variants folded in the
+// folded columns as in searchCondition. A poem spelling a term as written
+// matches it in both and so ranks above one spelling it with other variants.
+func (t rankedTerm) spellings(fold bool) []termSpelling {
+	title, content := "title", "content_text"
+	if t.pinyin {
+		title, content = string(fieldTitlePinyin), string(fieldContentPinyin)
+	}
+
+	spellings := make([]termSpelling, 0, 2*len(t.variants))
+	add := func(spelling termSpelling) {
+		if !slices.Contains(spellings, spelling) {
+			spellings = append(spellings, spelling)
+		}
+	}
+	for _, variant := range t.variants {
+		add(termSpelling{text: variant, title: title, content: content})
+	}
+	if fold && !t.pinyin {
+		for _, variant := range t.variants {
+			add(termSpelling{text: classifier.FoldVariants(variant), title: string(fieldTitleFolded), content: string(fieldContentFolded)})
+		}
+	}
+	return spellings
+}
+
+// rankedTerms returns the terms a matching poem contains in its title or
+// content; excluded and author-only terms don't affect the score
+func (r *Repository) rankedTerms(q *search.Query, searchType string) []rankedTerm {
+	var terms []rankedTerm
+	for _, term := range q.Terms() {
+		ranked := rankedTerm{text: term.Text}
+		for _, field := range termFields(term, searchType) {
+			switch field {
+			case search.FieldTitle:
+				ranked.title = true
+			case search.FieldContent:
+				ranked.content = true
+			case fieldTitlePinyin:
+				ranked.title, ranked.pinyin = true, true
+			case fieldContentPinyin:
+				ranked.content, ranked.pinyin = true, true
+			}
+			if ranked.variants == nil {
+				ranked.variants = r.fieldVariants(term.Text, field)
+			}
+		}
+		if (ranked.title || ranked.content) && len(ranked.variants) > 0 {
+			terms = append(terms, ranked)
+		}
+	}
+	return terms
+}
+
+// searchRank returns a subquery yielding (rank_id, score) for the poems whose
+// title or content contains any of terms; higher scores are better matches. A
+// hit in the title weighs ten times as much as a hit in the content.
+func (r *Repository) searchRank(terms []rankedTerm) *gorm.DB {
+	ftsTable := r.poemsFtsTable()
+
+	if useFtsMatch(terms) {
+		// bm25() returns lower values for better matches, so negate it. The
+		// weights are per column: title, content_text, their pinyin and their
+		// folded forms.
+		return r.db.Table(ftsTable).
+			Select("rowid AS rank_id, -bm25("+ftsTable+", 10.0, 1.0, 10.0, 1.0, 10.0, 1.0) AS score").
+			Where(ftsTable+" MATCH ?", ftsMatchExpr(terms, true))
+	}
+
+	// Occurrences of a term in a column: the length the column loses when every
+	// occurrence is removed, divided by the term length
+	occurrences := func(column, weight string) string {
+		return "(length(" + column + ") - length(replace(" + column + ", ?, ''))) / length(?) * " + weight
+	}
+
+	var scores, conditions []string
+	var scoreArgs, conditionArgs []any
+	for _, term := range terms {
+		for _, spelling := range term.spellings(true) {
+			if term.title {
+				scores = append(scores, occurrences(spelling.title, "10.0"))
+				scoreArgs = append(scoreArgs, spelling.text, spelling.text)
+				condition, pattern := containsCondition(spelling.title, spelling.text)
+				conditions = append(conditions, condition)
+				conditionArgs = append(conditionArgs, pattern)
+			}
+			if term.content {
+				scores = append(scores, occurrences(spelling.content, "1.0"))
+				scoreArgs = append(scoreArgs, spelling.text, spelling.text)
+				condition, pattern := containsCondition(spelling.content, spelling.text)
+				conditions = append(conditions, condition)
+				conditionArgs = append(conditionArgs, pattern)
+			}
+		}
+	}
+	return r.db.Table(ftsTable).
+		Select("rowid AS rank_id, "+strings.Join(scores, " + ")+" AS score", scoreArgs...).
+		Where(strings.Join(conditions, " OR "), conditionArgs...)
+}
+
+// searchFragment holds the highlighted title and content snippet of a result
+type searchFragment struct {
+	ID        int64
+	Highlight string
+	Snippet   string
+}
+
+// searchFragments builds title highlights and content snippets for poems with
+// FTS5's highlight() and snippet(). Poems the MATCH doesn't cover (short
+// terms, poems that only matched on the author or through the simplified
+// index) are left out, for fallbackFragments to handle, as are pinyin
+// searches, whose hits are in columns highlight() doesn't show.
+func (r *Repository) searchFragments(terms []rankedTerm, poems []Poem) (map[int64]searchFragment, error) {
+	fragments := make(map[int64]searchFragment, len(poems))
+	isPinyin := func(term rankedTerm) bool { return term.pinyin }
+	if len(poems) == 0 || len(terms) == 0 || !useFtsMatch(terms) || slices.ContainsFunc(terms, isPinyin) {
+		return fragments, nil
+	}
+
+	ids := make([]int64, len(poems))
+	for i := range poems {
+		ids[i] = poems[i].ID
+	}
+
+	ftsTable := r.poemsFtsTable()
+	var rows []searchFragment
+	err := r.db.Table(ftsTable).
+		Select("rowid AS id, highlight("+ftsTable+", 0, ?, ?) AS highlight, snippet("+ftsTable+", 1, ?, ?, '…', ?) AS snippet",
+			HighlightOpen, HighlightClose, HighlightOpen, HighlightClose, snippetRunes).
+		Where(ftsTable+" MATCH ? AND rowid IN ?", ftsMatchExpr(terms, false), ids).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		fragments[row.ID] = row
+	}
+	return fragments, nil
+}
+
+// useFtsMatch reports whether terms can be run through FTS5 MATCH, which with
+// the trigram tokenizer needs at least three characters per term
+func useFtsMatch(terms []rankedTerm) bool {
+	for _, term := range terms {
+		for _, variant := range term.variants {
+			if utf8.RuneCountInString(variant) < 3 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ftsMatchExpr builds an FTS5 expression matching any spelling of terms (see
+// rankedTerm.spellings). Each spelling is quoted as a phrase, so operators and
+// punctuation in it are matched literally, and restricted to the columns its
+// term targets. Without fold, variant characters must match exactly, so that
+// highlight() marks every poem the expression matches.
+func ftsMatchExpr(terms []rankedTerm, fold bool) string {
+	var phrases []string
+	for _, term := range terms {
+		for _, spelling := range term.spellings(fold) {
+			phrase := `"` + strings.ReplaceAll(spelling.text, `"`, `""`) + `"`
+			switch {
+			case term.title && term.content:
+				phrases = append(phrases, "{"+spelling.title+" "+spelling.content+"} : "+phrase)
+			case term.title:
+				phrases = append(phrases, spelling.title+" : "+phrase)
+			default:
+				phrases = append(phrases, spelling.content+" : "+phrase)
+			}
+		}
+	}
+	return strings.Join(phrases, " OR ")
+}
+
+// fallbackFragments builds a title highlight and content snippet in Go, for
+// results searchFragments has none for
+func fallbackFragments(terms []rankedTerm, poem *Poem) searchFragment {
+	title, content := &termMatcher{}, &termMatcher{}
+	for _, term := range terms {
+		if term.title {
+			title.add(term.text, term.pinyin)
+		}
+		if term.content {
+			content.add(term.text, term.pinyin)
+		}
+	}
+	return searchFragment{
+		ID:        poem.ID,
+		Highlight: highlightText(poem.Title, title),
+		Snippet:   snippetText(strings.Join(poemParagraphs(poem), ""), content),
+	}
+}
+
+// foldScript converts text to simplified Chinese so that text and terms can be
+// compared whichever script they are in. Text whose conversion changes its
+// length is returned as is, so rune offsets into the result stay valid for
+// the original.
+func foldScript(text string) string {
+	folded, err := classifier.ToSimplified(text)
+	if err != nil || utf8.RuneCountInString(folded) != utf8.RuneCountInString(text) {
+		return text
+	}
+	return folded
+}
+
+// foldText folds the script and the variant characters of text, so that text
+// and terms can be compared however they are written. Offsets into the result
+// are valid for text, as with foldScript.
+func foldText(text string) string {
+	return classifier.FoldVariants(foldScript(text))
+}
+
+// termMatcher locates search terms in text regardless of script and variant
+// characters, or by their pinyin
+type termMatcher struct {
+	terms  [][]rune // Folded with foldText
+	pinyin []string // Normalized pinyin, see pinyin.Locate
+}
+
+// add adds a term to look for, or with isPinyin set its normalized pinyin
+func (m *termMatcher) add(term string, isPinyin bool) {
+	switch {
+	case term == "":
+	case isPinyin:
+		m.pinyin = append(m.pinyin, term)
+	default:
+		m.terms = append(m.terms, []rune(foldText(term)))
+	}
+}
+
+// find returns the rune ranges [start, end) of non-overlapping term
+// occurrences in text, preferring the longest term where several start at
+// the same place
+func (m *termMatcher) find(text string) [][2]int {
+	if len(m.pinyin) > 0 {
+		return m.findPinyin(text)
+	}
+	if len(m.terms) == 0 {
+		return nil
+	}
+	folded := []rune(foldText(text))
+	var ranges [][2]int
+	for i := 0; i < len(folded); {
+		n := 0
+		for _, term := range m.terms {
+			if len(term) > n && i+len(term) <= len(folded) && slices.Equal(folded[i:i+len(term)], term) {
+				n = len(term)
+			}
+		}
+		if n == 0 {
+			i++
+			continue
+		}
+		ranges = append(ranges, [2]int{i, i + n})
+		i += n
+	}
+	return ranges
+}
+
+// findPinyin returns the rune ranges of text spelling any of the pinyin terms,
+// dropping those that overlap an earlier one
+func (m *termMatcher) findPinyin(text string) [][2]int {
+	var found [][2]int
+	for _, term := range m.pinyin {
+		found = append(found, pinyin.Locate(text, term)...)
+	}
+	slices.SortFunc(found, func(a, b [2]int) int { return a[0] - b[0] })
+
+	var ranges [][2]int
+	for _, r := range found {
+		if len(ranges) == 0 || r[0] >= ranges[len(ranges)-1][1] {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
+
+// highlightText wraps every term occurrence in text with the highlight markers
+func highlightText(text string, m *termMatcher) string {
+	runes := []rune(text)
+	var b strings.Builder
+	pos := 0
+	for _, r := range m.find(text) {
+		b.WriteString(string(runes[pos:r[0]]))
+		b.WriteString(HighlightOpen + string(runes[r[0]:r[1]]) + HighlightClose)
+		pos = r[1]
+	}
+	b.WriteString(string(runes[pos:]))
+	return b.String()
+}
+
+// snippetText returns about snippetRunes runes of text centred on the first
+// term occurrence (or the start of text if there is none), highlighted and
+// with an ellipsis wherever text was cut.
+func snippetText(text string, m *termMatcher) string {
+	runes := []rune(text)
+	start := 0
+	if ranges := m.find(text); len(ranges) > 0 {
+		first := ranges[0]
+		start = max(0, first[0]-(snippetRunes-(first[1]-first[0]))/2)
+	}
+	end := min(len(runes), start+snippetRunes)
+	start = max(0, min(start, end-snippetRunes))
+
+	snippet := highlightText(string(runes[start:end]), m)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// matchOffsets locates every occurrence of the query's terms in the fields
+// each term covers, ordered by field, line and position
+func matchOffsets(q *search.Query, searchType string, poem *Poem) []MatchOffset {
+	matchers := make(map[search.Field]*termMatcher)
+	for _, term := range q.Terms() {
+		for _, field := range termFields(term, searchType) {
+			target, isPinyin := pinyinFields[field]
+			if !isPinyin {
+				target = field
+			}
+			if matchers[target] == nil {
+				matchers[target] = &termMatcher{}
+			}
+			matchers[target].add(term.Text, isPinyin)
+		}
+	}
+
+	matches := []MatchOffset{}
+	if m := matchers[search.FieldTitle]; m != nil {
+		matches = appendMatches(matches, search.FieldTitle, 0, poem.Title, m)
+	}
+	if m := matchers[search.FieldContent]; m != nil {
+		for line, paragraph := range poemParagraphs(poem) {
+			matches = appendMatches(matches, search.FieldContent, line, paragraph, m)
+		}
+	}
+	if m := matchers[search.FieldAuthor]; m != nil && poem.Author != nil {
+		matches = appendMatches(matches, search.FieldAuthor, 0, poem.Author.Name, m)
+	}
+	return matches
+}
+
+// appendMatches appends the rune offsets of each term occurrence in text
+func appendMatches(matches []MatchOffset, field search.Field, line int, text string, m *termMatcher) []MatchOffset {
+	for _, r := range m.find(text) {
+		matches = append(matches, MatchOffset{Field: string(field), Line: line, Start: r[0], End: r[1]})
+	}
+	return matches
+}
+
+// poemParagraphs decodes the JSON array of paragraphs stored in poem.Content
+func poemParagraphs(poem *Poem) []string {
+	var paragraphs []string
+	_ = json.Unmarshal(poem.Content, &paragraphs)
+	return paragraphs
+}
@@ -1,5 +1,11 @@
 package database
 
+import (
+	"slices"
+
+	"gorm.io/gorm"
+)
+
 // Statistics and counting methods
 
 // CountPoems returns the total number of poems
@@ -95,14 +101,11 @@ func (r *Repository) GetStatistics() (*Statistics, error) {
 
 // ListAuthorsWithFilter returns a paginated list of authors with optional dynasty filter
 func (r *Repository) ListAuthorsWithFilter(limit, offset int, dynastyID *int64) ([]AuthorWithStats, int, error) {
-	authorTable := r.authorsTable()
-	poemTable := r.poemsTable()
-
-	query := r.db.Table(authorTable)
+	query := r.db.Table(r.authorsTable())
 
 	// Apply dynasty filter
 	if dynastyID != nil {
-		query = query.Where(authorTable+".dynasty_id = ?", *dynastyID)
+		query = query.Where("dynasty_id = ?", *dynastyID)
 	}
 
 	// Get total count
@@ -111,31 +114,92 @@ func (r *Repository) ListAuthorsWithFilter(limit, offset int, dynastyID *int64)
 		return nil, 0, err
 	}
 
-	// Get authors with poem counts
-	var results []struct {
-		Author
-		PoemCount int `gorm:"column:poem_count"`
-	}
-
+	// Poem counts are stored with the authors (see migrateAuthorCountsForLang)
+	var authors []AuthorWithStats
 	err := query.
-		Select(authorTable + ".*, COUNT(" + poemTable + ".id) as poem_count").
-		Joins("LEFT JOIN " + poemTable + " ON " + authorTable + ".id = " + poemTable + ".author_id").
-		Group(authorTable + ".id").
-		Order("poem_count DESC").
+		Order("poem_count DESC, id ASC").
 		Limit(limit).Offset(offset).
-		Scan(&results).Error
+		Find(&authors).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// Convert to AuthorWithStats
-	authors := make([]AuthorWithStats, len(results))
-	for i, r := range results {
-		authors[i] = AuthorWithStats{
-			Author:    r.Author,
-			PoemCount: r.PoemCount,
+	return authors, int(totalCount), nil
+}
+
+// PageAuthors returns a window of authors ordered by poem count (descending,
+// ties broken by ID), with an optional dynasty filter, along with the total
+// count. Poem counts are stored with the authors and kept up to date by
+// triggers, so cursor pages seek through the (poem_count, id) index instead of
+// counting every author's poems; they only change when the processor updates
+// the database. adjacent reports whether further authors exist on either side
+// of the window.
+func (r *Repository) PageAuthors(dynastyID *int64, page Page) (authors []AuthorWithStats, total int, adjacent Adjacent, err error) {
+	filter := func(q *gorm.DB) *gorm.DB {
+		if dynastyID != nil {
+			q = q.Where("dynasty_id = ?", *dynastyID)
 		}
+		return q
 	}
 
-	return authors, int(totalCount), nil
+	var totalCount int64
+	if err := filter(r.db.Table(r.authorsTable())).Count(&totalCount).Error; err != nil {
+		return nil, 0, adjacent, err
+	}
+
+	// Rows past a cursor in the listing, or ahead of it. The bound on
+	// poem_count alone is what the index seeks to; ties are then skipped.
+	past := func(q *gorm.DB, c *Cursor, inclusive bool) *gorm.DB {
+		op := ">"
+		if inclusive {
+			op = ">="
+		}
+		return q.Where("poem_count <= ? AND (poem_count < ? OR id "+op+" ?)", c.PoemCount, c.PoemCount, c.ID)
+	}
+	ahead := func(q *gorm.DB, c *Cursor, inclusive bool) *gorm.DB {
+		op := "<"
+		if inclusive {
+			op = "<="
+		}
+		return q.Where("poem_count >= ? AND (poem_count > ? OR id "+op+" ?)", c.PoemCount, c.PoemCount, c.ID)
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	query := filter(r.db.Table(r.authorsTable())).Limit(page.Limit + 1)
+	switch {
+	case page.Before != nil:
+		query = ahead(query, page.Before, false).Order("poem_count ASC, id DESC")
+	case page.After != nil:
+		query = past(query, page.After, false).Order("poem_count DESC, id ASC")
+	default:
+		query = query.Offset(page.Offset).Order("poem_count DESC, id ASC")
+	}
+	if err := query.Find(&authors).Error; err != nil {
+		return nil, 0, adjacent, err
+	}
+
+	more := len(authors) > page.Limit
+	if more {
+		authors = authors[:page.Limit]
+	}
+
+	// The other side of a cursor has authors if any are at or past it
+	switch {
+	case page.Before != nil:
+		slices.Reverse(authors)
+		adjacent.Before = more
+		adjacent.After, err = exists(past(filter(r.db.Table(r.authorsTable())), page.Before, true))
+	case page.After != nil:
+		adjacent.After = more
+		adjacent.Before, err = exists(ahead(filter(r.db.Table(r.authorsTable())), page.After, true))
+	default:
+		adjacent.After = more
+		adjacent.Before = page.Offset > 0 && totalCount > 0
+	}
+	if err != nil {
+		return nil, 0, adjacent, err
+	}
+
+	r.loadAuthorDynasties(authors)
+	return authors, int(totalCount), adjacent, nil
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -164,6 +165,44 @@ func TestGetPoetryTypeID(t *testing.T) {
 	}
 }
 
+func TestGetOrCreateCiPai(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	chars := 100
+	niannujiao := &CiPai{Name: "念奴娇", Aliases: datatypes.JSON(`["百字令","酹江月"]`), Chars: &chars}
+	require.NoError(t, db.Table(repo.ciPaiTable()).Create(niannujiao).Error)
+
+	tests := []struct {
+		name   string
+		ciPai  string
+		wantID int64 // 0 for a newly created tune
+	}{
+		{"get by name", "念奴娇", niannujiao.ID},
+		{"get by alias", "百字令", niannujiao.ID},
+		{"create new tune", "水调歌头", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := repo.GetOrCreateCiPai(tt.ciPai)
+			require.NoError(t, err)
+			if tt.wantID != 0 {
+				assert.Equal(t, tt.wantID, id)
+				return
+			}
+			assert.NotEqual(t, niannujiao.ID, id)
+
+			again, err := repo.GetOrCreateCiPai(tt.ciPai)
+			require.NoError(t, err)
+			assert.Equal(t, id, again, "Should return same ID for existing tune")
+		})
+	}
+
+	_, err := repo.GetCiPaiByName("不存在的词牌")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
 func TestCountPoems(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewRepository(db)
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/vbauerster/mpb/v8"
@@ -72,6 +73,59 @@ func (r *Repository) GetOrCreateAuthor(name string, dynastyID int64) (int64, err
 	return author.ID, nil
 }
 
+// UpdateAuthorDescriptions sets the description of each author matching the
+// name and dynasty name of a description, in one transaction. Returns the
+// number of authors updated; descriptions matching no author are skipped.
+func (r *Repository) UpdateAuthorDescriptions(descriptions []AuthorDescription) (int64, error) {
+	query := fmt.Sprintf(
+		"UPDATE %s SET description = ? WHERE name = ? AND dynasty_id IN (SELECT id FROM %s WHERE name = ?)",
+		r.authorsTable(), r.dynastiesTable(),
+	)
+
+	var updated int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, d := range descriptions {
+			result := tx.Exec(query, d.Description, d.Name, d.Dynasty)
+			if result.Error != nil {
+				return fmt.Errorf("failed to update description of %s: %w", d.Name, result.Error)
+			}
+			updated += result.RowsAffected
+		}
+		return nil
+	})
+	return updated, err
+}
+
+// GetOrCreateCiPai gets the ID of the 词牌 named name, by its own name or one
+// of its aliases, creating it without metadata if there is none
+// Uses ON CONFLICT to handle concurrent inserts gracefully
+func (r *Repository) GetOrCreateCiPai(name string) (int64, error) {
+	if ciPai, err := r.GetCiPaiByName(name); err == nil {
+		return ciPai.ID, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	ciPai := CiPai{Name: name}
+	err := r.db.Table(r.ciPaiTable()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true, // Ignore if created concurrently
+	}).Create(&ciPai).Error
+	if err != nil {
+		return 0, err
+	}
+
+	// If ciPai.ID is 0, the insert was skipped (created concurrently)
+	if ciPai.ID == 0 {
+		err = r.db.Table(r.ciPaiTable()).Where("name = ?", name).First(&ciPai).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return ciPai.ID, nil
+}
+
 // GetPoetryTypeID gets the ID of a poetry type by name
 func (r *Repository) GetPoetryTypeID(name string) (int64, error) {
 	var poetryType PoetryType
@@ -86,33 +140,43 @@ func (r *Repository) GetPoetryTypeID(name string) (int64, error) {
 // Returns IDs in the same order as the input names
 // Returns error if any of the requested types are not found
 func (r *Repository) GetPoetryTypeIDs(names []string) ([]int64, error) {
+	return r.lookupIDsByName(r.poetryTypesTable(), names)
+}
+
+// lookupIDsByName resolves names in a table with a unique name column to IDs
+// in a single query. A name matches as given or converted to the table's
+// script (see Lang.ToBase). IDs are returned in the same order as names, and
+// gorm.ErrRecordNotFound is returned if any name is missing.
+func (r *Repository) lookupIDsByName(table string, names []string) ([]int64, error) {
 	if len(names) == 0 {
 		return []int64{}, nil
 	}
 
-	var poetryTypes []PoetryType
-	err := r.db.Table(r.poetryTypesTable()).
-		Where("name IN ?", names).
-		Find(&poetryTypes).Error
+	var rows []struct {
+		ID   int64
+		Name string
+	}
+	err := r.db.Table(table).
+		Select("id, name").
+		Where("name IN ?", r.NameVariants(names)).
+		Find(&rows).Error
 	if err != nil {
 		return nil, err
 	}
 
-	// Check if we found all requested types
-	if len(poetryTypes) != len(names) {
-		return nil, gorm.ErrRecordNotFound
-	}
-
 	// Create a map for O(1) lookup
-	typeMap := make(map[string]int64, len(poetryTypes))
-	for _, pt := range poetryTypes {
-		typeMap[pt.Name] = pt.ID
+	idMap := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		idMap[row.Name] = row.ID
 	}
 
 	// Return IDs in the same order as input names
 	ids := make([]int64, len(names))
 	for i, name := range names {
-		id, ok := typeMap[name]
+		id, ok := idMap[name]
+		if !ok {
+			id, ok = idMap[r.baseName(name)]
+		}
 		if !ok {
 			return nil, gorm.ErrRecordNotFound
 		}
@@ -122,6 +186,30 @@ func (r *Repository) GetPoetryTypeIDs(names []string) ([]int64, error) {
 	return ids, nil
 }
 
+// NameVariants returns names together with their conversions to the script of
+// this repository's tables, for matching names that may be written in a
+// regional variant against stored ones
+func (r *Repository) NameVariants(names []string) []string {
+	variants := make([]string, 0, 2*len(names))
+	for _, name := range names {
+		variants = append(variants, name)
+		if base := r.baseName(name); base != name {
+			variants = append(variants, base)
+		}
+	}
+	return variants
+}
+
+// baseName converts name to the script of this repository's tables, keeping
+// it as is if the conversion fails
+func (r *Repository) baseName(name string) string {
+	base, err := r.lang.ToBase(name)
+	if err != nil {
+		return name
+	}
+	return base
+}
+
 // InsertPoem inserts a poem into the database
 func (r *Repository) InsertPoem(poem *Poem) error {
 	return r.db.Table(r.poemsTable()).Create(poem).Error
@@ -229,6 +317,249 @@ func (r *Repository) BatchInsertPoemsWithTransaction(poems []*Poem, transactionS
 func (r *Repository) UpsertPoem(poem *Poem) error {
 	return r.db.Table(r.poemsTable()).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"title", "content", "author_id", "dynasty_id", "type_id"}),
+		DoUpdates: clause.AssignmentColumns([]string{"title", "content", "author_id", "dynasty_id", "type_id", "ci_pai_id", "gong_diao", "qu_pai", "stanza_sizes", "title_folded", "content_folded", "source_key", "source_hash", "dataset_key", "dataset_name", "source_path", "source_id", "source_index"}),
 	}).Create(poem).Error
 }
+
+// deleteChunkSize bounds the IDs bound in one DELETE or lookup, below SQLite's
+// limit on host parameters
+const deleteChunkSize = 500
+
+// poemSaveColumns are the columns SavePoems overwrites in a stored poem: all
+// but its ID, its cluster, which clustering sets afterwards, and its creation
+var poemSaveColumns = []string{
+	"type_id", "type_rule", "type_confidence", "type_evidence", "ci_pai_id", "gong_diao", "qu_pai",
+	"title", "content", "stanza_sizes", "content_hash",
+	"source_key", "source_hash", "dataset_key", "dataset_name", "source_path", "source_id", "source_index",
+	"title_pinyin", "content_pinyin", "title_folded", "content_folded",
+	"meter_status", "meter_detail", "rhyme_detail", "author_id", "dynasty_id",
+}
+
+// ListPoemSources returns the source identity of every poem, then of every
+// source record skipped as a duplicate, for comparing them with the source data
+func (r *Repository) ListPoemSources() ([]PoemSource, error) {
+	var sources []PoemSource
+	err := r.db.Table(r.poemsTable()).
+		Select("id, source_key, source_hash, source_path, source_index").
+		Order("id").
+		Scan(&sources).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var duplicates []PoemSource
+	err = r.db.Table(r.poemDuplicatesTable()).
+		Select("source_key, source_hash, duplicate_of").
+		Order("source_key").
+		Scan(&duplicates).Error
+	return append(sources, duplicates...), err
+}
+
+// RecordDuplicates records which of poems, just inserted with
+// BatchInsertPoems, were skipped as exact duplicates of a stored poem, and
+// returns how many were
+func (r *Repository) RecordDuplicates(poems []*Poem) (int, error) {
+	stored := make(map[int64]bool, len(poems))
+	for i := 0; i < len(poems); i += deleteChunkSize {
+		chunk := poems[i:min(i+deleteChunkSize, len(poems))]
+		ids := make([]int64, len(chunk))
+		for j, poem := range chunk {
+			ids[j] = poem.ID
+		}
+		var found []int64
+		if err := r.db.Table(r.poemsTable()).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+			return 0, fmt.Errorf("failed to look up poems: %w", err)
+		}
+		for _, id := range found {
+			stored[id] = true
+		}
+	}
+	if len(stored) == len(poems) {
+		return 0, nil
+	}
+
+	recorded := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, poem := range poems {
+			if stored[poem.ID] {
+				continue
+			}
+			original, err := r.findDuplicate(tx, poem)
+			if err != nil {
+				return err
+			}
+			if original == 0 {
+				continue // Skipped for another reason, or already gone
+			}
+			if err := r.recordDuplicate(tx, poem, original); err != nil {
+				return err
+			}
+			recorded++
+		}
+		return nil
+	})
+	return recorded, err
+}
+
+// SavePoems stores poems under their IDs in one transaction, inserting new
+// ones and overwriting the stored ones in place, as an update does. A poem
+// whose title and content would duplicate another stored poem is not saved:
+// if a version of it is stored already, that version stays as it is, and
+// otherwise it is recorded as a duplicate, as a build would skip it. Returns
+// the poems not saved, with the ID of the poem they duplicate as DuplicateOf
+// and their own ID only if they kept a stored version.
+func (r *Repository) SavePoems(poems []*Poem) ([]PoemSource, error) {
+	var unsaved []PoemSource
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, poem := range poems {
+			original, err := r.findDuplicate(tx, poem)
+			if err != nil {
+				return err
+			}
+			if original == 0 {
+				err := tx.Table(r.poemsTable()).Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "id"}},
+					DoUpdates: clause.AssignmentColumns(poemSaveColumns),
+				}).Create(poem).Error
+				if err != nil {
+					return fmt.Errorf("failed to save poem %d: %w", poem.ID, err)
+				}
+				forget := fmt.Sprintf("DELETE FROM %s WHERE source_key = ?", r.poemDuplicatesTable())
+				if err := tx.Exec(forget, poem.SourceKey).Error; err != nil {
+					return fmt.Errorf("failed to forget duplicate %q: %w", poem.SourceKey, err)
+				}
+				continue
+			}
+
+			var stored int64
+			if err := tx.Table(r.poemsTable()).Where("id = ?", poem.ID).Count(&stored).Error; err != nil {
+				return fmt.Errorf("failed to look up poem %d: %w", poem.ID, err)
+			}
+			if stored > 0 {
+				unsaved = append(unsaved, PoemSource{ID: poem.ID, SourceKey: poem.SourceKey, SourceHash: poem.SourceHash, DuplicateOf: original})
+				continue
+			}
+			if err := r.recordDuplicate(tx, poem, original); err != nil {
+				return err
+			}
+			unsaved = append(unsaved, PoemSource{SourceKey: poem.SourceKey, SourceHash: poem.SourceHash, DuplicateOf: original})
+		}
+		return nil
+	})
+	return unsaved, err
+}
+
+// findDuplicate returns the ID of the stored poem other than poem with the
+// same title and content, or 0 if there is none
+func (r *Repository) findDuplicate(tx *gorm.DB, poem *Poem) (int64, error) {
+	var ids []int64
+	err := tx.Table(r.poemsTable()).
+		Where("title = ? AND content_hash = ? AND id <> ?", poem.Title, poem.ContentHash, poem.ID).
+		Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up duplicates of poem %d: %w", poem.ID, err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return ids[0], nil
+}
+
+// recordDuplicate records the source record of poem as a duplicate of the
+// stored poem original
+func (r *Repository) recordDuplicate(tx *gorm.DB, poem *Poem, original int64) error {
+	err := tx.Exec(fmt.Sprintf(
+		`INSERT INTO %s (source_key, source_hash, duplicate_of) VALUES (?, ?, ?)
+		ON CONFLICT(source_key) DO UPDATE SET source_hash = excluded.source_hash, duplicate_of = excluded.duplicate_of`,
+		r.poemDuplicatesTable(),
+	), poem.SourceKey, poem.SourceHash, original).Error
+	if err != nil {
+		return fmt.Errorf("failed to record duplicate %q: %w", poem.SourceKey, err)
+	}
+	return nil
+}
+
+// DeleteDuplicates forgets the source records with the given keys that were
+// skipped as duplicates, in one transaction
+func (r *Repository) DeleteDuplicates(sourceKeys []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := 0; i < len(sourceKeys); i += deleteChunkSize {
+			chunk := sourceKeys[i:min(i+deleteChunkSize, len(sourceKeys))]
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE source_key IN ?", r.poemDuplicatesTable()), chunk).Error; err != nil {
+				return fmt.Errorf("failed to delete duplicates: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeletePoems deletes the poems with the given IDs in one transaction. IDs of
+// poems that don't exist are ignored.
+func (r *Repository) DeletePoems(ids []int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := 0; i < len(ids); i += deleteChunkSize {
+			chunk := ids[i:min(i+deleteChunkSize, len(ids))]
+			if err := tx.Table(r.poemsTable()).Where("id IN ?", chunk).Delete(&Poem{}).Error; err != nil {
+				return fmt.Errorf("failed to delete poems: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteUnusedAuthors deletes the authors no poem refers to any more, returning
+// how many were deleted
+func (r *Repository) DeleteUnusedAuthors() (int64, error) {
+	result := r.db.Exec(fmt.Sprintf(
+		"DELETE FROM %s WHERE id NOT IN (SELECT author_id FROM %s WHERE author_id IS NOT NULL)",
+		r.authorsTable(), r.poemsTable(),
+	))
+	return result.RowsAffected, result.Error
+}
+
+// ListPoemTexts returns up to limit poems with IDs above afterID, ordered by
+// ID, with only the fields near duplicates are found by: ID, Title,
+// ContentHash, ContentFolded and ClusterID. Passing the last ID as afterID
+// pages through every poem.
+func (r *Repository) ListPoemTexts(afterID int64, limit int) ([]*Poem, error) {
+	var poems []*Poem
+	err := r.db.Table(r.poemsTable()).
+		Select("id, title, content_hash, content_folded, cluster_id").
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&poems).Error
+	return poems, err
+}
+
+// UpdateSourcePositions stores the SourcePath and SourceIndex of each source
+// in one transaction, for poems whose source record only moved
+func (r *Repository) UpdateSourcePositions(sources []PoemSource) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, source := range sources {
+			err := tx.Table(r.poemsTable()).
+				Where("id = ?", source.ID).
+				Updates(map[string]any{"source_path": source.SourcePath, "source_index": source.SourceIndex}).Error
+			if err != nil {
+				return fmt.Errorf("failed to update source of poem %d: %w", source.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateClusterIDs stores the ClusterID of each poem in one transaction
+func (r *Repository) UpdateClusterIDs(poems []*Poem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, poem := range poems {
+			err := tx.Table(r.poemsTable()).
+				Where("id = ?", poem.ID).
+				Update("cluster_id", poem.ClusterID).Error
+			if err != nil {
+				return fmt.Errorf("failed to update cluster of poem %d: %w", poem.ID, err)
+			}
+		}
+		return nil
+	})
+}
@@ -60,6 +60,12 @@ func TestGetPoetryTypeIDs(t *testing.T) {
 			expectError:   false,
 			expectedCount: 1,
 		},
+		{
+			name:          "repeated type",
+			inputNames:    []string{"五言绝句", "五言绝句"},
+			expectError:   false,
+			expectedCount: 2,
+		},
 		{
 			name:          "empty input",
 			inputNames:    []string{},
@@ -1,8 +1,10 @@
 package database
 
+import "slices"
+
 const (
 	// Schema version for migrations
-	SchemaVersion = 1
+	SchemaVersion = 13
 )
 
 // InitialDynastiesSQL contains initial data for dynasties
@@ -23,15 +25,16 @@ var InitialDynastiesSQL = `INSERT OR IGNORE INTO dynasties (name, name_en, start
 // InitialPoetryTypesSQL contains initial data for poetry types
 // IDs use semantic ranges for easy categorization and extension:
 //
-//	10-19: 诗 (Poetry) - 包括唐诗、古诗等
+//	10-19: 诗 (Poetry) - 包括唐诗、古诗等, continued in 100-109
 //	20-29: 词 (Ci) - 包括宋词、五代词等
-//	30-39: 曲 (Qu) - 元曲
+//	30-39: 曲 (Qu) - 元曲、小令、套数
 //	40-49: 蒙学 (Primer)
 //	50-59: 诗经 (Book of Songs)
 //	60-69: 论语 (Analects)
 //	70-79: 楚辞 (Songs of Chu)
 //	80-89: 四书五经 (Four Books and Five Classics)
 //	99: 其他 (Other)
+//	100-109: 诗 (Poetry), continued - 排律、杂言, added once 10-19 was full
 var InitialPoetryTypesSQL = `INSERT OR IGNORE INTO poetry_types (id, name, category, lines, chars_per_line, description) VALUES
 	(10, '唐诗', '唐诗', NULL, NULL, '诗'),
 	(11, '五言绝句', '唐诗', 4, 5, '四句，每句五字'),
@@ -41,12 +44,102 @@ var InitialPoetryTypesSQL = `INSERT OR IGNORE INTO poetry_types (id, name, categ
 	(15, '五言古诗', '唐诗', NULL, 5, '不限句数，每句五字'),
 	(16, '七言古诗', '唐诗', NULL, 7, '不限句数，每句七字'),
 	(17, '乐府诗', '唐诗', NULL, NULL, '不限句数，不限字数'),
+	(18, '六言绝句', '唐诗', 4, 6, '四句，每句六字'),
+	(19, '六言律诗', '唐诗', 8, 6, '八句，每句六字'),
 	(20, '宋词', '宋词', NULL, NULL, '长短句'),
 	(21, '五代词', '词', NULL, NULL, '长短句'),
 	(30, '元曲', '曲', NULL, NULL, '散曲'),
+	(31, '小令', '曲', NULL, NULL, '单支曲子，含带过曲'),
+	(32, '套数', '曲', NULL, NULL, '同一宫调的若干曲子连缀成套，一般有尾声'),
 	(40, '蒙学', '蒙学', NULL, NULL, '蒙学'),
 	(50, '诗经', '诗经', NULL, NULL, '诗经'),
 	(60, '论语', '论语', NULL, NULL, '论语'),
 	(70, '楚辞', '楚辞', NULL, NULL, '楚辞'),
 	(80, '四书五经', '四书五经', NULL, NULL, '四书五经'),
-	(99, '其他', '其他', NULL, NULL, '不规则或其他形式')`
+	(99, '其他', '其他', NULL, NULL, '不规则或其他形式'),
+	(100, '五言排律', '唐诗', NULL, 5, '十句以上的偶数句，每句五字'),
+	(101, '七言排律', '唐诗', NULL, 7, '十句以上的偶数句，每句七字'),
+	(102, '杂言', '唐诗', NULL, NULL, '句子长短不一')`
+
+// poetryTypeSubtypes lists the types refining a broader type, whose poems a
+// filter by the broader type also matches: 元曲 are typed as 小令 or 套数
+var poetryTypeSubtypes = map[int64][]int64{30: {31, 32}}
+
+// withSubtypes returns typeIDs together with their subtypes
+func withSubtypes(typeIDs []int64) []int64 {
+	ids := slices.Clone(typeIDs)
+	for _, id := range typeIDs {
+		ids = append(ids, poetryTypeSubtypes[id]...)
+	}
+	return ids
+}
+
+// InitialCiPaiSQL contains initial data for the best-known 词牌: their other
+// names, so that poems written to an alias are filed under one tune, and the
+// number of characters of their standard form (正体). Tunes missing here are
+// added without metadata as the poems are processed.
+var InitialCiPaiSQL = `INSERT OR IGNORE INTO ci_pai (name, aliases, chars) VALUES
+	('念奴娇', '["百字令","酹江月","大江东去","壶中天","湘月"]', 100),
+	('水调歌头', '["元会曲","台城游"]', 95),
+	('满江红', '["上江虹","念良游","伤春曲"]', 93),
+	('沁园春', '["寿星明","洞庭春色"]', 114),
+	('菩萨蛮', '["子夜歌","重叠金","巫山一片云","花间意","梅花句"]', 44),
+	('浣溪沙', '["浣沙溪","小庭花","满院春","醉木犀","试香罗"]', 42),
+	('蝶恋花', '["鹊踏枝","凤栖梧","黄金缕","卷珠帘","一箩金"]', 60),
+	('西江月', '["白苹香","步虚词","江月令"]', 50),
+	('临江仙', '["谢新恩","雁后归","画屏春","庭院深深"]', 60),
+	('如梦令', '["忆仙姿","宴桃源","无梦令"]', 33),
+	('鹧鸪天', '["思佳客","思越人","剪朝霞","醉梅花"]', 55),
+	('虞美人', '["一江春水","玉壶冰","忆柳曲"]', 56),
+	('卜算子', '["百尺楼","眉峰碧","楚天遥"]', 44),
+	('清平乐', '["清平乐令","忆萝月","醉东风"]', 46),
+	('点绛唇', '["点樱桃","南浦月","沙头雨","寻瑶草"]', 41),
+	('采桑子', '["丑奴儿","丑奴儿令","罗敷媚","罗敷艳歌"]', 44),
+	('忆秦娥', '["秦楼月","碧云深","双荷叶"]', 46),
+	('渔家傲', NULL, 62),
+	('青玉案', '["横塘路","西湖路"]', 67),
+	('生查子', '["楚云深","相和柳","晴色入青山"]', 40),
+	('长相思', '["双红豆","忆多娇","山渐青"]', 36),
+	('相见欢', '["上西楼","秋夜月","忆真妃"]', 36),
+	('破阵子', '["十拍子"]', 62),
+	('永遇乐', NULL, 104),
+	('声声慢', '["胜胜慢","人在楼上","寒松叹"]', 97),
+	('雨霖铃', '["雨淋铃"]', 103),
+	('八声甘州', '["甘州","潇潇雨","宴瑶池"]', 97),
+	('水龙吟', '["龙吟曲","丰年瑞","鼓笛慢","小楼连苑"]', 102),
+	('贺新郎', '["金缕曲","乳燕飞","貂裘换酒","贺新凉"]', 116),
+	('摸鱼儿', '["摸鱼子","买陂塘","迈陂塘","双蕖怨"]', 116),
+	('浪淘沙', '["浪淘沙令"]', 54),
+	('江城子', '["江神子","村意远"]', 70),
+	('踏莎行', '["柳长春","喜朝天"]', 58),
+	('苏幕遮', '["云雾敛","鬓云松令"]', 62),
+	('玉楼春', '["春晓曲","惜春容"]', 56),
+	('醉花阴', NULL, 52),
+	('一剪梅', '["腊梅香","玉簟秋"]', 60),
+	('南乡子', '["好离乡","蕉叶怨"]', 56),
+	('定风波', '["定风流","定风波令","卷春空","醉琼枝"]', 62),
+	('桂枝香', '["疏帘淡月"]', 101),
+	('扬州慢', '["郎州慢"]', 98),
+	('兰陵王', NULL, 130),
+	('六州歌头', NULL, 143),
+	('莺啼序', '["丰乐楼"]', 240),
+	('眼儿媚', '["秋波媚","小阑干"]', 48),
+	('阮郎归', '["醉桃源","碧桃春"]', 47),
+	('朝中措', '["照江梅","芙蓉曲"]', 48),
+	('好事近', '["钓船笛","翠圆枝"]', 45),
+	('谒金门', '["空相忆","花自落","垂杨碧"]', 45),
+	('霜天晓角', '["月当窗"]', 43),
+	('柳梢青', '["陇头月","早春怨"]', 49),
+	('钗头凤', '["撷芳词","折红英"]', 60),
+	('忆江南', '["望江南","梦江南","江南好","春去也","谢秋娘"]', 27),
+	('更漏子', NULL, 46),
+	('满庭芳', '["锁阳台","满庭霜","潇湘夜雨","话桐乡"]', 95),
+	('齐天乐', '["台城路","五福降中天","如此江山"]', 102),
+	('唐多令', '["糖多令","南楼令"]', 60),
+	('行香子', NULL, 66),
+	('小重山', '["小冲山","柳色新"]', 58),
+	('风入松', NULL, 76),
+	('洞仙歌', NULL, 83),
+	('暗香', '["红情"]', 97),
+	('疏影', '["绿意"]', 110),
+	('高阳台', '["庆春泽慢"]', 100)`
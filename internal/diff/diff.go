@@ -0,0 +1,100 @@
+// Package diff compares texts character by character, as needed to show how
+// two versions of a poem differ.
+package diff
+
+// Kind is what an Op does to the first text to turn it into the second
+type Kind string
+
+const (
+	Equal  Kind = "equal"  // Text is in both
+	Delete Kind = "delete" // Text is only in the first
+	Insert Kind = "insert" // Text is only in the second
+)
+
+// Op is a run of characters kept, deleted or inserted
+type Op struct {
+	Kind Kind   `json:"op"`
+	Text string `json:"text"`
+}
+
+// maxCells bounds the table of the longest common subsequence of the parts of
+// two texts that differ. Beyond it, the parts are reported as replaced whole.
+const maxCells = 1 << 22
+
+// Chars returns the ops turning a into b, comparing runes. Equal runs are as
+// long as possible: the ops are a longest common subsequence of a and b, with
+// the deleted and inserted runes between its runs.
+func Chars(a, b string) []Op {
+	x, y := []rune(a), []rune(b)
+
+	// Texts that are versions of each other mostly differ in the middle
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	var ops []Op
+	ops = appendOp(ops, Equal, x[:prefix])
+	ops = appendMiddle(ops, x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])
+	return appendOp(ops, Equal, x[len(x)-suffix:])
+}
+
+// appendMiddle appends the ops turning x into y, from the table of their
+// longest common subsequences
+func appendMiddle(ops []Op, x, y []rune) []Op {
+	n, m := len(x), len(y)
+	if n == 0 || m == 0 || n*m > maxCells {
+		ops = appendOp(ops, Delete, x)
+		return appendOp(ops, Insert, y)
+	}
+
+	// lcs[i*(m+1)+j] is the length of the longest common subsequence of x[i:]
+	// and y[j:]
+	lcs := make([]int32, (n+1)*(m+1))
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case x[i] == y[j]:
+				lcs[i*(m+1)+j] = lcs[(i+1)*(m+1)+j+1] + 1
+			case lcs[(i+1)*(m+1)+j] >= lcs[i*(m+1)+j+1]:
+				lcs[i*(m+1)+j] = lcs[(i+1)*(m+1)+j]
+			default:
+				lcs[i*(m+1)+j] = lcs[i*(m+1)+j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case x[i] == y[j]:
+			ops = appendOp(ops, Equal, x[i:i+1])
+			i, j = i+1, j+1
+		case lcs[(i+1)*(m+1)+j] >= lcs[i*(m+1)+j+1]:
+			ops = appendOp(ops, Delete, x[i:i+1])
+			i++
+		default:
+			ops = appendOp(ops, Insert, y[j:j+1])
+			j++
+		}
+	}
+	ops = appendOp(ops, Delete, x[i:])
+	return appendOp(ops, Insert, y[j:])
+}
+
+// appendOp appends text as an op of kind, merging it into the last op if that
+// is of the same kind
+func appendOp(ops []Op, kind Kind, text []rune) []Op {
+	if len(text) == 0 {
+		return ops
+	}
+	if last := len(ops) - 1; last >= 0 && ops[last].Kind == kind {
+		ops[last].Text += string(text)
+		return ops
+	}
+	return append(ops, Op{Kind: kind, Text: string(text)})
+}
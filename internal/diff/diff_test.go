@@ -0,0 +1,82 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChars(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Op
+	}{
+		{
+			name: "identical",
+			a:    "床前明月光",
+			b:    "床前明月光",
+			want: []Op{{Equal, "床前明月光"}},
+		},
+		{
+			name: "one character replaced",
+			a:    "床前明月光",
+			b:    "床前看月光",
+			want: []Op{{Equal, "床前"}, {Delete, "明"}, {Insert, "看"}, {Equal, "月光"}},
+		},
+		{
+			name: "characters inserted",
+			a:    "举头望明月",
+			b:    "举头望山上明月",
+			want: []Op{{Equal, "举头望"}, {Insert, "山上"}, {Equal, "明月"}},
+		},
+		{
+			name: "line deleted",
+			a:    "白日依山尽\n黄河入海流",
+			b:    "白日依山尽",
+			want: []Op{{Equal, "白日依山尽"}, {Delete, "\n黄河入海流"}},
+		},
+		{
+			name: "changes apart",
+			a:    "春眠不觉晓处处闻啼鸟",
+			b:    "春眠不知晓处处闻鸣鸟",
+			want: []Op{{Equal, "春眠不"}, {Delete, "觉"}, {Insert, "知"}, {Equal, "晓处处闻"}, {Delete, "啼"}, {Insert, "鸣"}, {Equal, "鸟"}},
+		},
+		{
+			name: "first empty",
+			a:    "",
+			b:    "静夜思",
+			want: []Op{{Insert, "静夜思"}},
+		},
+		{
+			name: "both empty",
+			a:    "",
+			b:    "",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Chars(tt.a, tt.b))
+		})
+	}
+}
+
+func TestCharsRebuildsBothTexts(t *testing.T) {
+	a := strings.Repeat("明月几时有把酒问青天", 300)
+	b := strings.Replace(a, "青天", "苍天", 7)
+
+	var fromOps, toOps strings.Builder
+	for _, op := range Chars(a, b) {
+		if op.Kind != Insert {
+			fromOps.WriteString(op.Text)
+		}
+		if op.Kind != Delete {
+			toOps.WriteString(op.Text)
+		}
+	}
+	assert.Equal(t, a, fromOps.String())
+	assert.Equal(t, b, toOps.String())
+}
@@ -53,11 +53,12 @@ type DirectiveRoot struct{}
 
 type ComplexityRoot struct {
 	Author struct {
-		Dynasty   func(childComplexity int) int
-		ID        func(childComplexity int) int
-		Name      func(childComplexity int) int
-		PoemCount func(childComplexity int) int
-		Poems     func(childComplexity int, page *int, pageSize *int) int
+		Description func(childComplexity int) int
+		Dynasty     func(childComplexity int) int
+		ID          func(childComplexity int) int
+		Name        func(childComplexity int) int
+		PoemCount   func(childComplexity int) int
+		Poems       func(childComplexity int, page *int, pageSize *int, after *string, before *string) int
 	}
 
 	AuthorConnection struct {
@@ -86,6 +87,27 @@ type ComplexityRoot struct {
 		Dynasty func(childComplexity int) int
 	}
 
+	MatchOffset struct {
+		End   func(childComplexity int) int
+		Field func(childComplexity int) int
+		Line  func(childComplexity int) int
+		Start func(childComplexity int) int
+	}
+
+	Meter struct {
+		Deviations func(childComplexity int) int
+		Pattern    func(childComplexity int) int
+		Status     func(childComplexity int) int
+		Tones      func(childComplexity int) int
+	}
+
+	MeterDeviation struct {
+		Kind     func(childComplexity int) int
+		Line     func(childComplexity int) int
+		Position func(childComplexity int) int
+		Rescued  func(childComplexity int) int
+	}
+
 	PageInfo struct {
 		EndCursor       func(childComplexity int) int
 		HasNextPage     func(childComplexity int) int
@@ -98,6 +120,11 @@ type ComplexityRoot struct {
 		Content func(childComplexity int) int
 		Dynasty func(childComplexity int) int
 		ID      func(childComplexity int) int
+		Meter   func(childComplexity int) int
+		Pinyin  func(childComplexity int, style *model.PinyinStyle) int
+		Rhyme   func(childComplexity int) int
+		Source  func(childComplexity int) int
+		Stanzas func(childComplexity int) int
 		Title   func(childComplexity int) int
 		Type    func(childComplexity int) int
 	}
@@ -109,8 +136,17 @@ type ComplexityRoot struct {
 	}
 
 	PoemEdge struct {
-		Cursor func(childComplexity int) int
-		Node   func(childComplexity int) int
+		Cursor    func(childComplexity int) int
+		Highlight func(childComplexity int) int
+		Matches   func(childComplexity int) int
+		Node      func(childComplexity int) int
+		Score     func(childComplexity int) int
+		Snippet   func(childComplexity int) int
+	}
+
+	PoemPinyin struct {
+		Content func(childComplexity int) int
+		Title   func(childComplexity int) int
 	}
 
 	PoetryType struct {
@@ -125,16 +161,38 @@ type ComplexityRoot struct {
 
 	Query struct {
 		Author      func(childComplexity int, id string, lang *database.Lang) int
-		Authors     func(childComplexity int, lang *database.Lang, page *int, pageSize *int, dynastyID *string) int
+		Authors     func(childComplexity int, lang *database.Lang, page *int, pageSize *int, dynastyID *string, after *string, before *string) int
+		DailyPoem   func(childComplexity int, date *string, timezone *string, lang *database.Lang, dynastyID *string, authorID *string, typeID *string) int
 		Dynasties   func(childComplexity int, lang *database.Lang) int
 		Poem        func(childComplexity int, id string, lang *database.Lang) int
 		PoemTypes   func(childComplexity int, lang *database.Lang) int
-		Poems       func(childComplexity int, lang *database.Lang, page *int, pageSize *int, dynastyID *string, authorID *string, typeID *string) int
+		Poems       func(childComplexity int, lang *database.Lang, page *int, pageSize *int, dynastyID *string, authorID *string, typeID *string, meter *model.MeterFilter, rhyme *string, dataset *string, after *string, before *string) int
 		RandomPoem  func(childComplexity int, lang *database.Lang, dynastyID *string, typeID *string) int
-		SearchPoems func(childComplexity int, query string, lang *database.Lang, searchType *model.SearchType, page *int, pageSize *int) int
+		SearchPoems func(childComplexity int, query string, lang *database.Lang, searchType *model.SearchType, sort *model.SearchSort, page *int, pageSize *int) int
 		Statistics  func(childComplexity int, lang *database.Lang) int
 	}
 
+	Rhyme struct {
+		Groups    func(childComplexity int) int
+		Positions func(childComplexity int) int
+		System    func(childComplexity int) int
+	}
+
+	RhymePosition struct {
+		Char       func(childComplexity int) int
+		Group      func(childComplexity int) int
+		Line       func(childComplexity int) int
+		OutOfRhyme func(childComplexity int) int
+	}
+
+	Source struct {
+		Dataset     func(childComplexity int) int
+		DatasetName func(childComplexity int) int
+		File        func(childComplexity int) int
+		Index       func(childComplexity int) int
+		RecordID    func(childComplexity int) int
+	}
+
 	Statistics struct {
 		PoemsByDynasty func(childComplexity int) int
 		PoemsByType    func(childComplexity int) int
@@ -150,7 +208,7 @@ type ComplexityRoot struct {
 }
 
 type AuthorResolver interface {
-	Poems(ctx context.Context, obj *database.Author, page *int, pageSize *int) (*database.PoemConnection, error)
+	Poems(ctx context.Context, obj *database.Author, page *int, pageSize *int, after *string, before *string) (*database.PoemConnection, error)
 	PoemCount(ctx context.Context, obj *database.Author) (int, error)
 }
 type AuthorEdgeResolver interface {
@@ -162,17 +220,22 @@ type DynastyResolver interface {
 }
 type PoemResolver interface {
 	Content(ctx context.Context, obj *database.Poem) ([]string, error)
+	Stanzas(ctx context.Context, obj *database.Poem) ([][]string, error)
+	Pinyin(ctx context.Context, obj *database.Poem, style *model.PinyinStyle) (*model.PoemPinyin, error)
+	Meter(ctx context.Context, obj *database.Poem) (*model.Meter, error)
+	Rhyme(ctx context.Context, obj *database.Poem) (*model.Rhyme, error)
 }
 type PoetryTypeResolver interface {
 	PoemCount(ctx context.Context, obj *database.PoetryType) (int, error)
 }
 type QueryResolver interface {
 	Poem(ctx context.Context, id string, lang *database.Lang) (*database.Poem, error)
-	Poems(ctx context.Context, lang *database.Lang, page *int, pageSize *int, dynastyID *string, authorID *string, typeID *string) (*database.PoemConnection, error)
-	SearchPoems(ctx context.Context, query string, lang *database.Lang, searchType *model.SearchType, page *int, pageSize *int) (*database.PoemConnection, error)
+	Poems(ctx context.Context, lang *database.Lang, page *int, pageSize *int, dynastyID *string, authorID *string, typeID *string, meter *model.MeterFilter, rhyme *string, dataset *string, after *string, before *string) (*database.PoemConnection, error)
+	SearchPoems(ctx context.Context, query string, lang *database.Lang, searchType *model.SearchType, sort *model.SearchSort, page *int, pageSize *int) (*database.PoemConnection, error)
 	RandomPoem(ctx context.Context, lang *database.Lang, dynastyID *string, typeID *string) (*database.Poem, error)
+	DailyPoem(ctx context.Context, date *string, timezone *string, lang *database.Lang, dynastyID *string, authorID *string, typeID *string) (*database.Poem, error)
 	Author(ctx context.Context, id string, lang *database.Lang) (*database.Author, error)
-	Authors(ctx context.Context, lang *database.Lang, page *int, pageSize *int, dynastyID *string) (*database.AuthorConnection, error)
+	Authors(ctx context.Context, lang *database.Lang, page *int, pageSize *int, dynastyID *string, after *string, before *string) (*database.AuthorConnection, error)
 	Dynasties(ctx context.Context, lang *database.Lang) ([]*database.Dynasty, error)
 	PoemTypes(ctx context.Context, lang *database.Lang) ([]*database.PoetryType, error)
 	Statistics(ctx context.Context, lang *database.Lang) (*database.Statistics, error)
@@ -201,6 +264,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 	_ = ec
 	switch typeName + "." + field {
 
+	case "Author.description":
+		if e.complexity.Author.Description == nil {
+			break
+		}
+
+		return e.complexity.Author.Description(childComplexity), true
 	case "Author.dynasty":
 		if e.complexity.Author.Dynasty == nil {
 			break
@@ -235,7 +304,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Author.Poems(childComplexity, args["page"].(*int), args["pageSize"].(*int)), true
+		return e.complexity.Author.Poems(childComplexity, args["page"].(*int), args["pageSize"].(*int), args["after"].(*string), args["before"].(*string)), true
 
 	case "AuthorConnection.edges":
 		if e.complexity.AuthorConnection.Edges == nil {
@@ -325,6 +394,81 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.DynastyStats.Dynasty(childComplexity), true
 
+	case "MatchOffset.end":
+		if e.complexity.MatchOffset.End == nil {
+			break
+		}
+
+		return e.complexity.MatchOffset.End(childComplexity), true
+	case "MatchOffset.field":
+		if e.complexity.MatchOffset.Field == nil {
+			break
+		}
+
+		return e.complexity.MatchOffset.Field(childComplexity), true
+	case "MatchOffset.line":
+		if e.complexity.MatchOffset.Line == nil {
+			break
+		}
+
+		return e.complexity.MatchOffset.Line(childComplexity), true
+	case "MatchOffset.start":
+		if e.complexity.MatchOffset.Start == nil {
+			break
+		}
+
+		return e.complexity.MatchOffset.Start(childComplexity), true
+
+	case "Meter.deviations":
+		if e.complexity.Meter.Deviations == nil {
+			break
+		}
+
+		return e.complexity.Meter.Deviations(childComplexity), true
+	case "Meter.pattern":
+		if e.complexity.Meter.Pattern == nil {
+			break
+		}
+
+		return e.complexity.Meter.Pattern(childComplexity), true
+	case "Meter.status":
+		if e.complexity.Meter.Status == nil {
+			break
+		}
+
+		return e.complexity.Meter.Status(childComplexity), true
+	case "Meter.tones":
+		if e.complexity.Meter.Tones == nil {
+			break
+		}
+
+		return e.complexity.Meter.Tones(childComplexity), true
+
+	case "MeterDeviation.kind":
+		if e.complexity.MeterDeviation.Kind == nil {
+			break
+		}
+
+		return e.complexity.MeterDeviation.Kind(childComplexity), true
+	case "MeterDeviation.line":
+		if e.complexity.MeterDeviation.Line == nil {
+			break
+		}
+
+		return e.complexity.MeterDeviation.Line(childComplexity), true
+	case "MeterDeviation.position":
+		if e.complexity.MeterDeviation.Position == nil {
+			break
+		}
+
+		return e.complexity.MeterDeviation.Position(childComplexity), true
+	case "MeterDeviation.rescued":
+		if e.complexity.MeterDeviation.Rescued == nil {
+			break
+		}
+
+		return e.complexity.MeterDeviation.Rescued(childComplexity), true
+
 	case "PageInfo.endCursor":
 		if e.complexity.PageInfo.EndCursor == nil {
 			break
@@ -374,6 +518,41 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.complexity.Poem.ID(childComplexity), true
+	case "Poem.meter":
+		if e.complexity.Poem.Meter == nil {
+			break
+		}
+
+		return e.complexity.Poem.Meter(childComplexity), true
+	case "Poem.pinyin":
+		if e.complexity.Poem.Pinyin == nil {
+			break
+		}
+
+		args, err := ec.field_Poem_pinyin_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Poem.Pinyin(childComplexity, args["style"].(*model.PinyinStyle)), true
+	case "Poem.rhyme":
+		if e.complexity.Poem.Rhyme == nil {
+			break
+		}
+
+		return e.complexity.Poem.Rhyme(childComplexity), true
+	case "Poem.source":
+		if e.complexity.Poem.Source == nil {
+			break
+		}
+
+		return e.complexity.Poem.Source(childComplexity), true
+	case "Poem.stanzas":
+		if e.complexity.Poem.Stanzas == nil {
+			break
+		}
+
+		return e.complexity.Poem.Stanzas(childComplexity), true
 	case "Poem.title":
 		if e.complexity.Poem.Title == nil {
 			break
@@ -412,12 +591,49 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.complexity.PoemEdge.Cursor(childComplexity), true
+	case "PoemEdge.highlight":
+		if e.complexity.PoemEdge.Highlight == nil {
+			break
+		}
+
+		return e.complexity.PoemEdge.Highlight(childComplexity), true
+	case "PoemEdge.matches":
+		if e.complexity.PoemEdge.Matches == nil {
+			break
+		}
+
+		return e.complexity.PoemEdge.Matches(childComplexity), true
 	case "PoemEdge.node":
 		if e.complexity.PoemEdge.Node == nil {
 			break
 		}
 
 		return e.complexity.PoemEdge.Node(childComplexity), true
+	case "PoemEdge.score":
+		if e.complexity.PoemEdge.Score == nil {
+			break
+		}
+
+		return e.complexity.PoemEdge.Score(childComplexity), true
+	case "PoemEdge.snippet":
+		if e.complexity.PoemEdge.Snippet == nil {
+			break
+		}
+
+		return e.complexity.PoemEdge.Snippet(childComplexity), true
+
+	case "PoemPinyin.content":
+		if e.complexity.PoemPinyin.Content == nil {
+			break
+		}
+
+		return e.complexity.PoemPinyin.Content(childComplexity), true
+	case "PoemPinyin.title":
+		if e.complexity.PoemPinyin.Title == nil {
+			break
+		}
+
+		return e.complexity.PoemPinyin.Title(childComplexity), true
 
 	case "PoetryType.category":
 		if e.complexity.PoetryType.Category == nil {
@@ -483,7 +699,18 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Query.Authors(childComplexity, args["lang"].(*database.Lang), args["page"].(*int), args["pageSize"].(*int), args["dynastyId"].(*string)), true
+		return e.complexity.Query.Authors(childComplexity, args["lang"].(*database.Lang), args["page"].(*int), args["pageSize"].(*int), args["dynastyId"].(*string), args["after"].(*string), args["before"].(*string)), true
+	case "Query.dailyPoem":
+		if e.complexity.Query.DailyPoem == nil {
+			break
+		}
+
+		args, err := ec.field_Query_dailyPoem_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.DailyPoem(childComplexity, args["date"].(*string), args["timezone"].(*string), args["lang"].(*database.Lang), args["dynastyId"].(*string), args["authorId"].(*string), args["typeId"].(*string)), true
 	case "Query.dynasties":
 		if e.complexity.Query.Dynasties == nil {
 			break
@@ -527,7 +754,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Query.Poems(childComplexity, args["lang"].(*database.Lang), args["page"].(*int), args["pageSize"].(*int), args["dynastyId"].(*string), args["authorId"].(*string), args["typeId"].(*string)), true
+		return e.complexity.Query.Poems(childComplexity, args["lang"].(*database.Lang), args["page"].(*int), args["pageSize"].(*int), args["dynastyId"].(*string), args["authorId"].(*string), args["typeId"].(*string), args["meter"].(*model.MeterFilter), args["rhyme"].(*string), args["dataset"].(*string), args["after"].(*string), args["before"].(*string)), true
 	case "Query.randomPoem":
 		if e.complexity.Query.RandomPoem == nil {
 			break
@@ -549,7 +776,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Query.SearchPoems(childComplexity, args["query"].(string), args["lang"].(*database.Lang), args["searchType"].(*model.SearchType), args["page"].(*int), args["pageSize"].(*int)), true
+		return e.complexity.Query.SearchPoems(childComplexity, args["query"].(string), args["lang"].(*database.Lang), args["searchType"].(*model.SearchType), args["sort"].(*model.SearchSort), args["page"].(*int), args["pageSize"].(*int)), true
 	case "Query.statistics":
 		if e.complexity.Query.Statistics == nil {
 			break
@@ -562,6 +789,81 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Query.Statistics(childComplexity, args["lang"].(*database.Lang)), true
 
+	case "Rhyme.groups":
+		if e.complexity.Rhyme.Groups == nil {
+			break
+		}
+
+		return e.complexity.Rhyme.Groups(childComplexity), true
+	case "Rhyme.positions":
+		if e.complexity.Rhyme.Positions == nil {
+			break
+		}
+
+		return e.complexity.Rhyme.Positions(childComplexity), true
+	case "Rhyme.system":
+		if e.complexity.Rhyme.System == nil {
+			break
+		}
+
+		return e.complexity.Rhyme.System(childComplexity), true
+
+	case "RhymePosition.char":
+		if e.complexity.RhymePosition.Char == nil {
+			break
+		}
+
+		return e.complexity.RhymePosition.Char(childComplexity), true
+	case "RhymePosition.group":
+		if e.complexity.RhymePosition.Group == nil {
+			break
+		}
+
+		return e.complexity.RhymePosition.Group(childComplexity), true
+	case "RhymePosition.line":
+		if e.complexity.RhymePosition.Line == nil {
+			break
+		}
+
+		return e.complexity.RhymePosition.Line(childComplexity), true
+	case "RhymePosition.outOfRhyme":
+		if e.complexity.RhymePosition.OutOfRhyme == nil {
+			break
+		}
+
+		return e.complexity.RhymePosition.OutOfRhyme(childComplexity), true
+
+	case "Source.dataset":
+		if e.complexity.Source.Dataset == nil {
+			break
+		}
+
+		return e.complexity.Source.Dataset(childComplexity), true
+	case "Source.datasetName":
+		if e.complexity.Source.DatasetName == nil {
+			break
+		}
+
+		return e.complexity.Source.DatasetName(childComplexity), true
+	case "Source.file":
+		if e.complexity.Source.File == nil {
+			break
+		}
+
+		return e.complexity.Source.File(childComplexity), true
+	case "Source.index":
+		if e.complexity.Source.Index == nil {
+			break
+		}
+
+		return e.complexity.Source.Index(childComplexity), true
+	case "Source.recordId":
+		if e.complexity.Source.RecordID == nil {
+			break
+		}
+
+		return e.complexity.Source.RecordID(childComplexity), true
+
 	case "Statistics.poemsByDynasty":
 		if e.complexity.Statistics.PoemsByDynasty == nil {
 			break
@@ -703,13 +1005,22 @@ enum Lang {
   ZH_HANS
   """Traditional Chinese (zh-Hant)"""
   ZH_HANT
+  """Traditional Chinese as standardized in Taiwan (zh-TW)"""
+  ZH_TW
+  """Traditional Chinese as standardized in Hong Kong (zh-HK)"""
+  ZH_HK
+  """Japanese Shinjitai glyphs (ja)"""
+  JA
 }
 
 type Query {
   "Get a single poem by ID"
   poem(id: ID!, lang: Lang = ZH_HANS): Poem
 
-  "Get a list of poems with pagination and filters"
+  """
+  Get a list of poems with pagination and filters, ordered by descending ID.
+  Pass an edge cursor as after or before to page by keyset instead of page number.
+  """
   poems(
     lang: Lang = ZH_HANS
     page: Int = 1
@@ -717,13 +1028,23 @@ type Query {
     dynastyId: ID
     authorId: ID
     typeId: ID
+    "Keep poems whose tones follow the templates of 近体诗 this closely"
+    meter: MeterFilter
+    "Keep poems rhyming in this group of 平水韵 (十一尤, or just 尤) or 词林正韵 (第十二部)"
+    rhyme: String
+    "Keep poems of this upstream dataset, by its key in datas.json (e.g. tangsong)"
+    dataset: String
+    after: String
+    before: String
   ): PoemConnection!
 
   "Search poems by query"
   searchPoems(
+    "Terms to match: supports OR, -excluded terms, \"quoted phrases\" and title:/content:/author: prefixes"
     query: String!
     lang: Lang = ZH_HANS
     searchType: SearchType = ALL
+    sort: SearchSort = RELEVANCE
     page: Int = 1
     pageSize: Int = 20
   ): PoemConnection!
@@ -731,15 +1052,33 @@ type Query {
   "Get a random poem"
   randomPoem(lang: Lang = ZH_HANS, dynastyId: ID, typeId: ID): Poem
 
+  """
+  Get the poem of the day, the same all day in either language.
+  date is YYYY-MM-DD and defaults to today in timezone, an IANA name such as Asia/Shanghai (default UTC).
+  """
+  dailyPoem(
+    date: String
+    timezone: String
+    lang: Lang = ZH_HANS
+    dynastyId: ID
+    authorId: ID
+    typeId: ID
+  ): Poem
+
   "Get an author by ID"
   author(id: ID!, lang: Lang = ZH_HANS): Author
 
-  "Get a list of authors"
+  """
+  Get a list of authors, most prolific first.
+  Pass an edge cursor as after or before to page by keyset instead of page number.
+  """
   authors(
     lang: Lang = ZH_HANS
     page: Int = 1
     pageSize: Int = 20
     dynastyId: ID
+    after: String
+    before: String
   ): AuthorConnection!
 
   "Get all dynasties"
@@ -757,6 +1096,46 @@ enum SearchType {
   TITLE
   CONTENT
   AUTHOR
+  """Pinyin of title and content, e.g. jingyesi, jing ye si or jìng yè sī"""
+  PINYIN
+}
+
+enum SearchSort {
+  """Best match first"""
+  RELEVANCE
+  """Poem ID order"""
+  ID
+}
+
+enum PinyinStyle {
+  """Tones as diacritics: jìng yè sī"""
+  MARKS
+  """Tones as trailing digits: jing4 ye4 si1"""
+  NUMBERS
+}
+
+enum MeterStatus {
+  """Every required position follows the template"""
+  STRICT
+  """Every 拗 is offset by a recognized 拗救"""
+  RESCUED
+  """At least one 拗 is not rescued"""
+  BROKEN
+}
+
+enum MeterFilter {
+  STRICT
+  RESCUED
+  """STRICT or RESCUED"""
+  COMPLIANT
+  BROKEN
+}
+
+enum RhymeSystem {
+  """平水韵, the rhymes of 诗"""
+  PINGSHUI
+  """词林正韵, the rhymes of 词"""
+  CILIN
 }
 
 
@@ -764,16 +1143,86 @@ type Poem {
   id: ID!
   title: String!
   content: [String!]!
+  "Content grouped into stanzas, e.g. the 上阕 and 下阕 of a ci or the 章 of a poem from 诗经; a poem without breaks is one stanza"
+  stanzas: [[String!]!]!
   author: Author
   dynasty: Dynasty
   type: PoetryType
+  "Hanyu Pinyin of title and content, one syllable per character"
+  pinyin(style: PinyinStyle = MARKS): PoemPinyin!
+  "Tonal analysis against the templates of 近体诗, null unless the poem has their shape"
+  meter: Meter
+  "Rhyming lines and their groups, null if no two lines rhyme"
+  rhyme: Rhyme
+  "Where the poem comes from in the upstream chinese-poetry data"
+  source: Source!
+}
+
+type PoemPinyin {
+  title: String!
+  content: [String!]!
+}
+
+"A poem checked against the tonal template of 近体诗 it fits best"
+type Meter {
+  status: MeterStatus!
+  "Template, e.g. 仄起首句不入韵"
+  pattern: String!
+  "Tonal pattern of each line: 平, 仄, 中 for either and ？ for unknown"
+  tones: [String!]!
+  deviations: [MeterDeviation!]!
+}
+
+"A place where a poem departs from its template"
+type MeterDeviation {
+  "1-based line number"
+  line: Int!
+  "1-based character position in the line"
+  position: Int!
+  "ao (拗), gu_ping (孤平) or san_ping (三平尾)"
+  kind: String!
+  "Offset by 拗救 in the same or the paired line"
+  rescued: Boolean!
+}
+
+"Where a poem rhymes and in which groups of a rhyme system"
+type Rhyme {
+  system: RhymeSystem!
+  "In order of first use, e.g. 十一尤 or 第十二部"
+  groups: [String!]!
+  positions: [RhymePosition!]!
+}
+
+"A line whose last character rhymes, or should"
+type RhymePosition {
+  "1-based line number"
+  line: Int!
+  char: String!
+  "The rhyme group of the passage the line belongs to"
+  group: String!
+  "出韵: the character is not in group"
+  outOfRhyme: Boolean!
+}
+
+"The upstream record a poem was imported from"
+type Source {
+  "Key of the dataset in datas.json, e.g. tangsong"
+  dataset: String!
+  datasetName: String!
+  "File relative to the data directory"
+  file: String!
+  "ID the record has upstream, if any"
+  recordId: String
+  "0-based position of the record in its file"
+  index: Int!
 }
 
 type Author {
   id: ID!
   name: String!
   dynasty: Dynasty
-  poems(page: Int = 1, pageSize: Int = 20): PoemConnection!
+  description: String
+  poems(page: Int = 1, pageSize: Int = 20, after: String, before: String): PoemConnection!
   poemCount: Int!
 }
 
@@ -806,6 +1255,24 @@ type PoemConnection {
 type PoemEdge {
   node: Poem!
   cursor: String!
+  "Relevance score, higher is better (searchPoems only)"
+  score: Float
+  "Title with matches wrapped in <mark></mark> (searchPoems only)"
+  highlight: String
+  "Content fragment around the best match, marked like highlight (searchPoems only)"
+  snippet: String
+  "Every occurrence of the query in the poem (searchPoems only)"
+  matches: [MatchOffset!]
+}
+
+"Where a search query occurs in a poem; offsets count characters and end is exclusive"
+type MatchOffset {
+  "title, content or author"
+  field: String!
+  "Paragraph index for content matches, 0 otherwise"
+  line: Int!
+  start: Int!
+  end: Int!
 }
 
 type AuthorConnection {
@@ -864,6 +1331,27 @@ func (ec *executionContext) field_Author_poems_args(ctx context.Context, rawArgs
 		return nil, err
 	}
 	args["pageSize"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "after", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["after"] = arg2
+	arg3, err := graphql.ProcessArgField(ctx, rawArgs, "before", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["before"] = arg3
+	return args, nil
+}
+
+func (ec *executionContext) field_Poem_pinyin_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "style", ec.unmarshalOPinyinStyle2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐPinyinStyle)
+	if err != nil {
+		return nil, err
+	}
+	args["style"] = arg0
 	return args, nil
 }
 
@@ -917,6 +1405,52 @@ func (ec *executionContext) field_Query_authors_args(ctx context.Context, rawArg
 		return nil, err
 	}
 	args["dynastyId"] = arg3
+	arg4, err := graphql.ProcessArgField(ctx, rawArgs, "after", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["after"] = arg4
+	arg5, err := graphql.ProcessArgField(ctx, rawArgs, "before", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["before"] = arg5
+	return args, nil
+}
+
+func (ec *executionContext) field_Query_dailyPoem_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "date", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["date"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "timezone", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["timezone"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "lang", ec.unmarshalOLang2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐLang)
+	if err != nil {
+		return nil, err
+	}
+	args["lang"] = arg2
+	arg3, err := graphql.ProcessArgField(ctx, rawArgs, "dynastyId", ec.unmarshalOID2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["dynastyId"] = arg3
+	arg4, err := graphql.ProcessArgField(ctx, rawArgs, "authorId", ec.unmarshalOID2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["authorId"] = arg4
+	arg5, err := graphql.ProcessArgField(ctx, rawArgs, "typeId", ec.unmarshalOID2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["typeId"] = arg5
 	return args, nil
 }
 
@@ -991,6 +1525,31 @@ func (ec *executionContext) field_Query_poems_args(ctx context.Context, rawArgs
 		return nil, err
 	}
 	args["typeId"] = arg5
+	arg6, err := graphql.ProcessArgField(ctx, rawArgs, "meter", ec.unmarshalOMeterFilter2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐMeterFilter)
+	if err != nil {
+		return nil, err
+	}
+	args["meter"] = arg6
+	arg7, err := graphql.ProcessArgField(ctx, rawArgs, "rhyme", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["rhyme"] = arg7
+	arg8, err := graphql.ProcessArgField(ctx, rawArgs, "dataset", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["dataset"] = arg8
+	arg9, err := graphql.ProcessArgField(ctx, rawArgs, "after", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["after"] = arg9
+	arg10, err := graphql.ProcessArgField(ctx, rawArgs, "before", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["before"] = arg10
 	return args, nil
 }
 
@@ -1033,16 +1592,21 @@ func (ec *executionContext) field_Query_searchPoems_args(ctx context.Context, ra
 		return nil, err
 	}
 	args["searchType"] = arg2
-	arg3, err := graphql.ProcessArgField(ctx, rawArgs, "page", ec.unmarshalOInt2ᚖint)
+	arg3, err := graphql.ProcessArgField(ctx, rawArgs, "sort", ec.unmarshalOSearchSort2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐSearchSort)
+	if err != nil {
+		return nil, err
+	}
+	args["sort"] = arg3
+	arg4, err := graphql.ProcessArgField(ctx, rawArgs, "page", ec.unmarshalOInt2ᚖint)
 	if err != nil {
 		return nil, err
 	}
-	args["page"] = arg3
-	arg4, err := graphql.ProcessArgField(ctx, rawArgs, "pageSize", ec.unmarshalOInt2ᚖint)
+	args["page"] = arg4
+	arg5, err := graphql.ProcessArgField(ctx, rawArgs, "pageSize", ec.unmarshalOInt2ᚖint)
 	if err != nil {
 		return nil, err
 	}
-	args["pageSize"] = arg4
+	args["pageSize"] = arg5
 	return args, nil
 }
 
@@ -1212,37 +1776,66 @@ func (ec *executionContext) fieldContext_Author_dynasty(_ context.Context, field
 	return fc, nil
 }
 
-func (ec *executionContext) _Author_poems(ctx context.Context, field graphql.CollectedField, obj *database.Author) (ret graphql.Marshaler) {
+func (ec *executionContext) _Author_description(ctx context.Context, field graphql.CollectedField, obj *database.Author) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Author_poems,
+		ec.fieldContext_Author_description,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Author().Poems(ctx, obj, fc.Args["page"].(*int), fc.Args["pageSize"].(*int))
+			return obj.Description, nil
 		},
 		nil,
-		ec.marshalNPoemConnection2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoemConnection,
-		true,
+		ec.marshalOString2ᚖstring,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Author_poems(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Author_description(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Author",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "edges":
-				return ec.fieldContext_PoemConnection_edges(ctx, field)
-			case "pageInfo":
-				return ec.fieldContext_PoemConnection_pageInfo(ctx, field)
-			case "totalCount":
-				return ec.fieldContext_PoemConnection_totalCount(ctx, field)
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Author_poems(ctx context.Context, field graphql.CollectedField, obj *database.Author) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Author_poems,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Author().Poems(ctx, obj, fc.Args["page"].(*int), fc.Args["pageSize"].(*int), fc.Args["after"].(*string), fc.Args["before"].(*string))
+		},
+		nil,
+		ec.marshalNPoemConnection2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoemConnection,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Author_poems(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Author",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "edges":
+				return ec.fieldContext_PoemConnection_edges(ctx, field)
+			case "pageInfo":
+				return ec.fieldContext_PoemConnection_pageInfo(ctx, field)
+			case "totalCount":
+				return ec.fieldContext_PoemConnection_totalCount(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type PoemConnection", field.Name)
 		},
@@ -1423,6 +2016,8 @@ func (ec *executionContext) fieldContext_AuthorEdge_node(_ context.Context, fiel
 				return ec.fieldContext_Author_name(ctx, field)
 			case "dynasty":
 				return ec.fieldContext_Author_dynasty(ctx, field)
+			case "description":
+				return ec.fieldContext_Author_description(ctx, field)
 			case "poems":
 				return ec.fieldContext_Author_poems(ctx, field)
 			case "poemCount":
@@ -1740,159 +2335,159 @@ func (ec *executionContext) fieldContext_DynastyStats_count(_ context.Context, f
 	return fc, nil
 }
 
-func (ec *executionContext) _PageInfo_hasNextPage(ctx context.Context, field graphql.CollectedField, obj *database.PageInfo) (ret graphql.Marshaler) {
+func (ec *executionContext) _MatchOffset_field(ctx context.Context, field graphql.CollectedField, obj *database.MatchOffset) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PageInfo_hasNextPage,
+		ec.fieldContext_MatchOffset_field,
 		func(ctx context.Context) (any, error) {
-			return obj.HasNextPage, nil
+			return obj.Field, nil
 		},
 		nil,
-		ec.marshalNBoolean2bool,
+		ec.marshalNString2string,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_PageInfo_hasNextPage(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_MatchOffset_field(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PageInfo",
+		Object:     "MatchOffset",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Boolean does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PageInfo_hasPreviousPage(ctx context.Context, field graphql.CollectedField, obj *database.PageInfo) (ret graphql.Marshaler) {
+func (ec *executionContext) _MatchOffset_line(ctx context.Context, field graphql.CollectedField, obj *database.MatchOffset) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PageInfo_hasPreviousPage,
+		ec.fieldContext_MatchOffset_line,
 		func(ctx context.Context) (any, error) {
-			return obj.HasPreviousPage, nil
+			return obj.Line, nil
 		},
 		nil,
-		ec.marshalNBoolean2bool,
+		ec.marshalNInt2int,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_PageInfo_hasPreviousPage(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_MatchOffset_line(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PageInfo",
+		Object:     "MatchOffset",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Boolean does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PageInfo_startCursor(ctx context.Context, field graphql.CollectedField, obj *database.PageInfo) (ret graphql.Marshaler) {
+func (ec *executionContext) _MatchOffset_start(ctx context.Context, field graphql.CollectedField, obj *database.MatchOffset) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PageInfo_startCursor,
+		ec.fieldContext_MatchOffset_start,
 		func(ctx context.Context) (any, error) {
-			return obj.StartCursor, nil
+			return obj.Start, nil
 		},
 		nil,
-		ec.marshalOString2ᚖstring,
+		ec.marshalNInt2int,
+		true,
 		true,
-		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_PageInfo_startCursor(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_MatchOffset_start(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PageInfo",
+		Object:     "MatchOffset",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PageInfo_endCursor(ctx context.Context, field graphql.CollectedField, obj *database.PageInfo) (ret graphql.Marshaler) {
+func (ec *executionContext) _MatchOffset_end(ctx context.Context, field graphql.CollectedField, obj *database.MatchOffset) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PageInfo_endCursor,
+		ec.fieldContext_MatchOffset_end,
 		func(ctx context.Context) (any, error) {
-			return obj.EndCursor, nil
+			return obj.End, nil
 		},
 		nil,
-		ec.marshalOString2ᚖstring,
+		ec.marshalNInt2int,
+		true,
 		true,
-		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_PageInfo_endCursor(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_MatchOffset_end(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PageInfo",
+		Object:     "MatchOffset",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Poem_id(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
+func (ec *executionContext) _Meter_status(ctx context.Context, field graphql.CollectedField, obj *model.Meter) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Poem_id,
+		ec.fieldContext_Meter_status,
 		func(ctx context.Context) (any, error) {
-			return obj.ID, nil
+			return obj.Status, nil
 		},
 		nil,
-		ec.marshalNID2int64,
+		ec.marshalNMeterStatus2githubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐMeterStatus,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Poem_id(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Meter_status(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Poem",
+		Object:     "Meter",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ID does not have child fields")
+			return nil, errors.New("field of type MeterStatus does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Poem_title(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
+func (ec *executionContext) _Meter_pattern(ctx context.Context, field graphql.CollectedField, obj *model.Meter) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Poem_title,
+		ec.fieldContext_Meter_pattern,
 		func(ctx context.Context) (any, error) {
-			return obj.Title, nil
+			return obj.Pattern, nil
 		},
 		nil,
 		ec.marshalNString2string,
@@ -1901,9 +2496,9 @@ func (ec *executionContext) _Poem_title(ctx context.Context, field graphql.Colle
 	)
 }
 
-func (ec *executionContext) fieldContext_Poem_title(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Meter_pattern(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Poem",
+		Object:     "Meter",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -1914,14 +2509,14 @@ func (ec *executionContext) fieldContext_Poem_title(_ context.Context, field gra
 	return fc, nil
 }
 
-func (ec *executionContext) _Poem_content(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
+func (ec *executionContext) _Meter_tones(ctx context.Context, field graphql.CollectedField, obj *model.Meter) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Poem_content,
+		ec.fieldContext_Meter_tones,
 		func(ctx context.Context) (any, error) {
-			return ec.resolvers.Poem().Content(ctx, obj)
+			return obj.Tones, nil
 		},
 		nil,
 		ec.marshalNString2ᚕstringᚄ,
@@ -1930,12 +2525,12 @@ func (ec *executionContext) _Poem_content(ctx context.Context, field graphql.Col
 	)
 }
 
-func (ec *executionContext) fieldContext_Poem_content(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Meter_tones(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Poem",
+		Object:     "Meter",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			return nil, errors.New("field of type String does not have child fields")
 		},
@@ -1943,302 +2538,238 @@ func (ec *executionContext) fieldContext_Poem_content(_ context.Context, field g
 	return fc, nil
 }
 
-func (ec *executionContext) _Poem_author(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
+func (ec *executionContext) _Meter_deviations(ctx context.Context, field graphql.CollectedField, obj *model.Meter) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Poem_author,
+		ec.fieldContext_Meter_deviations,
 		func(ctx context.Context) (any, error) {
-			return obj.Author, nil
+			return obj.Deviations, nil
 		},
 		nil,
-		ec.marshalOAuthor2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐAuthor,
+		ec.marshalNMeterDeviation2ᚕᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐMeterDeviationᚄ,
+		true,
 		true,
-		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Poem_author(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Meter_deviations(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Poem",
+		Object:     "Meter",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "id":
-				return ec.fieldContext_Author_id(ctx, field)
-			case "name":
-				return ec.fieldContext_Author_name(ctx, field)
-			case "dynasty":
-				return ec.fieldContext_Author_dynasty(ctx, field)
-			case "poems":
-				return ec.fieldContext_Author_poems(ctx, field)
-			case "poemCount":
-				return ec.fieldContext_Author_poemCount(ctx, field)
+			case "line":
+				return ec.fieldContext_MeterDeviation_line(ctx, field)
+			case "position":
+				return ec.fieldContext_MeterDeviation_position(ctx, field)
+			case "kind":
+				return ec.fieldContext_MeterDeviation_kind(ctx, field)
+			case "rescued":
+				return ec.fieldContext_MeterDeviation_rescued(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type Author", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type MeterDeviation", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Poem_dynasty(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
+func (ec *executionContext) _MeterDeviation_line(ctx context.Context, field graphql.CollectedField, obj *model.MeterDeviation) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Poem_dynasty,
+		ec.fieldContext_MeterDeviation_line,
 		func(ctx context.Context) (any, error) {
-			return obj.Dynasty, nil
+			return obj.Line, nil
 		},
 		nil,
-		ec.marshalODynasty2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐDynasty,
+		ec.marshalNInt2int,
+		true,
 		true,
-		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Poem_dynasty(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_MeterDeviation_line(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Poem",
+		Object:     "MeterDeviation",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_Dynasty_id(ctx, field)
-			case "name":
-				return ec.fieldContext_Dynasty_name(ctx, field)
-			case "nameEn":
-				return ec.fieldContext_Dynasty_nameEn(ctx, field)
-			case "startYear":
-				return ec.fieldContext_Dynasty_startYear(ctx, field)
-			case "endYear":
-				return ec.fieldContext_Dynasty_endYear(ctx, field)
-			case "poemCount":
-				return ec.fieldContext_Dynasty_poemCount(ctx, field)
-			case "authorCount":
-				return ec.fieldContext_Dynasty_authorCount(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Dynasty", field.Name)
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Poem_type(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
+func (ec *executionContext) _MeterDeviation_position(ctx context.Context, field graphql.CollectedField, obj *model.MeterDeviation) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Poem_type,
+		ec.fieldContext_MeterDeviation_position,
 		func(ctx context.Context) (any, error) {
-			return obj.Type, nil
+			return obj.Position, nil
 		},
 		nil,
-		ec.marshalOPoetryType2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoetryType,
+		ec.marshalNInt2int,
+		true,
 		true,
-		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Poem_type(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_MeterDeviation_position(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Poem",
+		Object:     "MeterDeviation",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_PoetryType_id(ctx, field)
-			case "name":
-				return ec.fieldContext_PoetryType_name(ctx, field)
-			case "category":
-				return ec.fieldContext_PoetryType_category(ctx, field)
-			case "lines":
-				return ec.fieldContext_PoetryType_lines(ctx, field)
-			case "charsPerLine":
-				return ec.fieldContext_PoetryType_charsPerLine(ctx, field)
-			case "description":
-				return ec.fieldContext_PoetryType_description(ctx, field)
-			case "poemCount":
-				return ec.fieldContext_PoetryType_poemCount(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PoetryType", field.Name)
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PoemConnection_edges(ctx context.Context, field graphql.CollectedField, obj *database.PoemConnection) (ret graphql.Marshaler) {
+func (ec *executionContext) _MeterDeviation_kind(ctx context.Context, field graphql.CollectedField, obj *model.MeterDeviation) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PoemConnection_edges,
+		ec.fieldContext_MeterDeviation_kind,
 		func(ctx context.Context) (any, error) {
-			return obj.Edges, nil
+			return obj.Kind, nil
 		},
 		nil,
-		ec.marshalNPoemEdge2ᚕgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoemEdgeᚄ,
+		ec.marshalNString2string,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_PoemConnection_edges(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_MeterDeviation_kind(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PoemConnection",
+		Object:     "MeterDeviation",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "node":
-				return ec.fieldContext_PoemEdge_node(ctx, field)
-			case "cursor":
-				return ec.fieldContext_PoemEdge_cursor(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PoemEdge", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PoemConnection_pageInfo(ctx context.Context, field graphql.CollectedField, obj *database.PoemConnection) (ret graphql.Marshaler) {
+func (ec *executionContext) _MeterDeviation_rescued(ctx context.Context, field graphql.CollectedField, obj *model.MeterDeviation) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PoemConnection_pageInfo,
+		ec.fieldContext_MeterDeviation_rescued,
 		func(ctx context.Context) (any, error) {
-			return obj.PageInfo, nil
+			return obj.Rescued, nil
 		},
 		nil,
-		ec.marshalNPageInfo2githubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPageInfo,
+		ec.marshalNBoolean2bool,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_PoemConnection_pageInfo(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_MeterDeviation_rescued(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PoemConnection",
+		Object:     "MeterDeviation",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "hasNextPage":
-				return ec.fieldContext_PageInfo_hasNextPage(ctx, field)
-			case "hasPreviousPage":
-				return ec.fieldContext_PageInfo_hasPreviousPage(ctx, field)
-			case "startCursor":
-				return ec.fieldContext_PageInfo_startCursor(ctx, field)
-			case "endCursor":
-				return ec.fieldContext_PageInfo_endCursor(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PoemConnection_totalCount(ctx context.Context, field graphql.CollectedField, obj *database.PoemConnection) (ret graphql.Marshaler) {
+func (ec *executionContext) _PageInfo_hasNextPage(ctx context.Context, field graphql.CollectedField, obj *database.PageInfo) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PoemConnection_totalCount,
+		ec.fieldContext_PageInfo_hasNextPage,
 		func(ctx context.Context) (any, error) {
-			return obj.TotalCount, nil
+			return obj.HasNextPage, nil
 		},
 		nil,
-		ec.marshalNInt2int,
+		ec.marshalNBoolean2bool,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_PoemConnection_totalCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PageInfo_hasNextPage(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PoemConnection",
+		Object:     "PageInfo",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PoemEdge_node(ctx context.Context, field graphql.CollectedField, obj *database.PoemEdge) (ret graphql.Marshaler) {
+func (ec *executionContext) _PageInfo_hasPreviousPage(ctx context.Context, field graphql.CollectedField, obj *database.PageInfo) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PoemEdge_node,
+		ec.fieldContext_PageInfo_hasPreviousPage,
 		func(ctx context.Context) (any, error) {
-			return obj.Node, nil
+			return obj.HasPreviousPage, nil
 		},
 		nil,
-		ec.marshalNPoem2githubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoem,
+		ec.marshalNBoolean2bool,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_PoemEdge_node(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PageInfo_hasPreviousPage(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PoemEdge",
+		Object:     "PageInfo",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_Poem_id(ctx, field)
-			case "title":
-				return ec.fieldContext_Poem_title(ctx, field)
-			case "content":
-				return ec.fieldContext_Poem_content(ctx, field)
-			case "author":
-				return ec.fieldContext_Poem_author(ctx, field)
-			case "dynasty":
-				return ec.fieldContext_Poem_dynasty(ctx, field)
-			case "type":
-				return ec.fieldContext_Poem_type(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Poem", field.Name)
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PoemEdge_cursor(ctx context.Context, field graphql.CollectedField, obj *database.PoemEdge) (ret graphql.Marshaler) {
+func (ec *executionContext) _PageInfo_startCursor(ctx context.Context, field graphql.CollectedField, obj *database.PageInfo) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PoemEdge_cursor,
+		ec.fieldContext_PageInfo_startCursor,
 		func(ctx context.Context) (any, error) {
-			return obj.Cursor, nil
+			return obj.StartCursor, nil
 		},
 		nil,
-		ec.marshalNString2string,
-		true,
+		ec.marshalOString2ᚖstring,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_PoemEdge_cursor(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PageInfo_startCursor(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PoemEdge",
+		Object:     "PageInfo",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2249,72 +2780,72 @@ func (ec *executionContext) fieldContext_PoemEdge_cursor(_ context.Context, fiel
 	return fc, nil
 }
 
-func (ec *executionContext) _PoetryType_id(ctx context.Context, field graphql.CollectedField, obj *database.PoetryType) (ret graphql.Marshaler) {
+func (ec *executionContext) _PageInfo_endCursor(ctx context.Context, field graphql.CollectedField, obj *database.PageInfo) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PoetryType_id,
+		ec.fieldContext_PageInfo_endCursor,
 		func(ctx context.Context) (any, error) {
-			return obj.ID, nil
+			return obj.EndCursor, nil
 		},
 		nil,
-		ec.marshalNID2int64,
-		true,
+		ec.marshalOString2ᚖstring,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_PoetryType_id(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PageInfo_endCursor(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PoetryType",
+		Object:     "PageInfo",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ID does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PoetryType_name(ctx context.Context, field graphql.CollectedField, obj *database.PoetryType) (ret graphql.Marshaler) {
+func (ec *executionContext) _Poem_id(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PoetryType_name,
+		ec.fieldContext_Poem_id,
 		func(ctx context.Context) (any, error) {
-			return obj.Name, nil
+			return obj.ID, nil
 		},
 		nil,
-		ec.marshalNString2string,
+		ec.marshalNID2int64,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_PoetryType_name(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Poem_id(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PoetryType",
+		Object:     "Poem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type ID does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PoetryType_category(ctx context.Context, field graphql.CollectedField, obj *database.PoetryType) (ret graphql.Marshaler) {
+func (ec *executionContext) _Poem_title(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PoetryType_category,
+		ec.fieldContext_Poem_title,
 		func(ctx context.Context) (any, error) {
-			return obj.Category, nil
+			return obj.Title, nil
 		},
 		nil,
 		ec.marshalNString2string,
@@ -2323,9 +2854,9 @@ func (ec *executionContext) _PoetryType_category(ctx context.Context, field grap
 	)
 }
 
-func (ec *executionContext) fieldContext_PoetryType_category(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Poem_title(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PoetryType",
+		Object:     "Poem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2336,210 +2867,228 @@ func (ec *executionContext) fieldContext_PoetryType_category(_ context.Context,
 	return fc, nil
 }
 
-func (ec *executionContext) _PoetryType_lines(ctx context.Context, field graphql.CollectedField, obj *database.PoetryType) (ret graphql.Marshaler) {
+func (ec *executionContext) _Poem_content(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PoetryType_lines,
+		ec.fieldContext_Poem_content,
 		func(ctx context.Context) (any, error) {
-			return obj.Lines, nil
+			return ec.resolvers.Poem().Content(ctx, obj)
 		},
 		nil,
-		ec.marshalOInt2ᚖint,
+		ec.marshalNString2ᚕstringᚄ,
+		true,
 		true,
-		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_PoetryType_lines(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Poem_content(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PoetryType",
+		Object:     "Poem",
 		Field:      field,
-		IsMethod:   false,
-		IsResolver: false,
+		IsMethod:   true,
+		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PoetryType_charsPerLine(ctx context.Context, field graphql.CollectedField, obj *database.PoetryType) (ret graphql.Marshaler) {
+func (ec *executionContext) _Poem_stanzas(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PoetryType_charsPerLine,
+		ec.fieldContext_Poem_stanzas,
 		func(ctx context.Context) (any, error) {
-			return obj.CharsPerLine, nil
+			return ec.resolvers.Poem().Stanzas(ctx, obj)
 		},
 		nil,
-		ec.marshalOInt2ᚖint,
+		ec.marshalNString2ᚕᚕstringᚄᚄ,
+		true,
 		true,
-		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_PoetryType_charsPerLine(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Poem_stanzas(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PoetryType",
+		Object:     "Poem",
 		Field:      field,
-		IsMethod:   false,
-		IsResolver: false,
+		IsMethod:   true,
+		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PoetryType_description(ctx context.Context, field graphql.CollectedField, obj *database.PoetryType) (ret graphql.Marshaler) {
+func (ec *executionContext) _Poem_author(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PoetryType_description,
+		ec.fieldContext_Poem_author,
 		func(ctx context.Context) (any, error) {
-			return obj.Description, nil
+			return obj.Author, nil
 		},
 		nil,
-		ec.marshalOString2ᚖstring,
+		ec.marshalOAuthor2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐAuthor,
 		true,
 		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_PoetryType_description(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Poem_author(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PoetryType",
+		Object:     "Poem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Author_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Author_name(ctx, field)
+			case "dynasty":
+				return ec.fieldContext_Author_dynasty(ctx, field)
+			case "description":
+				return ec.fieldContext_Author_description(ctx, field)
+			case "poems":
+				return ec.fieldContext_Author_poems(ctx, field)
+			case "poemCount":
+				return ec.fieldContext_Author_poemCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Author", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PoetryType_poemCount(ctx context.Context, field graphql.CollectedField, obj *database.PoetryType) (ret graphql.Marshaler) {
+func (ec *executionContext) _Poem_dynasty(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_PoetryType_poemCount,
+		ec.fieldContext_Poem_dynasty,
 		func(ctx context.Context) (any, error) {
-			return ec.resolvers.PoetryType().PoemCount(ctx, obj)
+			return obj.Dynasty, nil
 		},
 		nil,
-		ec.marshalNInt2int,
-		true,
+		ec.marshalODynasty2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐDynasty,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_PoetryType_poemCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Poem_dynasty(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PoetryType",
+		Object:     "Poem",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Dynasty_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Dynasty_name(ctx, field)
+			case "nameEn":
+				return ec.fieldContext_Dynasty_nameEn(ctx, field)
+			case "startYear":
+				return ec.fieldContext_Dynasty_startYear(ctx, field)
+			case "endYear":
+				return ec.fieldContext_Dynasty_endYear(ctx, field)
+			case "poemCount":
+				return ec.fieldContext_Dynasty_poemCount(ctx, field)
+			case "authorCount":
+				return ec.fieldContext_Dynasty_authorCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Dynasty", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_poem(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Poem_type(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_poem,
+		ec.fieldContext_Poem_type,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().Poem(ctx, fc.Args["id"].(string), fc.Args["lang"].(*database.Lang))
+			return obj.Type, nil
 		},
 		nil,
-		ec.marshalOPoem2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoem,
+		ec.marshalOPoetryType2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoetryType,
 		true,
 		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_poem(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Poem_type(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Poem",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
 			case "id":
-				return ec.fieldContext_Poem_id(ctx, field)
-			case "title":
-				return ec.fieldContext_Poem_title(ctx, field)
-			case "content":
-				return ec.fieldContext_Poem_content(ctx, field)
-			case "author":
-				return ec.fieldContext_Poem_author(ctx, field)
-			case "dynasty":
-				return ec.fieldContext_Poem_dynasty(ctx, field)
-			case "type":
-				return ec.fieldContext_Poem_type(ctx, field)
+				return ec.fieldContext_PoetryType_id(ctx, field)
+			case "name":
+				return ec.fieldContext_PoetryType_name(ctx, field)
+			case "category":
+				return ec.fieldContext_PoetryType_category(ctx, field)
+			case "lines":
+				return ec.fieldContext_PoetryType_lines(ctx, field)
+			case "charsPerLine":
+				return ec.fieldContext_PoetryType_charsPerLine(ctx, field)
+			case "description":
+				return ec.fieldContext_PoetryType_description(ctx, field)
+			case "poemCount":
+				return ec.fieldContext_PoetryType_poemCount(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type Poem", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type PoetryType", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_poem_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_poems(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Poem_pinyin(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_poems,
+		ec.fieldContext_Poem_pinyin,
 		func(ctx context.Context) (any, error) {
 			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().Poems(ctx, fc.Args["lang"].(*database.Lang), fc.Args["page"].(*int), fc.Args["pageSize"].(*int), fc.Args["dynastyId"].(*string), fc.Args["authorId"].(*string), fc.Args["typeId"].(*string))
+			return ec.resolvers.Poem().Pinyin(ctx, obj, fc.Args["style"].(*model.PinyinStyle))
 		},
 		nil,
-		ec.marshalNPoemConnection2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoemConnection,
+		ec.marshalNPoemPinyin2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐPoemPinyin,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_poems(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Poem_pinyin(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Poem",
 		Field:      field,
 		IsMethod:   true,
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "edges":
-				return ec.fieldContext_PoemConnection_edges(ctx, field)
-			case "pageInfo":
-				return ec.fieldContext_PoemConnection_pageInfo(ctx, field)
-			case "totalCount":
-				return ec.fieldContext_PoemConnection_totalCount(ctx, field)
+			case "title":
+				return ec.fieldContext_PoemPinyin_title(ctx, field)
+			case "content":
+				return ec.fieldContext_PoemPinyin_content(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type PoemConnection", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type PoemPinyin", field.Name)
 		},
 	}
 	defer func() {
@@ -2549,489 +3098,1735 @@ func (ec *executionContext) fieldContext_Query_poems(ctx context.Context, field
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_poems_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Poem_pinyin_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_searchPoems(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Poem_meter(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_searchPoems,
+		ec.fieldContext_Poem_meter,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().SearchPoems(ctx, fc.Args["query"].(string), fc.Args["lang"].(*database.Lang), fc.Args["searchType"].(*model.SearchType), fc.Args["page"].(*int), fc.Args["pageSize"].(*int))
+			return ec.resolvers.Poem().Meter(ctx, obj)
 		},
 		nil,
-		ec.marshalNPoemConnection2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoemConnection,
-		true,
+		ec.marshalOMeter2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐMeter,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_searchPoems(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Poem_meter(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Poem",
 		Field:      field,
 		IsMethod:   true,
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "edges":
-				return ec.fieldContext_PoemConnection_edges(ctx, field)
-			case "pageInfo":
-				return ec.fieldContext_PoemConnection_pageInfo(ctx, field)
-			case "totalCount":
-				return ec.fieldContext_PoemConnection_totalCount(ctx, field)
+			case "status":
+				return ec.fieldContext_Meter_status(ctx, field)
+			case "pattern":
+				return ec.fieldContext_Meter_pattern(ctx, field)
+			case "tones":
+				return ec.fieldContext_Meter_tones(ctx, field)
+			case "deviations":
+				return ec.fieldContext_Meter_deviations(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type PoemConnection", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Meter", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_searchPoems_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_randomPoem(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Poem_rhyme(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_randomPoem,
+		ec.fieldContext_Poem_rhyme,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().RandomPoem(ctx, fc.Args["lang"].(*database.Lang), fc.Args["dynastyId"].(*string), fc.Args["typeId"].(*string))
+			return ec.resolvers.Poem().Rhyme(ctx, obj)
 		},
 		nil,
-		ec.marshalOPoem2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoem,
+		ec.marshalORhyme2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐRhyme,
 		true,
 		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_randomPoem(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Poem_rhyme(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Poem",
 		Field:      field,
 		IsMethod:   true,
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "id":
-				return ec.fieldContext_Poem_id(ctx, field)
-			case "title":
-				return ec.fieldContext_Poem_title(ctx, field)
-			case "content":
-				return ec.fieldContext_Poem_content(ctx, field)
-			case "author":
-				return ec.fieldContext_Poem_author(ctx, field)
-			case "dynasty":
-				return ec.fieldContext_Poem_dynasty(ctx, field)
-			case "type":
-				return ec.fieldContext_Poem_type(ctx, field)
+			case "system":
+				return ec.fieldContext_Rhyme_system(ctx, field)
+			case "groups":
+				return ec.fieldContext_Rhyme_groups(ctx, field)
+			case "positions":
+				return ec.fieldContext_Rhyme_positions(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type Poem", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Rhyme", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_randomPoem_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_author(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Poem_source(ctx context.Context, field graphql.CollectedField, obj *database.Poem) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_author,
+		ec.fieldContext_Poem_source,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().Author(ctx, fc.Args["id"].(string), fc.Args["lang"].(*database.Lang))
+			return obj.Source(), nil
 		},
 		nil,
-		ec.marshalOAuthor2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐAuthor,
+		ec.marshalNSource2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐSource,
+		true,
 		true,
-		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_author(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Poem_source(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Poem",
 		Field:      field,
 		IsMethod:   true,
-		IsResolver: true,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "id":
-				return ec.fieldContext_Author_id(ctx, field)
-			case "name":
-				return ec.fieldContext_Author_name(ctx, field)
-			case "dynasty":
-				return ec.fieldContext_Author_dynasty(ctx, field)
-			case "poems":
-				return ec.fieldContext_Author_poems(ctx, field)
-			case "poemCount":
-				return ec.fieldContext_Author_poemCount(ctx, field)
+			case "dataset":
+				return ec.fieldContext_Source_dataset(ctx, field)
+			case "datasetName":
+				return ec.fieldContext_Source_datasetName(ctx, field)
+			case "file":
+				return ec.fieldContext_Source_file(ctx, field)
+			case "recordId":
+				return ec.fieldContext_Source_recordId(ctx, field)
+			case "index":
+				return ec.fieldContext_Source_index(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type Author", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Source", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_author_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_authors(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _PoemConnection_edges(ctx context.Context, field graphql.CollectedField, obj *database.PoemConnection) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_authors,
+		ec.fieldContext_PoemConnection_edges,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().Authors(ctx, fc.Args["lang"].(*database.Lang), fc.Args["page"].(*int), fc.Args["pageSize"].(*int), fc.Args["dynastyId"].(*string))
+			return obj.Edges, nil
 		},
 		nil,
-		ec.marshalNAuthorConnection2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐAuthorConnection,
+		ec.marshalNPoemEdge2ᚕgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoemEdgeᚄ,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_authors(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PoemConnection_edges(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "PoemConnection",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "edges":
-				return ec.fieldContext_AuthorConnection_edges(ctx, field)
-			case "pageInfo":
-				return ec.fieldContext_AuthorConnection_pageInfo(ctx, field)
-			case "totalCount":
-				return ec.fieldContext_AuthorConnection_totalCount(ctx, field)
+			case "node":
+				return ec.fieldContext_PoemEdge_node(ctx, field)
+			case "cursor":
+				return ec.fieldContext_PoemEdge_cursor(ctx, field)
+			case "score":
+				return ec.fieldContext_PoemEdge_score(ctx, field)
+			case "highlight":
+				return ec.fieldContext_PoemEdge_highlight(ctx, field)
+			case "snippet":
+				return ec.fieldContext_PoemEdge_snippet(ctx, field)
+			case "matches":
+				return ec.fieldContext_PoemEdge_matches(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type AuthorConnection", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type PoemEdge", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_authors_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_dynasties(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _PoemConnection_pageInfo(ctx context.Context, field graphql.CollectedField, obj *database.PoemConnection) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_dynasties,
+		ec.fieldContext_PoemConnection_pageInfo,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().Dynasties(ctx, fc.Args["lang"].(*database.Lang))
+			return obj.PageInfo, nil
 		},
 		nil,
-		ec.marshalNDynasty2ᚕᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐDynastyᚄ,
+		ec.marshalNPageInfo2githubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPageInfo,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_dynasties(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PoemConnection_pageInfo(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "PoemConnection",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "id":
-				return ec.fieldContext_Dynasty_id(ctx, field)
-			case "name":
-				return ec.fieldContext_Dynasty_name(ctx, field)
-			case "nameEn":
-				return ec.fieldContext_Dynasty_nameEn(ctx, field)
-			case "startYear":
-				return ec.fieldContext_Dynasty_startYear(ctx, field)
-			case "endYear":
-				return ec.fieldContext_Dynasty_endYear(ctx, field)
-			case "poemCount":
-				return ec.fieldContext_Dynasty_poemCount(ctx, field)
-			case "authorCount":
-				return ec.fieldContext_Dynasty_authorCount(ctx, field)
+			case "hasNextPage":
+				return ec.fieldContext_PageInfo_hasNextPage(ctx, field)
+			case "hasPreviousPage":
+				return ec.fieldContext_PageInfo_hasPreviousPage(ctx, field)
+			case "startCursor":
+				return ec.fieldContext_PageInfo_startCursor(ctx, field)
+			case "endCursor":
+				return ec.fieldContext_PageInfo_endCursor(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type Dynasty", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_dynasties_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_poemTypes(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _PoemConnection_totalCount(ctx context.Context, field graphql.CollectedField, obj *database.PoemConnection) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_poemTypes,
+		ec.fieldContext_PoemConnection_totalCount,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().PoemTypes(ctx, fc.Args["lang"].(*database.Lang))
+			return obj.TotalCount, nil
 		},
 		nil,
-		ec.marshalNPoetryType2ᚕᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoetryTypeᚄ,
+		ec.marshalNInt2int,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_poemTypes(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PoemConnection_totalCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "PoemConnection",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_PoetryType_id(ctx, field)
-			case "name":
-				return ec.fieldContext_PoetryType_name(ctx, field)
-			case "category":
-				return ec.fieldContext_PoetryType_category(ctx, field)
-			case "lines":
-				return ec.fieldContext_PoetryType_lines(ctx, field)
-			case "charsPerLine":
-				return ec.fieldContext_PoetryType_charsPerLine(ctx, field)
-			case "description":
-				return ec.fieldContext_PoetryType_description(ctx, field)
-			case "poemCount":
-				return ec.fieldContext_PoetryType_poemCount(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PoetryType", field.Name)
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_poemTypes_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_statistics(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _PoemEdge_node(ctx context.Context, field graphql.CollectedField, obj *database.PoemEdge) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_statistics,
+		ec.fieldContext_PoemEdge_node,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().Statistics(ctx, fc.Args["lang"].(*database.Lang))
+			return obj.Node, nil
 		},
 		nil,
-		ec.marshalNStatistics2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐStatistics,
+		ec.marshalNPoem2githubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoem,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_statistics(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PoemEdge_node(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "PoemEdge",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "totalPoems":
-				return ec.fieldContext_Statistics_totalPoems(ctx, field)
-			case "totalAuthors":
-				return ec.fieldContext_Statistics_totalAuthors(ctx, field)
-			case "totalDynasties":
-				return ec.fieldContext_Statistics_totalDynasties(ctx, field)
-			case "poemsByDynasty":
-				return ec.fieldContext_Statistics_poemsByDynasty(ctx, field)
-			case "poemsByType":
-				return ec.fieldContext_Statistics_poemsByType(ctx, field)
+			case "id":
+				return ec.fieldContext_Poem_id(ctx, field)
+			case "title":
+				return ec.fieldContext_Poem_title(ctx, field)
+			case "content":
+				return ec.fieldContext_Poem_content(ctx, field)
+			case "stanzas":
+				return ec.fieldContext_Poem_stanzas(ctx, field)
+			case "author":
+				return ec.fieldContext_Poem_author(ctx, field)
+			case "dynasty":
+				return ec.fieldContext_Poem_dynasty(ctx, field)
+			case "type":
+				return ec.fieldContext_Poem_type(ctx, field)
+			case "pinyin":
+				return ec.fieldContext_Poem_pinyin(ctx, field)
+			case "meter":
+				return ec.fieldContext_Poem_meter(ctx, field)
+			case "rhyme":
+				return ec.fieldContext_Poem_rhyme(ctx, field)
+			case "source":
+				return ec.fieldContext_Poem_source(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type Statistics", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Poem", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_statistics_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _PoemEdge_cursor(ctx context.Context, field graphql.CollectedField, obj *database.PoemEdge) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query___type,
+		ec.fieldContext_PoemEdge_cursor,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.introspectType(fc.Args["name"].(string))
+			return obj.Cursor, nil
 		},
 		nil,
-		ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType,
+		ec.marshalNString2string,
+		true,
 		true,
-		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query___type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PoemEdge_cursor(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "PoemEdge",
 		Field:      field,
-		IsMethod:   true,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PoemEdge_score(ctx context.Context, field graphql.CollectedField, obj *database.PoemEdge) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PoemEdge_score,
+		func(ctx context.Context) (any, error) {
+			return obj.Score, nil
+		},
+		nil,
+		ec.marshalOFloat2ᚖfloat64,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_PoemEdge_score(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PoemEdge",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Float does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PoemEdge_highlight(ctx context.Context, field graphql.CollectedField, obj *database.PoemEdge) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PoemEdge_highlight,
+		func(ctx context.Context) (any, error) {
+			return obj.Highlight, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_PoemEdge_highlight(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PoemEdge",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PoemEdge_snippet(ctx context.Context, field graphql.CollectedField, obj *database.PoemEdge) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PoemEdge_snippet,
+		func(ctx context.Context) (any, error) {
+			return obj.Snippet, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_PoemEdge_snippet(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PoemEdge",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PoemEdge_matches(ctx context.Context, field graphql.CollectedField, obj *database.PoemEdge) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PoemEdge_matches,
+		func(ctx context.Context) (any, error) {
+			return obj.Matches, nil
+		},
+		nil,
+		ec.marshalOMatchOffset2ᚕgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐMatchOffsetᚄ,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_PoemEdge_matches(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PoemEdge",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "field":
+				return ec.fieldContext_MatchOffset_field(ctx, field)
+			case "line":
+				return ec.fieldContext_MatchOffset_line(ctx, field)
+			case "start":
+				return ec.fieldContext_MatchOffset_start(ctx, field)
+			case "end":
+				return ec.fieldContext_MatchOffset_end(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type MatchOffset", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PoemPinyin_title(ctx context.Context, field graphql.CollectedField, obj *model.PoemPinyin) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PoemPinyin_title,
+		func(ctx context.Context) (any, error) {
+			return obj.Title, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PoemPinyin_title(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PoemPinyin",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PoemPinyin_content(ctx context.Context, field graphql.CollectedField, obj *model.PoemPinyin) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PoemPinyin_content,
+		func(ctx context.Context) (any, error) {
+			return obj.Content, nil
+		},
+		nil,
+		ec.marshalNString2ᚕstringᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PoemPinyin_content(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PoemPinyin",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PoetryType_id(ctx context.Context, field graphql.CollectedField, obj *database.PoetryType) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PoetryType_id,
+		func(ctx context.Context) (any, error) {
+			return obj.ID, nil
+		},
+		nil,
+		ec.marshalNID2int64,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PoetryType_id(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PoetryType",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type ID does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PoetryType_name(ctx context.Context, field graphql.CollectedField, obj *database.PoetryType) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PoetryType_name,
+		func(ctx context.Context) (any, error) {
+			return obj.Name, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PoetryType_name(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PoetryType",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PoetryType_category(ctx context.Context, field graphql.CollectedField, obj *database.PoetryType) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PoetryType_category,
+		func(ctx context.Context) (any, error) {
+			return obj.Category, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PoetryType_category(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PoetryType",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PoetryType_lines(ctx context.Context, field graphql.CollectedField, obj *database.PoetryType) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PoetryType_lines,
+		func(ctx context.Context) (any, error) {
+			return obj.Lines, nil
+		},
+		nil,
+		ec.marshalOInt2ᚖint,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_PoetryType_lines(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PoetryType",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PoetryType_charsPerLine(ctx context.Context, field graphql.CollectedField, obj *database.PoetryType) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PoetryType_charsPerLine,
+		func(ctx context.Context) (any, error) {
+			return obj.CharsPerLine, nil
+		},
+		nil,
+		ec.marshalOInt2ᚖint,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_PoetryType_charsPerLine(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PoetryType",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PoetryType_description(ctx context.Context, field graphql.CollectedField, obj *database.PoetryType) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PoetryType_description,
+		func(ctx context.Context) (any, error) {
+			return obj.Description, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_PoetryType_description(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PoetryType",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PoetryType_poemCount(ctx context.Context, field graphql.CollectedField, obj *database.PoetryType) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PoetryType_poemCount,
+		func(ctx context.Context) (any, error) {
+			return ec.resolvers.PoetryType().PoemCount(ctx, obj)
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PoetryType_poemCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PoetryType",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_poem(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_poem,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().Poem(ctx, fc.Args["id"].(string), fc.Args["lang"].(*database.Lang))
+		},
+		nil,
+		ec.marshalOPoem2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoem,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_poem(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Poem_id(ctx, field)
+			case "title":
+				return ec.fieldContext_Poem_title(ctx, field)
+			case "content":
+				return ec.fieldContext_Poem_content(ctx, field)
+			case "stanzas":
+				return ec.fieldContext_Poem_stanzas(ctx, field)
+			case "author":
+				return ec.fieldContext_Poem_author(ctx, field)
+			case "dynasty":
+				return ec.fieldContext_Poem_dynasty(ctx, field)
+			case "type":
+				return ec.fieldContext_Poem_type(ctx, field)
+			case "pinyin":
+				return ec.fieldContext_Poem_pinyin(ctx, field)
+			case "meter":
+				return ec.fieldContext_Poem_meter(ctx, field)
+			case "rhyme":
+				return ec.fieldContext_Poem_rhyme(ctx, field)
+			case "source":
+				return ec.fieldContext_Poem_source(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Poem", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_poem_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_poems(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_poems,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().Poems(ctx, fc.Args["lang"].(*database.Lang), fc.Args["page"].(*int), fc.Args["pageSize"].(*int), fc.Args["dynastyId"].(*string), fc.Args["authorId"].(*string), fc.Args["typeId"].(*string), fc.Args["meter"].(*model.MeterFilter), fc.Args["rhyme"].(*string), fc.Args["dataset"].(*string), fc.Args["after"].(*string), fc.Args["before"].(*string))
+		},
+		nil,
+		ec.marshalNPoemConnection2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoemConnection,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_poems(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "edges":
+				return ec.fieldContext_PoemConnection_edges(ctx, field)
+			case "pageInfo":
+				return ec.fieldContext_PoemConnection_pageInfo(ctx, field)
+			case "totalCount":
+				return ec.fieldContext_PoemConnection_totalCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PoemConnection", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_poems_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_searchPoems(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_searchPoems,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().SearchPoems(ctx, fc.Args["query"].(string), fc.Args["lang"].(*database.Lang), fc.Args["searchType"].(*model.SearchType), fc.Args["sort"].(*model.SearchSort), fc.Args["page"].(*int), fc.Args["pageSize"].(*int))
+		},
+		nil,
+		ec.marshalNPoemConnection2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoemConnection,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_searchPoems(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "edges":
+				return ec.fieldContext_PoemConnection_edges(ctx, field)
+			case "pageInfo":
+				return ec.fieldContext_PoemConnection_pageInfo(ctx, field)
+			case "totalCount":
+				return ec.fieldContext_PoemConnection_totalCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PoemConnection", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_searchPoems_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_randomPoem(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_randomPoem,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().RandomPoem(ctx, fc.Args["lang"].(*database.Lang), fc.Args["dynastyId"].(*string), fc.Args["typeId"].(*string))
+		},
+		nil,
+		ec.marshalOPoem2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoem,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_randomPoem(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Poem_id(ctx, field)
+			case "title":
+				return ec.fieldContext_Poem_title(ctx, field)
+			case "content":
+				return ec.fieldContext_Poem_content(ctx, field)
+			case "stanzas":
+				return ec.fieldContext_Poem_stanzas(ctx, field)
+			case "author":
+				return ec.fieldContext_Poem_author(ctx, field)
+			case "dynasty":
+				return ec.fieldContext_Poem_dynasty(ctx, field)
+			case "type":
+				return ec.fieldContext_Poem_type(ctx, field)
+			case "pinyin":
+				return ec.fieldContext_Poem_pinyin(ctx, field)
+			case "meter":
+				return ec.fieldContext_Poem_meter(ctx, field)
+			case "rhyme":
+				return ec.fieldContext_Poem_rhyme(ctx, field)
+			case "source":
+				return ec.fieldContext_Poem_source(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Poem", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_randomPoem_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_dailyPoem(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_dailyPoem,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().DailyPoem(ctx, fc.Args["date"].(*string), fc.Args["timezone"].(*string), fc.Args["lang"].(*database.Lang), fc.Args["dynastyId"].(*string), fc.Args["authorId"].(*string), fc.Args["typeId"].(*string))
+		},
+		nil,
+		ec.marshalOPoem2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoem,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_dailyPoem(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Poem_id(ctx, field)
+			case "title":
+				return ec.fieldContext_Poem_title(ctx, field)
+			case "content":
+				return ec.fieldContext_Poem_content(ctx, field)
+			case "stanzas":
+				return ec.fieldContext_Poem_stanzas(ctx, field)
+			case "author":
+				return ec.fieldContext_Poem_author(ctx, field)
+			case "dynasty":
+				return ec.fieldContext_Poem_dynasty(ctx, field)
+			case "type":
+				return ec.fieldContext_Poem_type(ctx, field)
+			case "pinyin":
+				return ec.fieldContext_Poem_pinyin(ctx, field)
+			case "meter":
+				return ec.fieldContext_Poem_meter(ctx, field)
+			case "rhyme":
+				return ec.fieldContext_Poem_rhyme(ctx, field)
+			case "source":
+				return ec.fieldContext_Poem_source(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Poem", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_dailyPoem_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_author(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_author,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().Author(ctx, fc.Args["id"].(string), fc.Args["lang"].(*database.Lang))
+		},
+		nil,
+		ec.marshalOAuthor2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐAuthor,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_author(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Author_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Author_name(ctx, field)
+			case "dynasty":
+				return ec.fieldContext_Author_dynasty(ctx, field)
+			case "description":
+				return ec.fieldContext_Author_description(ctx, field)
+			case "poems":
+				return ec.fieldContext_Author_poems(ctx, field)
+			case "poemCount":
+				return ec.fieldContext_Author_poemCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Author", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_author_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_authors(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_authors,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().Authors(ctx, fc.Args["lang"].(*database.Lang), fc.Args["page"].(*int), fc.Args["pageSize"].(*int), fc.Args["dynastyId"].(*string), fc.Args["after"].(*string), fc.Args["before"].(*string))
+		},
+		nil,
+		ec.marshalNAuthorConnection2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐAuthorConnection,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_authors(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "edges":
+				return ec.fieldContext_AuthorConnection_edges(ctx, field)
+			case "pageInfo":
+				return ec.fieldContext_AuthorConnection_pageInfo(ctx, field)
+			case "totalCount":
+				return ec.fieldContext_AuthorConnection_totalCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type AuthorConnection", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_authors_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_dynasties(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_dynasties,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().Dynasties(ctx, fc.Args["lang"].(*database.Lang))
+		},
+		nil,
+		ec.marshalNDynasty2ᚕᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐDynastyᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_dynasties(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Dynasty_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Dynasty_name(ctx, field)
+			case "nameEn":
+				return ec.fieldContext_Dynasty_nameEn(ctx, field)
+			case "startYear":
+				return ec.fieldContext_Dynasty_startYear(ctx, field)
+			case "endYear":
+				return ec.fieldContext_Dynasty_endYear(ctx, field)
+			case "poemCount":
+				return ec.fieldContext_Dynasty_poemCount(ctx, field)
+			case "authorCount":
+				return ec.fieldContext_Dynasty_authorCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Dynasty", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_dynasties_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_poemTypes(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_poemTypes,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().PoemTypes(ctx, fc.Args["lang"].(*database.Lang))
+		},
+		nil,
+		ec.marshalNPoetryType2ᚕᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoetryTypeᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_poemTypes(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_PoetryType_id(ctx, field)
+			case "name":
+				return ec.fieldContext_PoetryType_name(ctx, field)
+			case "category":
+				return ec.fieldContext_PoetryType_category(ctx, field)
+			case "lines":
+				return ec.fieldContext_PoetryType_lines(ctx, field)
+			case "charsPerLine":
+				return ec.fieldContext_PoetryType_charsPerLine(ctx, field)
+			case "description":
+				return ec.fieldContext_PoetryType_description(ctx, field)
+			case "poemCount":
+				return ec.fieldContext_PoetryType_poemCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PoetryType", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_poemTypes_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_statistics(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_statistics,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().Statistics(ctx, fc.Args["lang"].(*database.Lang))
+		},
+		nil,
+		ec.marshalNStatistics2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐStatistics,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_statistics(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "totalPoems":
+				return ec.fieldContext_Statistics_totalPoems(ctx, field)
+			case "totalAuthors":
+				return ec.fieldContext_Statistics_totalAuthors(ctx, field)
+			case "totalDynasties":
+				return ec.fieldContext_Statistics_totalDynasties(ctx, field)
+			case "poemsByDynasty":
+				return ec.fieldContext_Statistics_poemsByDynasty(ctx, field)
+			case "poemsByType":
+				return ec.fieldContext_Statistics_poemsByType(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Statistics", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_statistics_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query___type,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.introspectType(fc.Args["name"].(string))
+		},
+		nil,
+		ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query___type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "kind":
+				return ec.fieldContext___Type_kind(ctx, field)
+			case "name":
+				return ec.fieldContext___Type_name(ctx, field)
+			case "description":
+				return ec.fieldContext___Type_description(ctx, field)
+			case "specifiedByURL":
+				return ec.fieldContext___Type_specifiedByURL(ctx, field)
+			case "fields":
+				return ec.fieldContext___Type_fields(ctx, field)
+			case "interfaces":
+				return ec.fieldContext___Type_interfaces(ctx, field)
+			case "possibleTypes":
+				return ec.fieldContext___Type_possibleTypes(ctx, field)
+			case "enumValues":
+				return ec.fieldContext___Type_enumValues(ctx, field)
+			case "inputFields":
+				return ec.fieldContext___Type_inputFields(ctx, field)
+			case "ofType":
+				return ec.fieldContext___Type_ofType(ctx, field)
+			case "isOneOf":
+				return ec.fieldContext___Type_isOneOf(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type __Type", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query___type_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query___schema,
+		func(ctx context.Context) (any, error) {
+			return ec.introspectSchema()
+		},
+		nil,
+		ec.marshalO__Schema2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐSchema,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query___schema(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "description":
+				return ec.fieldContext___Schema_description(ctx, field)
+			case "types":
+				return ec.fieldContext___Schema_types(ctx, field)
+			case "queryType":
+				return ec.fieldContext___Schema_queryType(ctx, field)
+			case "mutationType":
+				return ec.fieldContext___Schema_mutationType(ctx, field)
+			case "subscriptionType":
+				return ec.fieldContext___Schema_subscriptionType(ctx, field)
+			case "directives":
+				return ec.fieldContext___Schema_directives(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type __Schema", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Rhyme_system(ctx context.Context, field graphql.CollectedField, obj *model.Rhyme) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Rhyme_system,
+		func(ctx context.Context) (any, error) {
+			return obj.System, nil
+		},
+		nil,
+		ec.marshalNRhymeSystem2githubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐRhymeSystem,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Rhyme_system(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Rhyme",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type RhymeSystem does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Rhyme_groups(ctx context.Context, field graphql.CollectedField, obj *model.Rhyme) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Rhyme_groups,
+		func(ctx context.Context) (any, error) {
+			return obj.Groups, nil
+		},
+		nil,
+		ec.marshalNString2ᚕstringᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Rhyme_groups(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Rhyme",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Rhyme_positions(ctx context.Context, field graphql.CollectedField, obj *model.Rhyme) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Rhyme_positions,
+		func(ctx context.Context) (any, error) {
+			return obj.Positions, nil
+		},
+		nil,
+		ec.marshalNRhymePosition2ᚕᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐRhymePositionᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Rhyme_positions(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Rhyme",
+		Field:      field,
+		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "kind":
-				return ec.fieldContext___Type_kind(ctx, field)
-			case "name":
-				return ec.fieldContext___Type_name(ctx, field)
-			case "description":
-				return ec.fieldContext___Type_description(ctx, field)
-			case "specifiedByURL":
-				return ec.fieldContext___Type_specifiedByURL(ctx, field)
-			case "fields":
-				return ec.fieldContext___Type_fields(ctx, field)
-			case "interfaces":
-				return ec.fieldContext___Type_interfaces(ctx, field)
-			case "possibleTypes":
-				return ec.fieldContext___Type_possibleTypes(ctx, field)
-			case "enumValues":
-				return ec.fieldContext___Type_enumValues(ctx, field)
-			case "inputFields":
-				return ec.fieldContext___Type_inputFields(ctx, field)
-			case "ofType":
-				return ec.fieldContext___Type_ofType(ctx, field)
-			case "isOneOf":
-				return ec.fieldContext___Type_isOneOf(ctx, field)
+			case "line":
+				return ec.fieldContext_RhymePosition_line(ctx, field)
+			case "char":
+				return ec.fieldContext_RhymePosition_char(ctx, field)
+			case "group":
+				return ec.fieldContext_RhymePosition_group(ctx, field)
+			case "outOfRhyme":
+				return ec.fieldContext_RhymePosition_outOfRhyme(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type __Type", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type RhymePosition", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query___type_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
+	return fc, nil
+}
+
+func (ec *executionContext) _RhymePosition_line(ctx context.Context, field graphql.CollectedField, obj *model.RhymePosition) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_RhymePosition_line,
+		func(ctx context.Context) (any, error) {
+			return obj.Line, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_RhymePosition_line(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RhymePosition",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _RhymePosition_char(ctx context.Context, field graphql.CollectedField, obj *model.RhymePosition) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query___schema,
+		ec.fieldContext_RhymePosition_char,
 		func(ctx context.Context) (any, error) {
-			return ec.introspectSchema()
+			return obj.Char, nil
 		},
 		nil,
-		ec.marshalO__Schema2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐSchema,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_RhymePosition_char(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RhymePosition",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _RhymePosition_group(ctx context.Context, field graphql.CollectedField, obj *model.RhymePosition) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_RhymePosition_group,
+		func(ctx context.Context) (any, error) {
+			return obj.Group, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_RhymePosition_group(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RhymePosition",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _RhymePosition_outOfRhyme(ctx context.Context, field graphql.CollectedField, obj *model.RhymePosition) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_RhymePosition_outOfRhyme,
+		func(ctx context.Context) (any, error) {
+			return obj.OutOfRhyme, nil
+		},
+		nil,
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_RhymePosition_outOfRhyme(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RhymePosition",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Source_dataset(ctx context.Context, field graphql.CollectedField, obj *database.Source) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Source_dataset,
+		func(ctx context.Context) (any, error) {
+			return obj.Dataset, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Source_dataset(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Source",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Source_datasetName(ctx context.Context, field graphql.CollectedField, obj *database.Source) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Source_datasetName,
+		func(ctx context.Context) (any, error) {
+			return obj.DatasetName, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Source_datasetName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Source",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Source_file(ctx context.Context, field graphql.CollectedField, obj *database.Source) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Source_file,
+		func(ctx context.Context) (any, error) {
+			return obj.File, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Source_file(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Source",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Source_recordId(ctx context.Context, field graphql.CollectedField, obj *database.Source) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Source_recordId,
+		func(ctx context.Context) (any, error) {
+			return obj.RecordID, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
 		true,
 		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query___schema(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Source_recordId(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Source",
 		Field:      field,
-		IsMethod:   true,
+		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "description":
-				return ec.fieldContext___Schema_description(ctx, field)
-			case "types":
-				return ec.fieldContext___Schema_types(ctx, field)
-			case "queryType":
-				return ec.fieldContext___Schema_queryType(ctx, field)
-			case "mutationType":
-				return ec.fieldContext___Schema_mutationType(ctx, field)
-			case "subscriptionType":
-				return ec.fieldContext___Schema_subscriptionType(ctx, field)
-			case "directives":
-				return ec.fieldContext___Schema_directives(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type __Schema", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Source_index(ctx context.Context, field graphql.CollectedField, obj *database.Source) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Source_index,
+		func(ctx context.Context) (any, error) {
+			return obj.Index, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Source_index(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Source",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
@@ -4745,6 +6540,8 @@ func (ec *executionContext) _Author(ctx context.Context, sel ast.SelectionSet, o
 			}
 		case "dynasty":
 			out.Values[i] = ec._Author_dynasty(ctx, field, obj)
+		case "description":
+			out.Values[i] = ec._Author_description(ctx, field, obj)
 		case "poems":
 			field := field
 
@@ -5130,6 +6927,168 @@ func (ec *executionContext) _DynastyStats(ctx context.Context, sel ast.Selection
 	return out
 }
 
+var matchOffsetImplementors = []string{"MatchOffset"}
+
+func (ec *executionContext) _MatchOffset(ctx context.Context, sel ast.SelectionSet, obj *database.MatchOffset) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, matchOffsetImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("MatchOffset")
+		case "field":
+			out.Values[i] = ec._MatchOffset_field(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "line":
+			out.Values[i] = ec._MatchOffset_line(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "start":
+			out.Values[i] = ec._MatchOffset_start(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "end":
+			out.Values[i] = ec._MatchOffset_end(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var meterImplementors = []string{"Meter"}
+
+func (ec *executionContext) _Meter(ctx context.Context, sel ast.SelectionSet, obj *model.Meter) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, meterImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Meter")
+		case "status":
+			out.Values[i] = ec._Meter_status(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "pattern":
+			out.Values[i] = ec._Meter_pattern(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "tones":
+			out.Values[i] = ec._Meter_tones(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "deviations":
+			out.Values[i] = ec._Meter_deviations(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var meterDeviationImplementors = []string{"MeterDeviation"}
+
+func (ec *executionContext) _MeterDeviation(ctx context.Context, sel ast.SelectionSet, obj *model.MeterDeviation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, meterDeviationImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("MeterDeviation")
+		case "line":
+			out.Values[i] = ec._MeterDeviation_line(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "position":
+			out.Values[i] = ec._MeterDeviation_position(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "kind":
+			out.Values[i] = ec._MeterDeviation_kind(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "rescued":
+			out.Values[i] = ec._MeterDeviation_rescued(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var pageInfoImplementors = []string{"PageInfo"}
 
 func (ec *executionContext) _PageInfo(ctx context.Context, sel ast.SelectionSet, obj *database.PageInfo) graphql.Marshaler {
@@ -5178,40 +7137,184 @@ func (ec *executionContext) _PageInfo(ctx context.Context, sel ast.SelectionSet,
 	return out
 }
 
-var poemImplementors = []string{"Poem"}
+var poemImplementors = []string{"Poem"}
+
+func (ec *executionContext) _Poem(ctx context.Context, sel ast.SelectionSet, obj *database.Poem) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, poemImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Poem")
+		case "id":
+			out.Values[i] = ec._Poem_id(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&out.Invalids, 1)
+			}
+		case "title":
+			out.Values[i] = ec._Poem_title(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&out.Invalids, 1)
+			}
+		case "content":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Poem_content(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
+
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+		case "stanzas":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Poem_stanzas(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
+
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+		case "author":
+			out.Values[i] = ec._Poem_author(ctx, field, obj)
+		case "dynasty":
+			out.Values[i] = ec._Poem_dynasty(ctx, field, obj)
+		case "type":
+			out.Values[i] = ec._Poem_type(ctx, field, obj)
+		case "pinyin":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Poem_pinyin(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
+
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
+			}
 
-func (ec *executionContext) _Poem(ctx context.Context, sel ast.SelectionSet, obj *database.Poem) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, poemImplementors)
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+		case "meter":
+			field := field
 
-	out := graphql.NewFieldSet(fields)
-	deferred := make(map[string]*graphql.FieldSet)
-	for i, field := range fields {
-		switch field.Name {
-		case "__typename":
-			out.Values[i] = graphql.MarshalString("Poem")
-		case "id":
-			out.Values[i] = ec._Poem_id(ctx, field, obj)
-			if out.Values[i] == graphql.Null {
-				atomic.AddUint32(&out.Invalids, 1)
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Poem_meter(ctx, field, obj)
+				return res
 			}
-		case "title":
-			out.Values[i] = ec._Poem_title(ctx, field, obj)
-			if out.Values[i] == graphql.Null {
-				atomic.AddUint32(&out.Invalids, 1)
+
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
+
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
 			}
-		case "content":
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+		case "rhyme":
 			field := field
 
-			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
 				defer func() {
 					if r := recover(); r != nil {
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._Poem_content(ctx, field, obj)
-				if res == graphql.Null {
-					atomic.AddUint32(&fs.Invalids, 1)
-				}
+				res = ec._Poem_rhyme(ctx, field, obj)
 				return res
 			}
 
@@ -5235,12 +7338,11 @@ func (ec *executionContext) _Poem(ctx context.Context, sel ast.SelectionSet, obj
 			}
 
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
-		case "author":
-			out.Values[i] = ec._Poem_author(ctx, field, obj)
-		case "dynasty":
-			out.Values[i] = ec._Poem_dynasty(ctx, field, obj)
-		case "type":
-			out.Values[i] = ec._Poem_type(ctx, field, obj)
+		case "source":
+			out.Values[i] = ec._Poem_source(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&out.Invalids, 1)
+			}
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -5334,6 +7436,58 @@ func (ec *executionContext) _PoemEdge(ctx context.Context, sel ast.SelectionSet,
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "score":
+			out.Values[i] = ec._PoemEdge_score(ctx, field, obj)
+		case "highlight":
+			out.Values[i] = ec._PoemEdge_highlight(ctx, field, obj)
+		case "snippet":
+			out.Values[i] = ec._PoemEdge_snippet(ctx, field, obj)
+		case "matches":
+			out.Values[i] = ec._PoemEdge_matches(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var poemPinyinImplementors = []string{"PoemPinyin"}
+
+func (ec *executionContext) _PoemPinyin(ctx context.Context, sel ast.SelectionSet, obj *model.PoemPinyin) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, poemPinyinImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("PoemPinyin")
+		case "title":
+			out.Values[i] = ec._PoemPinyin_title(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "content":
+			out.Values[i] = ec._PoemPinyin_content(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -5548,6 +7702,25 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 			}
 
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "dailyPoem":
+			field := field
+
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_dailyPoem(ctx, field)
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
 		case "author":
 			field := field
@@ -5654,16 +7827,175 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 				return ec.OperationContext.RootResolverMiddleware(ctx,
 					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 			}
-
-			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
-		case "__type":
-			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
-				return ec._Query___type(ctx, field)
-			})
-		case "__schema":
-			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
-				return ec._Query___schema(ctx, field)
-			})
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "__type":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Query___type(ctx, field)
+			})
+		case "__schema":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Query___schema(ctx, field)
+			})
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var rhymeImplementors = []string{"Rhyme"}
+
+func (ec *executionContext) _Rhyme(ctx context.Context, sel ast.SelectionSet, obj *model.Rhyme) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, rhymeImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Rhyme")
+		case "system":
+			out.Values[i] = ec._Rhyme_system(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "groups":
+			out.Values[i] = ec._Rhyme_groups(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "positions":
+			out.Values[i] = ec._Rhyme_positions(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var rhymePositionImplementors = []string{"RhymePosition"}
+
+func (ec *executionContext) _RhymePosition(ctx context.Context, sel ast.SelectionSet, obj *model.RhymePosition) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, rhymePositionImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("RhymePosition")
+		case "line":
+			out.Values[i] = ec._RhymePosition_line(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "char":
+			out.Values[i] = ec._RhymePosition_char(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "group":
+			out.Values[i] = ec._RhymePosition_group(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "outOfRhyme":
+			out.Values[i] = ec._RhymePosition_outOfRhyme(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var sourceImplementors = []string{"Source"}
+
+func (ec *executionContext) _Source(ctx context.Context, sel ast.SelectionSet, obj *database.Source) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, sourceImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Source")
+		case "dataset":
+			out.Values[i] = ec._Source_dataset(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "datasetName":
+			out.Values[i] = ec._Source_datasetName(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "file":
+			out.Values[i] = ec._Source_file(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "recordId":
+			out.Values[i] = ec._Source_recordId(ctx, field, obj)
+		case "index":
+			out.Values[i] = ec._Source_index(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -6435,6 +8767,74 @@ func (ec *executionContext) marshalNInt2int(ctx context.Context, sel ast.Selecti
 	return res
 }
 
+func (ec *executionContext) marshalNMatchOffset2githubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐMatchOffset(ctx context.Context, sel ast.SelectionSet, v database.MatchOffset) graphql.Marshaler {
+	return ec._MatchOffset(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNMeterDeviation2ᚕᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐMeterDeviationᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.MeterDeviation) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNMeterDeviation2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐMeterDeviation(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNMeterDeviation2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐMeterDeviation(ctx context.Context, sel ast.SelectionSet, v *model.MeterDeviation) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._MeterDeviation(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalNMeterStatus2githubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐMeterStatus(ctx context.Context, v any) (model.MeterStatus, error) {
+	var res model.MeterStatus
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNMeterStatus2githubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐMeterStatus(ctx context.Context, sel ast.SelectionSet, v model.MeterStatus) graphql.Marshaler {
+	return v
+}
+
 func (ec *executionContext) marshalNPageInfo2githubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPageInfo(ctx context.Context, sel ast.SelectionSet, v database.PageInfo) graphql.Marshaler {
 	return ec._PageInfo(ctx, sel, &v)
 }
@@ -6505,6 +8905,16 @@ func (ec *executionContext) marshalNPoemEdge2ᚕgithubᚗcomᚋpalemokyᚋchines
 	return ret
 }
 
+func (ec *executionContext) marshalNPoemPinyin2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐPoemPinyin(ctx context.Context, sel ast.SelectionSet, v *model.PoemPinyin) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._PoemPinyin(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalNPoetryType2ᚕᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoetryTypeᚄ(ctx context.Context, sel ast.SelectionSet, v []*database.PoetryType) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
@@ -6559,6 +8969,80 @@ func (ec *executionContext) marshalNPoetryType2ᚖgithubᚗcomᚋpalemokyᚋchin
 	return ec._PoetryType(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalNRhymePosition2ᚕᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐRhymePositionᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.RhymePosition) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNRhymePosition2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐRhymePosition(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNRhymePosition2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐRhymePosition(ctx context.Context, sel ast.SelectionSet, v *model.RhymePosition) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._RhymePosition(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalNRhymeSystem2githubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐRhymeSystem(ctx context.Context, v any) (model.RhymeSystem, error) {
+	var res model.RhymeSystem
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNRhymeSystem2githubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐRhymeSystem(ctx context.Context, sel ast.SelectionSet, v model.RhymeSystem) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) marshalNSource2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐSource(ctx context.Context, sel ast.SelectionSet, v *database.Source) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._Source(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalNStatistics2githubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐStatistics(ctx context.Context, sel ast.SelectionSet, v database.Statistics) graphql.Marshaler {
 	return ec._Statistics(ctx, sel, &v)
 }
@@ -6619,6 +9103,21 @@ func (ec *executionContext) marshalNString2ᚕstringᚄ(ctx context.Context, sel
 	return ret
 }
 
+func (ec *executionContext) marshalNString2ᚕᚕstringᚄᚄ(ctx context.Context, sel ast.SelectionSet, v [][]string) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	for i := range v {
+		ret[i] = ec.marshalNString2ᚕstringᚄ(ctx, sel, v[i])
+	}
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
 func (ec *executionContext) marshalNTypeStats2ᚕᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐTypeStatsᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.TypeStats) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
@@ -6970,6 +9469,15 @@ func (ec *executionContext) marshalODynasty2ᚖgithubᚗcomᚋpalemokyᚋchinese
 	return ec._Dynasty(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalOFloat2ᚖfloat64(ctx context.Context, sel ast.SelectionSet, v *float64) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	_ = sel
+	res := graphql.MarshalFloatContext(*v)
+	return graphql.WrapContextMarshaler(ctx, res)
+}
+
 func (ec *executionContext) unmarshalOID2ᚖstring(ctx context.Context, v any) (*string, error) {
 	if v == nil {
 		return nil, nil
@@ -7025,6 +9533,92 @@ func (ec *executionContext) marshalOLang2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑ
 	return res
 }
 
+func (ec *executionContext) marshalOMatchOffset2ᚕgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐMatchOffsetᚄ(ctx context.Context, sel ast.SelectionSet, v []database.MatchOffset) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNMatchOffset2githubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐMatchOffset(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalOMeter2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐMeter(ctx context.Context, sel ast.SelectionSet, v *model.Meter) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._Meter(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalOMeterFilter2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐMeterFilter(ctx context.Context, v any) (*model.MeterFilter, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res := new(model.MeterFilter)
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOMeterFilter2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐMeterFilter(ctx context.Context, sel ast.SelectionSet, v *model.MeterFilter) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
+func (ec *executionContext) unmarshalOPinyinStyle2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐPinyinStyle(ctx context.Context, v any) (*model.PinyinStyle, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res := new(model.PinyinStyle)
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOPinyinStyle2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐPinyinStyle(ctx context.Context, sel ast.SelectionSet, v *model.PinyinStyle) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
 func (ec *executionContext) marshalOPoem2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋdatabaseᚐPoem(ctx context.Context, sel ast.SelectionSet, v *database.Poem) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -7039,6 +9633,29 @@ func (ec *executionContext) marshalOPoetryType2ᚖgithubᚗcomᚋpalemokyᚋchin
 	return ec._PoetryType(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalORhyme2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐRhyme(ctx context.Context, sel ast.SelectionSet, v *model.Rhyme) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._Rhyme(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalOSearchSort2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐSearchSort(ctx context.Context, v any) (*model.SearchSort, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res := new(model.SearchSort)
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOSearchSort2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐSearchSort(ctx context.Context, sel ast.SelectionSet, v *model.SearchSort) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
 func (ec *executionContext) unmarshalOSearchType2ᚖgithubᚗcomᚋpalemokyᚋchineseᚑpoetryᚑapiᚋinternalᚋgraphᚋmodelᚐSearchType(ctx context.Context, v any) (*model.SearchType, error) {
 	if v == nil {
 		return nil, nil
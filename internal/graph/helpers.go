@@ -1,7 +1,7 @@
 package graph
 
 import (
-	"strconv"
+	"errors"
 
 	"github.com/palemoky/chinese-poetry-api/internal/database"
 	"github.com/palemoky/chinese-poetry-api/internal/helpers"
@@ -12,6 +12,8 @@ type Pagination struct {
 	Page     int
 	PageSize int
 	Offset   int
+	After    *database.Cursor // Set for keyset pages; Page and Offset are then ignored
+	Before   *database.Cursor
 }
 
 // parsePagination extracts and validates pagination parameters with defaults.
@@ -35,31 +37,70 @@ func parsePagination(page, pageSize *int) Pagination {
 	}
 }
 
+// withCursors decodes the after/before connection arguments into p using
+// decode, which must match the listing the cursors came from.
+func (p Pagination) withCursors(after, before *string, decode func(string) (*database.Cursor, error)) (Pagination, error) {
+	if after != nil && before != nil {
+		return p, errors.New("after and before cannot be combined")
+	}
+
+	var err error
+	if after != nil {
+		p.After, err = decode(*after)
+	}
+	if before != nil {
+		p.Before, err = decode(*before)
+	}
+	return p, err
+}
+
+// dbPage converts p into a repository page
+func (p Pagination) dbPage() database.Page {
+	return database.Page{Limit: p.PageSize, Offset: p.Offset, After: p.After, Before: p.Before}
+}
+
+// poemPage converts p into a repository page of poems, which GraphQL lists by
+// descending ID
+func (p Pagination) poemPage() database.Page {
+	page := p.dbPage()
+	page.Desc = true
+	return page
+}
+
+// pageInfo builds PageInfo for a page whose neighbours the repository reported
+func pageInfo(adjacent database.Adjacent, startCursor, endCursor *string) database.PageInfo {
+	return database.PageInfo{
+		HasNextPage:     adjacent.After,
+		HasPreviousPage: adjacent.Before,
+		StartCursor:     startCursor,
+		EndCursor:       endCursor,
+	}
+}
+
 // parseOptionalID parses an optional string ID to int64 pointer.
 // Uses common helper function.
 func parseOptionalID(id *string) (*int64, error) {
 	return helpers.ParseOptionalInt64(id)
 }
 
-// parseLang converts an optional Lang pointer to a Lang value.
+// parseLang converts an optional Lang pointer to a Lang value, accepting the
+// enum values (ZH_HANT) as well as the tags (zh-Hant) for the variants.
 // Uses common helper function.
-func parseLang(lang *database.Lang) database.Lang {
+func parseLang(lang *database.Lang) (database.Lang, error) {
 	return helpers.ParseLangPointer(lang)
 }
 
-// buildPoemConnection creates a PoemConnection from poems slice and pagination info.
-func buildPoemConnection(poems []database.Poem, pag Pagination, totalCount int) *database.PoemConnection {
+// buildPoemConnection creates a PoemConnection from a page of poems.
+// Edge cursors are opaque poem-ID cursors usable as after/before.
+func buildPoemConnection(poems []database.Poem, totalCount int, adjacent database.Adjacent) *database.PoemConnection {
 	edges := make([]database.PoemEdge, len(poems))
 	for i, poem := range poems {
 		edges[i] = database.PoemEdge{
 			Node:   poem,
-			Cursor: strconv.Itoa(pag.Offset + i),
+			Cursor: database.EncodePoemCursor(poem.ID),
 		}
 	}
 
-	hasNextPage := pag.Offset+len(poems) < totalCount
-	hasPreviousPage := pag.Page > 1
-
 	var startCursor, endCursor *string
 	if len(edges) > 0 {
 		start := edges[0].Cursor
@@ -69,30 +110,23 @@ func buildPoemConnection(poems []database.Poem, pag Pagination, totalCount int)
 	}
 
 	return &database.PoemConnection{
-		Edges: edges,
-		PageInfo: database.PageInfo{
-			HasNextPage:     hasNextPage,
-			HasPreviousPage: hasPreviousPage,
-			StartCursor:     startCursor,
-			EndCursor:       endCursor,
-		},
+		Edges:      edges,
+		PageInfo:   pageInfo(adjacent, startCursor, endCursor),
 		TotalCount: totalCount,
 	}
 }
 
-// buildAuthorConnection creates an AuthorConnection from authors slice and pagination info.
-func buildAuthorConnection(authors []database.AuthorWithStats, pag Pagination, totalCount int) *database.AuthorConnection {
+// buildAuthorConnection creates an AuthorConnection from a page of authors.
+// Edge cursors are opaque (poem count, ID) cursors usable as after/before.
+func buildAuthorConnection(authors []database.AuthorWithStats, totalCount int, adjacent database.Adjacent) *database.AuthorConnection {
 	edges := make([]database.AuthorEdge, len(authors))
 	for i, author := range authors {
 		edges[i] = database.AuthorEdge{
 			Node:   author,
-			Cursor: strconv.Itoa(pag.Offset + i),
+			Cursor: database.EncodeAuthorCursor(author),
 		}
 	}
 
-	hasNextPage := pag.Offset+len(authors) < totalCount
-	hasPreviousPage := pag.Page > 1
-
 	var startCursor, endCursor *string
 	if len(edges) > 0 {
 		start := edges[0].Cursor
@@ -102,13 +136,8 @@ func buildAuthorConnection(authors []database.AuthorWithStats, pag Pagination, t
 	}
 
 	return &database.AuthorConnection{
-		Edges: edges,
-		PageInfo: database.PageInfo{
-			HasNextPage:     hasNextPage,
-			HasPreviousPage: hasPreviousPage,
-			StartCursor:     startCursor,
-			EndCursor:       endCursor,
-		},
+		Edges:      edges,
+		PageInfo:   pageInfo(adjacent, startCursor, endCursor),
 		TotalCount: totalCount,
 	}
 }
@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"github.com/palemoky/chinese-poetry-api/internal/database"
+)
+
+// ConvertLang is a field middleware rendering the strings under a query field
+// asked for in a regional variant (lang: ZH_TW, ZH_HK or JA) in its glyph
+// standard. Resolvers read the tables of the variant's base script (see
+// database.Lang.Base), so each string field is converted as it resolves.
+func ConvertLang(ctx context.Context, next graphql.Resolver) (any, error) {
+	res, err := next(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	lang := queryLang(graphql.GetFieldContext(ctx))
+	if !lang.IsRegional() {
+		return res, nil
+	}
+
+	switch v := res.(type) {
+	case string:
+		return lang.Convert(v)
+	case *string:
+		if v == nil {
+			return v, nil
+		}
+		converted, err := lang.Convert(*v)
+		if err != nil {
+			return nil, err
+		}
+		return &converted, nil
+	case []string:
+		converted := make([]string, len(v))
+		for i, s := range v {
+			if converted[i], err = lang.Convert(s); err != nil {
+				return nil, err
+			}
+		}
+		return converted, nil
+	case [][]string:
+		converted := make([][]string, len(v))
+		for i, lines := range v {
+			converted[i] = make([]string, len(lines))
+			for j, s := range lines {
+				if converted[i][j], err = lang.Convert(s); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return converted, nil
+	}
+	return res, nil
+}
+
+// queryLang returns the lang argument of the query field fc resolves under,
+// or "" when it has none or an unknown one, which its resolver reports. The
+// outermost field context belongs to the Query object itself, so the query
+// field is the one below it.
+func queryLang(fc *graphql.FieldContext) database.Lang {
+	for fc != nil && fc.Parent != nil && fc.Parent.Parent != nil {
+		fc = fc.Parent
+	}
+	if fc == nil {
+		return ""
+	}
+	lang, ok := fc.Args["lang"].(*database.Lang)
+	if !ok {
+		return ""
+	}
+	parsed, _ := parseLang(lang)
+	return parsed
+}
@@ -16,13 +16,340 @@ type DynastyStats struct {
 	Count   int               `json:"count"`
 }
 
+// A poem checked against the tonal template of 近体诗 it fits best
+type Meter struct {
+	Status MeterStatus `json:"status"`
+	// Template, e.g. 仄起首句不入韵
+	Pattern string `json:"pattern"`
+	// Tonal pattern of each line: 平, 仄, 中 for either and ？ for unknown
+	Tones      []string          `json:"tones"`
+	Deviations []*MeterDeviation `json:"deviations"`
+}
+
+// A place where a poem departs from its template
+type MeterDeviation struct {
+	// 1-based line number
+	Line int `json:"line"`
+	// 1-based character position in the line
+	Position int `json:"position"`
+	// ao (拗), gu_ping (孤平) or san_ping (三平尾)
+	Kind string `json:"kind"`
+	// Offset by 拗救 in the same or the paired line
+	Rescued bool `json:"rescued"`
+}
+
+type PoemPinyin struct {
+	Title   string   `json:"title"`
+	Content []string `json:"content"`
+}
+
 type Query struct{}
 
+// Where a poem rhymes and in which groups of a rhyme system
+type Rhyme struct {
+	System RhymeSystem `json:"system"`
+	// In order of first use, e.g. 十一尤 or 第十二部
+	Groups    []string         `json:"groups"`
+	Positions []*RhymePosition `json:"positions"`
+}
+
+// A line whose last character rhymes, or should
+type RhymePosition struct {
+	// 1-based line number
+	Line int    `json:"line"`
+	Char string `json:"char"`
+	// The rhyme group of the passage the line belongs to
+	Group string `json:"group"`
+	// 出韵: the character is not in group
+	OutOfRhyme bool `json:"outOfRhyme"`
+}
+
 type TypeStats struct {
 	Type  *database.PoetryType `json:"type"`
 	Count int                  `json:"count"`
 }
 
+type MeterFilter string
+
+const (
+	MeterFilterStrict    MeterFilter = "STRICT"
+	MeterFilterRescued   MeterFilter = "RESCUED"
+	MeterFilterCompliant MeterFilter = "COMPLIANT"
+	MeterFilterBroken    MeterFilter = "BROKEN"
+)
+
+var AllMeterFilter = []MeterFilter{
+	MeterFilterStrict,
+	MeterFilterRescued,
+	MeterFilterCompliant,
+	MeterFilterBroken,
+}
+
+func (e MeterFilter) IsValid() bool {
+	switch e {
+	case MeterFilterStrict, MeterFilterRescued, MeterFilterCompliant, MeterFilterBroken:
+		return true
+	}
+	return false
+}
+
+func (e MeterFilter) String() string {
+	return string(e)
+}
+
+func (e *MeterFilter) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MeterFilter(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MeterFilter", str)
+	}
+	return nil
+}
+
+func (e MeterFilter) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *MeterFilter) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e MeterFilter) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type MeterStatus string
+
+const (
+	MeterStatusStrict  MeterStatus = "STRICT"
+	MeterStatusRescued MeterStatus = "RESCUED"
+	MeterStatusBroken  MeterStatus = "BROKEN"
+)
+
+var AllMeterStatus = []MeterStatus{
+	MeterStatusStrict,
+	MeterStatusRescued,
+	MeterStatusBroken,
+}
+
+func (e MeterStatus) IsValid() bool {
+	switch e {
+	case MeterStatusStrict, MeterStatusRescued, MeterStatusBroken:
+		return true
+	}
+	return false
+}
+
+func (e MeterStatus) String() string {
+	return string(e)
+}
+
+func (e *MeterStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MeterStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MeterStatus", str)
+	}
+	return nil
+}
+
+func (e MeterStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *MeterStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e MeterStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PinyinStyle string
+
+const (
+	PinyinStyleMarks   PinyinStyle = "MARKS"
+	PinyinStyleNumbers PinyinStyle = "NUMBERS"
+)
+
+var AllPinyinStyle = []PinyinStyle{
+	PinyinStyleMarks,
+	PinyinStyleNumbers,
+}
+
+func (e PinyinStyle) IsValid() bool {
+	switch e {
+	case PinyinStyleMarks, PinyinStyleNumbers:
+		return true
+	}
+	return false
+}
+
+func (e PinyinStyle) String() string {
+	return string(e)
+}
+
+func (e *PinyinStyle) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PinyinStyle(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PinyinStyle", str)
+	}
+	return nil
+}
+
+func (e PinyinStyle) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PinyinStyle) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PinyinStyle) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type RhymeSystem string
+
+const (
+	RhymeSystemPingshui RhymeSystem = "PINGSHUI"
+	RhymeSystemCilin    RhymeSystem = "CILIN"
+)
+
+var AllRhymeSystem = []RhymeSystem{
+	RhymeSystemPingshui,
+	RhymeSystemCilin,
+}
+
+func (e RhymeSystem) IsValid() bool {
+	switch e {
+	case RhymeSystemPingshui, RhymeSystemCilin:
+		return true
+	}
+	return false
+}
+
+func (e RhymeSystem) String() string {
+	return string(e)
+}
+
+func (e *RhymeSystem) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RhymeSystem(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RhymeSystem", str)
+	}
+	return nil
+}
+
+func (e RhymeSystem) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *RhymeSystem) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e RhymeSystem) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SearchSort string
+
+const (
+	SearchSortRelevance SearchSort = "RELEVANCE"
+	SearchSortID        SearchSort = "ID"
+)
+
+var AllSearchSort = []SearchSort{
+	SearchSortRelevance,
+	SearchSortID,
+}
+
+func (e SearchSort) IsValid() bool {
+	switch e {
+	case SearchSortRelevance, SearchSortID:
+		return true
+	}
+	return false
+}
+
+func (e SearchSort) String() string {
+	return string(e)
+}
+
+func (e *SearchSort) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchSort(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchSort", str)
+	}
+	return nil
+}
+
+func (e SearchSort) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SearchSort) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SearchSort) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type SearchType string
 
 const (
@@ -30,6 +357,7 @@ const (
 	SearchTypeTitle   SearchType = "TITLE"
 	SearchTypeContent SearchType = "CONTENT"
 	SearchTypeAuthor  SearchType = "AUTHOR"
+	SearchTypePinyin  SearchType = "PINYIN"
 )
 
 var AllSearchType = []SearchType{
@@ -37,11 +365,12 @@ var AllSearchType = []SearchType{
 	SearchTypeTitle,
 	SearchTypeContent,
 	SearchTypeAuthor,
+	SearchTypePinyin,
 }
 
 func (e SearchType) IsValid() bool {
 	switch e {
-	case SearchTypeAll, SearchTypeTitle, SearchTypeContent, SearchTypeAuthor:
+	case SearchTypeAll, SearchTypeTitle, SearchTypeContent, SearchTypeAuthor, SearchTypePinyin:
 		return true
 	}
 	return false
@@ -15,6 +15,7 @@ import (
 
 	"github.com/palemoky/chinese-poetry-api/internal/database"
 	"github.com/palemoky/chinese-poetry-api/internal/graph/generated"
+	"github.com/palemoky/chinese-poetry-api/internal/pinyin"
 )
 
 // setupTestResolver creates a test resolver with an in-memory database
@@ -40,6 +41,7 @@ func createTestClient(t *testing.T, resolver *Resolver) *client.Client {
 	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
 		Resolvers: resolver,
 	}))
+	srv.AroundFields(ConvertLang)
 	return client.New(srv)
 }
 
@@ -55,13 +57,19 @@ func createTestData(t *testing.T, repo *database.Repository) (dynastyID, authorI
 	authorID, err = repo.GetOrCreateAuthor("李白", dynastyID)
 	require.NoError(t, err)
 
-	// Create poem
+	// Create poem, with the pinyin index the processor would build
 	poem := &database.Poem{
-		ID:        1,
-		Title:     "静夜思",
-		Content:   datatypes.JSON([]byte(`["床前明月光","疑是地上霜","举头望明月","低头思故乡"]`)),
-		AuthorID:  &authorID,
-		DynastyID: &dynastyID,
+		ID:            1,
+		Title:         "静夜思",
+		Content:       datatypes.JSON([]byte(`["床前明月光","疑是地上霜","举头望明月","低头思故乡"]`)),
+		AuthorID:      &authorID,
+		DynastyID:     &dynastyID,
+		TitlePinyin:   pinyin.Index("静夜思"),
+		ContentPinyin: pinyin.Index("床前明月光\n疑是地上霜\n举头望明月\n低头思故乡"),
+		DatasetKey:    "tangsong",
+		DatasetName:   "全唐诗",
+		SourcePath:    "全唐诗/poet.tang.0.json",
+		SourceID:      "3ad6d468-7ff1-4a7b-8b24-a27d70d00ed4",
 	}
 	err = repo.InsertPoem(poem)
 	require.NoError(t, err)
@@ -88,6 +96,79 @@ func TestPoemQuery(t *testing.T) {
 		assert.Len(t, resp.Poem.Content, 4)
 	})
 
+	t.Run("get poem with pinyin", func(t *testing.T) {
+		var resp struct {
+			Poem struct {
+				Pinyin struct {
+					Title   string
+					Content []string
+				}
+			}
+		}
+
+		err := c.Post(`query { poem(id: "1") { pinyin { title content } } }`, &resp)
+		require.NoError(t, err)
+		assert.Equal(t, "jìng yè sī", resp.Poem.Pinyin.Title)
+		require.Len(t, resp.Poem.Pinyin.Content, 4)
+		assert.Equal(t, "chuáng qián míng yuè guāng", resp.Poem.Pinyin.Content[0])
+
+		err = c.Post(`query { poem(id: "1") { pinyin(style: NUMBERS) { title content } } }`, &resp)
+		require.NoError(t, err)
+		assert.Equal(t, "jing4 ye4 si1", resp.Poem.Pinyin.Title)
+	})
+
+	t.Run("get poem in Taiwan glyphs", func(t *testing.T) {
+		var resp struct {
+			Poem struct {
+				Title   string
+				Content []string
+				Author  struct {
+					Name string
+				}
+			}
+		}
+
+		err := c.Post(`query { poem(id: "1", lang: ZH_TW) { title content author { name } } }`, &resp)
+		require.NoError(t, err)
+		assert.Equal(t, "靜夜思", resp.Poem.Title)
+		require.Len(t, resp.Poem.Content, 4)
+		assert.Equal(t, "舉頭望明月", resp.Poem.Content[2])
+		assert.Equal(t, "李白", resp.Poem.Author.Name)
+	})
+
+	t.Run("get poem stanzas", func(t *testing.T) {
+		var resp struct {
+			Poem struct {
+				Stanzas [][]string
+			}
+		}
+
+		err := c.Post(`query { poem(id: "1", lang: ZH_TW) { stanzas } }`, &resp)
+		require.NoError(t, err)
+		require.Len(t, resp.Poem.Stanzas, 1)
+		require.Len(t, resp.Poem.Stanzas[0], 4)
+		assert.Equal(t, "舉頭望明月", resp.Poem.Stanzas[0][2])
+	})
+
+	t.Run("get poem source", func(t *testing.T) {
+		var resp struct {
+			Poem struct {
+				Source struct {
+					Dataset  string
+					File     string
+					RecordID *string
+				}
+			}
+		}
+
+		err := c.Post(`query { poem(id: "1") { source { dataset file recordId } } }`, &resp)
+		require.NoError(t, err)
+		assert.Equal(t, "tangsong", resp.Poem.Source.Dataset)
+		assert.Equal(t, "全唐诗/poet.tang.0.json", resp.Poem.Source.File)
+		require.NotNil(t, resp.Poem.Source.RecordID)
+		assert.Equal(t, "3ad6d468-7ff1-4a7b-8b24-a27d70d00ed4", *resp.Poem.Source.RecordID)
+	})
+
 	t.Run("get non-existent poem returns error", func(t *testing.T) {
 		var resp struct {
 			Poem *struct {
@@ -175,6 +256,96 @@ func TestSearchPoemsQuery(t *testing.T) {
 		err := c.Post(`query { searchPoems(query: "李白", searchType: AUTHOR) { totalCount } }`, &resp)
 		require.NoError(t, err)
 	})
+
+	t.Run("search with match details", func(t *testing.T) {
+		var resp struct {
+			SearchPoems struct {
+				Edges []struct {
+					Score     *float64
+					Highlight *string
+					Snippet   *string
+					Matches   []struct {
+						Field string
+						Line  int
+						Start int
+						End   int
+					}
+				}
+			}
+		}
+
+		err := c.Post(`query { searchPoems(query: "静夜思", sort: RELEVANCE) { edges { score highlight snippet matches { field line start end } } } }`, &resp)
+		require.NoError(t, err)
+		require.Len(t, resp.SearchPoems.Edges, 1)
+
+		edge := resp.SearchPoems.Edges[0]
+		require.NotNil(t, edge.Score)
+		require.NotNil(t, edge.Highlight)
+		assert.Equal(t, "<mark>静夜思</mark>", *edge.Highlight)
+		require.Len(t, edge.Matches, 1)
+		assert.Equal(t, "title", edge.Matches[0].Field)
+		assert.Equal(t, 0, edge.Matches[0].Start)
+		assert.Equal(t, 3, edge.Matches[0].End)
+	})
+
+	t.Run("search by pinyin", func(t *testing.T) {
+		var resp struct {
+			SearchPoems struct {
+				Edges []struct {
+					Highlight *string
+				}
+				TotalCount int
+			}
+		}
+
+		err := c.Post(`query { searchPoems(query: "jing ye si", searchType: PINYIN) { edges { highlight } totalCount } }`, &resp)
+		require.NoError(t, err)
+		assert.Equal(t, 1, resp.SearchPoems.TotalCount)
+		require.Len(t, resp.SearchPoems.Edges, 1)
+		require.NotNil(t, resp.SearchPoems.Edges[0].Highlight)
+		assert.Equal(t, "<mark>静夜思</mark>", *resp.SearchPoems.Edges[0].Highlight)
+
+		err = c.Post(`query { searchPoems(query: "静夜思", searchType: PINYIN) { totalCount } }`, &resp)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "in pinyin query")
+	})
+
+	t.Run("search sorted by id", func(t *testing.T) {
+		var resp struct {
+			SearchPoems struct {
+				TotalCount int
+			}
+		}
+
+		err := c.Post(`query { searchPoems(query: "静夜思", sort: ID) { totalCount } }`, &resp)
+		require.NoError(t, err)
+		assert.Equal(t, 1, resp.SearchPoems.TotalCount)
+	})
+
+	t.Run("search with query syntax", func(t *testing.T) {
+		var resp struct {
+			SearchPoems struct {
+				TotalCount int
+			}
+		}
+
+		err := c.Post(`query($q: String!) { searchPoems(query: $q) { totalCount } }`, &resp,
+			client.Var("q", `title:静夜思 -author:杜甫 "明月 光" OR 明月`))
+		require.NoError(t, err)
+		assert.Equal(t, 1, resp.SearchPoems.TotalCount)
+	})
+
+	t.Run("malformed query", func(t *testing.T) {
+		var resp struct {
+			SearchPoems struct {
+				TotalCount int
+			}
+		}
+
+		err := c.Post(`query { searchPoems(query: "静夜思 OR") { totalCount } }`, &resp)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "OR must come between two terms")
+	})
 }
 
 func TestAuthorsQuery(t *testing.T) {
@@ -198,6 +369,30 @@ func TestAuthorsQuery(t *testing.T) {
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, resp.Authors.TotalCount, 1)
 	})
+
+	t.Run("get author description", func(t *testing.T) {
+		_, err := repo.UpdateAuthorDescriptions([]database.AuthorDescription{
+			{Name: "李白", Dynasty: "唐", Description: "李白字太白，号青莲居士。"},
+		})
+		require.NoError(t, err)
+
+		var resp struct {
+			Authors struct {
+				Edges []struct {
+					Node struct {
+						Name        string
+						Description *string
+					}
+				}
+			}
+		}
+
+		err = c.Post(`query { authors { edges { node { name description } } } }`, &resp)
+		require.NoError(t, err)
+		require.Len(t, resp.Authors.Edges, 1)
+		require.NotNil(t, resp.Authors.Edges[0].Node.Description)
+		assert.Equal(t, "李白字太白，号青莲居士。", *resp.Authors.Edges[0].Node.Description)
+	})
 }
 
 func TestDynastiesQuery(t *testing.T) {
@@ -279,6 +474,42 @@ func TestRandomPoemQuery(t *testing.T) {
 	})
 }
 
+func TestDailyPoemQuery(t *testing.T) {
+	resolver, repo := setupTestResolver(t)
+	createTestData(t, repo)
+	c := createTestClient(t, resolver)
+
+	t.Run("get daily poem", func(t *testing.T) {
+		var resp struct {
+			DailyPoem *struct {
+				ID    int64
+				Title string
+			}
+		}
+
+		err := c.Post(`query { dailyPoem(date: "2024-03-01", timezone: "Asia/Shanghai") { id title } }`, &resp)
+		require.NoError(t, err)
+		require.NotNil(t, resp.DailyPoem)
+		assert.Equal(t, "静夜思", resp.DailyPoem.Title)
+
+		err = c.Post(`query { dailyPoem { id } }`, &resp)
+		require.NoError(t, err)
+		assert.NotNil(t, resp.DailyPoem)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		var resp struct {
+			DailyPoem *struct {
+				ID int64
+			}
+		}
+
+		err := c.Post(`query { dailyPoem(date: "March 1") { id } }`, &resp)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expected YYYY-MM-DD")
+	})
+}
+
 // Integration test for context passing
 func TestResolverWithContext(t *testing.T) {
 	resolver, repo := setupTestResolver(t)
@@ -335,12 +566,21 @@ func createExtendedTestData(t *testing.T, resolver *Resolver, repo *database.Rep
 			TypeID:    &typeID,
 		},
 		{
-			ID:        1003,
-			Title:     "清明",
-			Content:   datatypes.JSON([]byte(`["清明时节雨纷纷"]`)),
-			AuthorID:  &dumuAuthorID,
-			DynastyID: &tangDynastyID,
-			TypeID:    &typeID,
+			ID:          1003,
+			Title:       "清明",
+			Content:     datatypes.JSON([]byte(`["清明时节雨纷纷"]`)),
+			AuthorID:    &dumuAuthorID,
+			DynastyID:   &tangDynastyID,
+			TypeID:      &typeID,
+			MeterStatus: "rescued",
+			MeterDetail: datatypes.JSON([]byte(`{"status":"rescued","pattern":"平起首句入韵",` +
+				`"tones":["平平平仄仄平平"],"deviations":[{"line":1,"position":3,"kind":"ao","rescued":true}]}`)),
+			RhymeDetail: datatypes.JSON([]byte(`{"system":"pingshui","groups":["十二文"],` +
+				`"positions":[{"line":1,"char":"纷","group":"十二文","out_of_rhyme":false}]}`)),
+			DatasetKey:  "shuimotangshi",
+			DatasetName: "水墨唐诗",
+			SourcePath:  "水墨唐诗/shuimotangshi.json",
+			SourceIndex: 7,
 		},
 	}
 
@@ -425,6 +665,143 @@ func TestPoemsWithFilters(t *testing.T) {
 		assert.Equal(t, 2, resp.Poems.TotalCount)
 	})
 
+	t.Run("filter by meter", func(t *testing.T) {
+		var resp struct {
+			Poems struct {
+				Edges []struct {
+					Node struct {
+						Title string
+						Meter *struct {
+							Status     string
+							Pattern    string
+							Deviations []struct {
+								Line     int
+								Position int
+								Kind     string
+								Rescued  bool
+							}
+						}
+					}
+				}
+				TotalCount int
+			}
+		}
+
+		query := `query { poems(meter: COMPLIANT) { edges { node { title meter { status pattern deviations { line position kind rescued } } } } totalCount } }`
+		err := c.Post(query, &resp)
+		require.NoError(t, err)
+		require.Equal(t, 1, resp.Poems.TotalCount)
+		node := resp.Poems.Edges[0].Node
+		assert.Equal(t, "清明", node.Title)
+		require.NotNil(t, node.Meter)
+		assert.Equal(t, "RESCUED", node.Meter.Status)
+		assert.Equal(t, "平起首句入韵", node.Meter.Pattern)
+		require.Len(t, node.Meter.Deviations, 1)
+		assert.True(t, node.Meter.Deviations[0].Rescued)
+
+		err = c.Post(`query { poems(meter: STRICT) { totalCount } }`, &resp)
+		require.NoError(t, err)
+		assert.Equal(t, 0, resp.Poems.TotalCount)
+	})
+
+	t.Run("filter by rhyme", func(t *testing.T) {
+		var resp struct {
+			Poems struct {
+				Edges []struct {
+					Node struct {
+						Title string
+						Rhyme *struct {
+							System    string
+							Groups    []string
+							Positions []struct {
+								Line       int
+								Char       string
+								Group      string
+								OutOfRhyme bool
+							}
+						}
+					}
+				}
+				TotalCount int
+			}
+		}
+
+		query := `query { poems(rhyme: "文") { edges { node { title rhyme { system groups positions { line char group outOfRhyme } } } } totalCount } }`
+		err := c.Post(query, &resp)
+		require.NoError(t, err)
+		require.Equal(t, 1, resp.Poems.TotalCount)
+		node := resp.Poems.Edges[0].Node
+		assert.Equal(t, "清明", node.Title)
+		require.NotNil(t, node.Rhyme)
+		assert.Equal(t, "PINGSHUI", node.Rhyme.System)
+		assert.Equal(t, []string{"十二文"}, node.Rhyme.Groups)
+		require.Len(t, node.Rhyme.Positions, 1)
+		assert.Equal(t, "纷", node.Rhyme.Positions[0].Char)
+
+		err = c.Post(`query { poems(rhyme: "十一尤") { totalCount } }`, &resp)
+		require.NoError(t, err)
+		assert.Equal(t, 0, resp.Poems.TotalCount)
+
+		err = c.Post(`query { poems(rhyme: "十六尤") { totalCount } }`, &resp)
+		assert.Error(t, err)
+	})
+
+	t.Run("filter by dataset", func(t *testing.T) {
+		var resp struct {
+			Poems struct {
+				Edges []struct {
+					Node struct {
+						Title  string
+						Source struct {
+							Dataset     string
+							DatasetName string
+							File        string
+							RecordID    *string
+							Index       int
+						}
+					}
+				}
+				TotalCount int
+			}
+		}
+
+		query := `query { poems(dataset: "shuimotangshi") { edges { node { title source { dataset datasetName file recordId index } } } totalCount } }`
+		err := c.Post(query, &resp)
+		require.NoError(t, err)
+		require.Equal(t, 1, resp.Poems.TotalCount)
+		node := resp.Poems.Edges[0].Node
+		assert.Equal(t, "清明", node.Title)
+		assert.Equal(t, "shuimotangshi", node.Source.Dataset)
+		assert.Equal(t, "水墨唐诗", node.Source.DatasetName)
+		assert.Equal(t, "水墨唐诗/shuimotangshi.json", node.Source.File)
+		assert.Nil(t, node.Source.RecordID)
+		assert.Equal(t, 7, node.Source.Index)
+
+		err = c.Post(`query { poems(dataset: "songci") { totalCount } }`, &resp)
+		require.NoError(t, err)
+		assert.Equal(t, 0, resp.Poems.TotalCount)
+	})
+
+	t.Run("poems without meter analysis", func(t *testing.T) {
+		var resp struct {
+			Poems struct {
+				Edges []struct {
+					Node struct {
+						Meter *struct {
+							Status string
+						}
+					}
+				}
+			}
+		}
+
+		query := fmt.Sprintf(`query { poems(authorId: "%d") { edges { node { meter { status } } } } }`, libaiID)
+		err := c.Post(query, &resp)
+		require.NoError(t, err)
+		require.Len(t, resp.Poems.Edges, 2)
+		assert.Nil(t, resp.Poems.Edges[0].Node.Meter)
+	})
+
 	t.Run("filter with non-existent dynastyId returns empty", func(t *testing.T) {
 		var resp struct {
 			Poems struct {
@@ -539,6 +916,132 @@ func TestPaginationBoundaries(t *testing.T) {
 	})
 }
 
+// TestCursorPagination tests keyset pagination of poems and authors via after/before
+func TestCursorPagination(t *testing.T) {
+	resolver, repo := setupTestResolver(t)
+	createExtendedTestData(t, resolver, repo)
+	c := createTestClient(t, resolver)
+
+	type poemsResp struct {
+		Poems struct {
+			Edges []struct {
+				Node struct {
+					ID int64
+				}
+				Cursor string
+			}
+			PageInfo struct {
+				HasNextPage     bool
+				HasPreviousPage bool
+				EndCursor       string
+				StartCursor     string
+			}
+			TotalCount int
+		}
+	}
+
+	ids := func(resp poemsResp) []int64 {
+		var got []int64
+		for _, edge := range resp.Poems.Edges {
+			got = append(got, edge.Node.ID)
+		}
+		return got
+	}
+
+	var first poemsResp
+	err := c.Post(`query { poems(pageSize: 2) { edges { node { id } cursor } pageInfo { hasNextPage hasPreviousPage endCursor startCursor } totalCount } }`, &first)
+	require.NoError(t, err)
+	// Poems are listed by descending ID
+	assert.Equal(t, []int64{1003, 1002}, ids(first))
+	assert.True(t, first.Poems.PageInfo.HasNextPage)
+	assert.False(t, first.Poems.PageInfo.HasPreviousPage)
+	assert.Equal(t, first.Poems.Edges[1].Cursor, first.Poems.PageInfo.EndCursor)
+
+	t.Run("after end cursor", func(t *testing.T) {
+		var resp poemsResp
+		query := fmt.Sprintf(`query { poems(pageSize: 2, after: %q) { edges { node { id } cursor } pageInfo { hasNextPage hasPreviousPage endCursor startCursor } totalCount } }`, first.Poems.PageInfo.EndCursor)
+		err := c.Post(query, &resp)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1001}, ids(resp))
+		assert.False(t, resp.Poems.PageInfo.HasNextPage)
+		assert.True(t, resp.Poems.PageInfo.HasPreviousPage)
+		assert.Equal(t, 3, resp.Poems.TotalCount)
+	})
+
+	t.Run("after a cursor ahead of the first poem", func(t *testing.T) {
+		var resp poemsResp
+		query := fmt.Sprintf(`query { poems(pageSize: 2, after: %q) { edges { node { id } cursor } pageInfo { hasNextPage hasPreviousPage endCursor startCursor } totalCount } }`, database.EncodePoemCursor(9999))
+		err := c.Post(query, &resp)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1003, 1002}, ids(resp))
+		assert.True(t, resp.Poems.PageInfo.HasNextPage)
+		assert.False(t, resp.Poems.PageInfo.HasPreviousPage)
+	})
+
+	t.Run("before a cursor", func(t *testing.T) {
+		var resp poemsResp
+		query := fmt.Sprintf(`query { poems(pageSize: 1, before: %q) { edges { node { id } cursor } pageInfo { hasNextPage hasPreviousPage endCursor startCursor } totalCount } }`, first.Poems.Edges[1].Cursor)
+		err := c.Post(query, &resp)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1003}, ids(resp))
+		assert.True(t, resp.Poems.PageInfo.HasNextPage)
+		assert.False(t, resp.Poems.PageInfo.HasPreviousPage)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		var resp poemsResp
+		err := c.Post(`query { poems(after: "not-a-cursor") { totalCount } }`, &resp)
+		assert.Error(t, err)
+	})
+
+	t.Run("after and before together", func(t *testing.T) {
+		var resp poemsResp
+		query := fmt.Sprintf(`query { poems(after: %q, before: %q) { totalCount } }`, first.Poems.PageInfo.StartCursor, first.Poems.PageInfo.EndCursor)
+		err := c.Post(query, &resp)
+		assert.Error(t, err)
+	})
+
+	t.Run("authors", func(t *testing.T) {
+		var resp struct {
+			Authors struct {
+				Edges []struct {
+					Node struct {
+						Name string
+					}
+				}
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   string
+				}
+			}
+		}
+
+		err := c.Post(`query { authors(pageSize: 1) { edges { node { name } } pageInfo { hasNextPage endCursor } } }`, &resp)
+		require.NoError(t, err)
+		require.Len(t, resp.Authors.Edges, 1)
+		assert.Equal(t, "李白", resp.Authors.Edges[0].Node.Name) // 2 poems
+		assert.True(t, resp.Authors.PageInfo.HasNextPage)
+
+		query := fmt.Sprintf(`query { authors(pageSize: 1, after: %q) { edges { node { name } } pageInfo { hasNextPage endCursor } } }`, resp.Authors.PageInfo.EndCursor)
+		err = c.Post(query, &resp)
+		require.NoError(t, err)
+		require.Len(t, resp.Authors.Edges, 1)
+		assert.Equal(t, "杜牧", resp.Authors.Edges[0].Node.Name) // 1 poem
+		assert.False(t, resp.Authors.PageInfo.HasNextPage)
+	})
+
+	t.Run("poem cursor rejected for authors", func(t *testing.T) {
+		var resp struct {
+			Authors struct {
+				TotalCount int
+			}
+		}
+		query := fmt.Sprintf(`query { authors(after: %q) { totalCount } }`, first.Poems.PageInfo.EndCursor)
+		err := c.Post(query, &resp)
+		assert.Error(t, err)
+	})
+}
+
 // TestAuthorsWithFilters tests GraphQL authors query with dynastyId filter
 func TestAuthorsWithFilters(t *testing.T) {
 	resolver, repo := setupTestResolver(t)
@@ -8,23 +8,33 @@ package graph
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/palemoky/chinese-poetry-api/internal/classifier"
 	"github.com/palemoky/chinese-poetry-api/internal/database"
 	"github.com/palemoky/chinese-poetry-api/internal/graph/generated"
 	"github.com/palemoky/chinese-poetry-api/internal/graph/model"
+	"github.com/palemoky/chinese-poetry-api/internal/helpers"
+	"github.com/palemoky/chinese-poetry-api/internal/pinyin"
 )
 
 // Poems is the resolver for the poems field.
-func (r *authorResolver) Poems(ctx context.Context, obj *database.Author, page *int, pageSize *int) (*database.PoemConnection, error) {
-	pag := parsePagination(page, pageSize)
+func (r *authorResolver) Poems(ctx context.Context, obj *database.Author, page *int, pageSize *int, after *string, before *string) (*database.PoemConnection, error) {
+	pag, err := parsePagination(page, pageSize).withCursors(after, before, database.DecodePoemCursor)
+	if err != nil {
+		return nil, err
+	}
 
-	poems, totalCount, err := r.Repo.ListAuthorPoems(obj.ID, pag.PageSize, pag.Offset)
+	filter := database.PoemFilter{AuthorIDs: []int64{obj.ID}}
+	poems, totalCount, adjacent, err := r.Repo.PagePoemsByFilter(filter, pag.poemPage())
 	if err != nil {
 		return nil, err
 	}
 
-	return buildPoemConnection(poems, pag, totalCount), nil
+	return buildPoemConnection(poems, totalCount, adjacent), nil
 }
 
 // PoemCount is the resolver for the poemCount field.
@@ -62,6 +72,88 @@ func (r *poemResolver) Content(ctx context.Context, obj *database.Poem) ([]strin
 	return content, nil
 }
 
+// Stanzas is the resolver for the stanzas field.
+func (r *poemResolver) Stanzas(ctx context.Context, obj *database.Poem) ([][]string, error) {
+	return obj.Stanzas()
+}
+
+// Pinyin is the resolver for the pinyin field.
+func (r *poemResolver) Pinyin(ctx context.Context, obj *database.Poem, style *model.PinyinStyle) (*model.PoemPinyin, error) {
+	content, err := r.Content(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	s := pinyin.StyleMarks
+	if style != nil && *style == model.PinyinStyleNumbers {
+		s = pinyin.StyleNumbers
+	}
+
+	result := &model.PoemPinyin{
+		Title:   pinyin.Annotate(obj.Title, s),
+		Content: make([]string, len(content)),
+	}
+	for i, line := range content {
+		result.Content[i] = pinyin.Annotate(line, s)
+	}
+	return result, nil
+}
+
+// Meter is the resolver for the meter field.
+func (r *poemResolver) Meter(ctx context.Context, obj *database.Poem) (*model.Meter, error) {
+	if len(obj.MeterDetail) == 0 {
+		return nil, nil
+	}
+
+	var analysis classifier.MeterAnalysis
+	if err := json.Unmarshal(obj.MeterDetail, &analysis); err != nil {
+		return nil, err
+	}
+
+	result := &model.Meter{
+		Status:     model.MeterStatus(strings.ToUpper(analysis.Status)),
+		Pattern:    analysis.Pattern,
+		Tones:      analysis.Tones,
+		Deviations: make([]*model.MeterDeviation, len(analysis.Deviations)),
+	}
+	for i, d := range analysis.Deviations {
+		result.Deviations[i] = &model.MeterDeviation{
+			Line:     d.Line,
+			Position: d.Position,
+			Kind:     d.Kind,
+			Rescued:  d.Rescued,
+		}
+	}
+	return result, nil
+}
+
+// Rhyme is the resolver for the rhyme field.
+func (r *poemResolver) Rhyme(ctx context.Context, obj *database.Poem) (*model.Rhyme, error) {
+	if len(obj.RhymeDetail) == 0 {
+		return nil, nil
+	}
+
+	var analysis classifier.RhymeAnalysis
+	if err := json.Unmarshal(obj.RhymeDetail, &analysis); err != nil {
+		return nil, err
+	}
+
+	result := &model.Rhyme{
+		System:    model.RhymeSystem(strings.ToUpper(analysis.System)),
+		Groups:    analysis.Groups,
+		Positions: make([]*model.RhymePosition, len(analysis.Positions)),
+	}
+	for i, p := range analysis.Positions {
+		result.Positions[i] = &model.RhymePosition{
+			Line:       p.Line,
+			Char:       p.Char,
+			Group:      p.Group,
+			OutOfRhyme: p.OutOfRhyme,
+		}
+	}
+	return result, nil
+}
+
 // PoemCount is the resolver for the poemCount field.
 func (r *poetryTypeResolver) PoemCount(ctx context.Context, obj *database.PoetryType) (int, error) {
 	var count int64
@@ -71,7 +163,11 @@ func (r *poetryTypeResolver) PoemCount(ctx context.Context, obj *database.Poetry
 
 // Poem is the resolver for the poem field.
 func (r *queryResolver) Poem(ctx context.Context, id string, lang *database.Lang) (*database.Poem, error) {
-	poem, err := r.Repo.GetPoemByID(id)
+	langVal, err := parseLang(lang)
+	if err != nil {
+		return nil, err
+	}
+	poem, err := r.Repo.WithLang(langVal).GetPoemByID(id)
 	if err != nil {
 		return nil, err
 	}
@@ -79,8 +175,15 @@ func (r *queryResolver) Poem(ctx context.Context, id string, lang *database.Lang
 }
 
 // Poems is the resolver for the poems field.
-func (r *queryResolver) Poems(ctx context.Context, lang *database.Lang, page *int, pageSize *int, dynastyID *string, authorID *string, typeID *string) (*database.PoemConnection, error) {
-	pag := parsePagination(page, pageSize)
+func (r *queryResolver) Poems(ctx context.Context, lang *database.Lang, page *int, pageSize *int, dynastyID *string, authorID *string, typeID *string, meter *model.MeterFilter, rhyme *string, dataset *string, after *string, before *string) (*database.PoemConnection, error) {
+	langVal, err := parseLang(lang)
+	if err != nil {
+		return nil, err
+	}
+	pag, err := parsePagination(page, pageSize).withCursors(after, before, database.DecodePoemCursor)
+	if err != nil {
+		return nil, err
+	}
 
 	// Parse filter IDs
 	dynastyIDInt, err := parseOptionalID(dynastyID)
@@ -96,16 +199,40 @@ func (r *queryResolver) Poems(ctx context.Context, lang *database.Lang, page *in
 		return nil, err
 	}
 
-	poems, totalCount, err := r.Repo.ListPoemsWithFilter(pag.PageSize, pag.Offset, dynastyIDInt, authorIDInt, typeIDInt)
+	var filter database.PoemFilter
+	if dynastyIDInt != nil {
+		filter.DynastyIDs = []int64{*dynastyIDInt}
+	}
+	if authorIDInt != nil {
+		filter.AuthorIDs = []int64{*authorIDInt}
+	}
+	if typeIDInt != nil {
+		filter.TypeIDs = []int64{*typeIDInt}
+	}
+	if meter != nil {
+		filter.MeterStatuses, _ = classifier.MeterStatuses(strings.ToLower(string(*meter)))
+	}
+	if rhyme != nil {
+		group, ok := classifier.RhymeGroup(*rhyme)
+		if !ok {
+			return nil, errors.New("unknown rhyme group " + *rhyme)
+		}
+		filter.RhymeGroups = []string{group}
+	}
+	if dataset != nil {
+		filter.Datasets = []string{*dataset}
+	}
+
+	poems, totalCount, adjacent, err := r.Repo.WithLang(langVal).PagePoemsByFilter(filter, pag.poemPage())
 	if err != nil {
 		return nil, err
 	}
 
-	return buildPoemConnection(poems, pag, totalCount), nil
+	return buildPoemConnection(poems, totalCount, adjacent), nil
 }
 
 // SearchPoems is the resolver for the searchPoems field.
-func (r *queryResolver) SearchPoems(ctx context.Context, query string, lang *database.Lang, searchType *model.SearchType, page *int, pageSize *int) (*database.PoemConnection, error) {
+func (r *queryResolver) SearchPoems(ctx context.Context, query string, lang *database.Lang, searchType *model.SearchType, sort *model.SearchSort, page *int, pageSize *int) (*database.PoemConnection, error) {
 	p := 1
 	if page != nil {
 		p = *page
@@ -125,22 +252,42 @@ func (r *queryResolver) SearchPoems(ctx context.Context, query string, lang *dat
 			st = "content"
 		case model.SearchTypeAuthor:
 			st = "author"
+		case model.SearchTypePinyin:
+			st = "pinyin"
 		}
 	}
 
+	order := database.SearchOrderRelevance
+	if sort != nil && *sort == model.SearchSortID {
+		order = database.SearchOrderID
+	}
+
 	// Use repository's SearchPoems with language context
-	langVal := parseLang(lang)
+	langVal, err := parseLang(lang)
+	if err != nil {
+		return nil, err
+	}
 	repo := r.Repo.WithLang(langVal)
-	poems, total, err := repo.SearchPoems(query, st, p, ps)
+	results, total, err := repo.SearchPoemsWithOptions(query, database.SearchOptions{
+		Type:     st,
+		Order:    order,
+		Page:     p,
+		PageSize: ps,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	edges := make([]database.PoemEdge, len(poems))
-	for i, poem := range poems {
+	edges := make([]database.PoemEdge, len(results))
+	for i := range results {
+		result := &results[i]
 		edges[i] = database.PoemEdge{
-			Node:   poem,
-			Cursor: strconv.Itoa(i),
+			Node:      result.Poem,
+			Cursor:    strconv.Itoa(i),
+			Score:     &result.Score,
+			Highlight: &result.Highlight,
+			Snippet:   &result.Snippet,
+			Matches:   result.Matches,
 		}
 	}
 
@@ -172,44 +319,99 @@ func (r *queryResolver) RandomPoem(ctx context.Context, lang *database.Lang, dyn
 	}
 
 	// Use repository's GetRandomPoem with language context (same as REST)
-	langVal := parseLang(lang)
+	langVal, err := parseLang(lang)
+	if err != nil {
+		return nil, err
+	}
 	repo := r.Repo.WithLang(langVal)
 	return repo.GetRandomPoem(dynastyIDInt, nil, typeIDs)
 }
 
+// DailyPoem is the resolver for the dailyPoem field.
+func (r *queryResolver) DailyPoem(ctx context.Context, date *string, timezone *string, lang *database.Lang, dynastyID *string, authorID *string, typeID *string) (*database.Poem, error) {
+	langVal, err := parseLang(lang)
+	if err != nil {
+		return nil, err
+	}
+	var dateStr, tzStr string
+	if date != nil {
+		dateStr = *date
+	}
+	if timezone != nil {
+		tzStr = *timezone
+	}
+	day, err := helpers.ParseDay(dateStr, tzStr, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	dynastyIDInt, authorIDInt, typeIDInt, err := helpers.ParseFilterIDs(dynastyID, authorID, typeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter database.PoemFilter
+	if dynastyIDInt != nil {
+		filter.DynastyIDs = []int64{*dynastyIDInt}
+	}
+	if authorIDInt != nil {
+		filter.AuthorIDs = []int64{*authorIDInt}
+	}
+	if typeIDInt != nil {
+		filter.TypeIDs = []int64{*typeIDInt}
+	}
+
+	repo := r.Repo.WithLang(langVal)
+	return repo.GetDailyPoemByFilter(day, filter)
+}
+
 // Author is the resolver for the author field.
 func (r *queryResolver) Author(ctx context.Context, id string, lang *database.Lang) (*database.Author, error) {
+	langVal, err := parseLang(lang)
+	if err != nil {
+		return nil, err
+	}
 	authorID, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
 		return nil, err
 	}
 
 	// Use Repository method which handles dynamic table names
-	return r.Repo.GetAuthorByID(authorID)
+	return r.Repo.WithLang(langVal).GetAuthorByID(authorID)
 }
 
 // Authors is the resolver for the authors field.
-func (r *queryResolver) Authors(ctx context.Context, lang *database.Lang, page *int, pageSize *int, dynastyID *string) (*database.AuthorConnection, error) {
-	pag := parsePagination(page, pageSize)
+func (r *queryResolver) Authors(ctx context.Context, lang *database.Lang, page *int, pageSize *int, dynastyID *string, after *string, before *string) (*database.AuthorConnection, error) {
+	langVal, err := parseLang(lang)
+	if err != nil {
+		return nil, err
+	}
+	pag, err := parsePagination(page, pageSize).withCursors(after, before, database.DecodeAuthorCursor)
+	if err != nil {
+		return nil, err
+	}
 
 	dynastyIDInt, err := parseOptionalID(dynastyID)
 	if err != nil {
 		return nil, err
 	}
 
-	authors, totalCount, err := r.Repo.ListAuthorsWithFilter(pag.PageSize, pag.Offset, dynastyIDInt)
+	authors, totalCount, adjacent, err := r.Repo.WithLang(langVal).PageAuthors(dynastyIDInt, pag.dbPage())
 	if err != nil {
 		return nil, err
 	}
 
-	return buildAuthorConnection(authors, pag, totalCount), nil
+	return buildAuthorConnection(authors, totalCount, adjacent), nil
 }
 
 // Dynasties is the resolver for the dynasties field.
 func (r *queryResolver) Dynasties(ctx context.Context, lang *database.Lang) ([]*database.Dynasty, error) {
+	langVal, err := parseLang(lang)
+	if err != nil {
+		return nil, err
+	}
 	var dynasties []*database.Dynasty
-	langVal := parseLang(lang)
-	err := r.DB.Table(database.DynastiesTable(langVal)).Order("id").Find(&dynasties).Error
+	err = r.DB.Table(database.DynastiesTable(langVal)).Order("id").Find(&dynasties).Error
 	if err != nil {
 		return nil, err
 	}
@@ -218,9 +420,12 @@ func (r *queryResolver) Dynasties(ctx context.Context, lang *database.Lang) ([]*
 
 // PoemTypes is the resolver for the poemTypes field.
 func (r *queryResolver) PoemTypes(ctx context.Context, lang *database.Lang) ([]*database.PoetryType, error) {
+	langVal, err := parseLang(lang)
+	if err != nil {
+		return nil, err
+	}
 	var types []*database.PoetryType
-	langVal := parseLang(lang)
-	err := r.DB.Table(database.PoetryTypesTable(langVal)).Order("id").Find(&types).Error
+	err = r.DB.Table(database.PoetryTypesTable(langVal)).Order("id").Find(&types).Error
 	if err != nil {
 		return nil, err
 	}
@@ -229,7 +434,11 @@ func (r *queryResolver) PoemTypes(ctx context.Context, lang *database.Lang) ([]*
 
 // Statistics is the resolver for the statistics field.
 func (r *queryResolver) Statistics(ctx context.Context, lang *database.Lang) (*database.Statistics, error) {
-	return r.Repo.GetStatistics()
+	langVal, err := parseLang(lang)
+	if err != nil {
+		return nil, err
+	}
+	return r.Repo.WithLang(langVal).GetStatistics()
 }
 
 // PoemsByDynasty is the resolver for the poemsByDynasty field.
@@ -1,7 +1,11 @@
 package helpers
 
 import (
+	"fmt"
 	"strconv"
+	"time"
+	// Embedded so timezones resolve in images without a zoneinfo database
+	_ "time/tzdata"
 
 	"github.com/palemoky/chinese-poetry-api/internal/database"
 )
@@ -38,22 +42,44 @@ func ParseFilterIDs(dynastyID, authorID, typeID *string) (*int64, *int64, *int64
 }
 
 // ParseLangString converts string to Lang enum
-// Supports "zh-Hans" (simplified) and "zh-Hant" (traditional)
-// Defaults to simplified Chinese
-func ParseLangString(langStr string) database.Lang {
-	if langStr == "zh-Hant" {
-		return database.LangHant
+// Supports the variants and spellings accepted by database.ParseLang
+// Defaults to simplified Chinese when empty; unknown values are an error
+func ParseLangString(langStr string) (database.Lang, error) {
+	if langStr == "" {
+		return database.LangHans, nil
 	}
-	return database.LangHans
+	return database.ParseLang(langStr)
 }
 
 // ParseLangPointer converts *Lang to Lang with default
-// Returns simplified Chinese if pointer is nil
-func ParseLangPointer(lang *database.Lang) database.Lang {
-	if lang != nil {
-		return *lang
+// Returns simplified Chinese if pointer is nil; unknown values are an error
+func ParseLangPointer(lang *database.Lang) (database.Lang, error) {
+	if lang == nil {
+		return database.LangHans, nil
 	}
-	return database.LangHans
+	return ParseLangString(string(*lang))
+}
+
+// ParseDay returns the calendar day named by date, or when date is empty the
+// day it is at now in timezone, formatted as YYYY-MM-DD
+// timezone is an IANA name such as "Asia/Shanghai" and defaults to UTC
+func ParseDay(date, timezone string, now time.Time) (string, error) {
+	loc := time.UTC
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return "", fmt.Errorf("invalid timezone %q", timezone)
+		}
+	}
+
+	if date == "" {
+		return now.In(loc).Format(time.DateOnly), nil
+	}
+	day, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date)
+	}
+	return day.Format(time.DateOnly), nil
 }
 
 // Pagination represents pagination parameters
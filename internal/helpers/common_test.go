@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -58,19 +59,29 @@ func TestParseOptionalInt64(t *testing.T) {
 
 func TestParseLangString(t *testing.T) {
 	tests := []struct {
-		name  string
-		input string
-		want  database.Lang
+		name    string
+		input   string
+		want    database.Lang
+		wantErr bool
 	}{
-		{"simplified", "zh-Hans", database.LangHans},
-		{"traditional", "zh-Hant", database.LangHant},
-		{"empty defaults to simplified", "", database.LangHans},
-		{"invalid defaults to simplified", "en", database.LangHans},
+		{"simplified", "zh-Hans", database.LangHans, false},
+		{"traditional", "zh-Hant", database.LangHant, false},
+		{"taiwan", "zh-TW", database.LangTW, false},
+		{"hong kong", "zh-HK", database.LangHK, false},
+		{"shinjitai", "ja", database.LangJa, false},
+		{"GraphQL enum value", "ZH_HANT", database.LangHant, false},
+		{"empty defaults to simplified", "", database.LangHans, false},
+		{"invalid", "en", "", true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := ParseLangString(tt.input)
+			got, err := ParseLangString(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -79,20 +90,64 @@ func TestParseLangString(t *testing.T) {
 func TestParseLangPointer(t *testing.T) {
 	hans := database.LangHans
 	hant := database.LangHant
+	enumTW := database.Lang("ZH_TW")
+	invalid := database.Lang("en")
 
 	tests := []struct {
-		name  string
-		input *database.Lang
-		want  database.Lang
+		name    string
+		input   *database.Lang
+		want    database.Lang
+		wantErr bool
 	}{
-		{"nil defaults to simplified", nil, database.LangHans},
-		{"simplified", &hans, database.LangHans},
-		{"traditional", &hant, database.LangHant},
+		{"nil defaults to simplified", nil, database.LangHans, false},
+		{"simplified", &hans, database.LangHans, false},
+		{"traditional", &hant, database.LangHant, false},
+		{"GraphQL enum value", &enumTW, database.LangTW, false},
+		{"invalid", &invalid, "", true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := ParseLangPointer(tt.input)
+			got, err := ParseLangPointer(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseDay(t *testing.T) {
+	// 2024-03-01 20:30 UTC is already 2024-03-02 in Shanghai
+	now := time.Date(2024, 3, 1, 20, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		date     string
+		timezone string
+		want     string
+		wantErr  bool
+	}{
+		{"today defaults to UTC", "", "", "2024-03-01", false},
+		{"today in timezone", "", "Asia/Shanghai", "2024-03-02", false},
+		{"today west of UTC", "", "America/Los_Angeles", "2024-03-01", false},
+		{"explicit date", "2023-12-31", "", "2023-12-31", false},
+		{"explicit date ignores timezone", "2023-12-31", "Asia/Shanghai", "2023-12-31", false},
+		{"invalid date", "2023-13-01", "", "", true},
+		{"wrong date format", "2023/12/31", "", "", true},
+		{"invalid timezone", "", "Mars/Olympus", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDay(tt.date, tt.timezone, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
 			assert.Equal(t, tt.want, got)
 		})
 	}
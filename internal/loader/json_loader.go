@@ -1,10 +1,19 @@
 package loader
 
 import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 // DataConfig represents the structure of datas.json
@@ -79,99 +88,274 @@ func NewJSONLoader(configPath string) (*JSONLoader, error) {
 	}, nil
 }
 
-// LoadAll loads all poetry data from all datasets
-func (l *JSONLoader) LoadAll() ([]PoemWithMeta, error) {
-	var allPoems []PoemWithMeta
-
-	for key, dataset := range l.config.Datasets {
-		poems, err := l.loadDataset(key, dataset)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load dataset %s: %w", key, err)
+// Poems streams the poems of all datasets, in a fixed order so that they
+// come in the same way every time. Files are read one at a time, so only the
+// poems of the file being read are held in memory; ranging over the sequence
+// again reads them anew. A file of a dataset directory that fails to load is
+// skipped with a warning, while a dataset that fails to load ends the sequence
+// with its error.
+func (l *JSONLoader) Poems() iter.Seq2[PoemWithMeta, error] {
+	return func(yield func(PoemWithMeta, error) bool) {
+		for _, key := range slices.Sorted(maps.Keys(l.config.Datasets)) {
+			if err := l.loadDataset(key, l.config.Datasets[key], yield); err != nil {
+				if !errors.Is(err, errStopped) {
+					yield(PoemWithMeta{}, fmt.Errorf("failed to load dataset %s: %w", key, err))
+				}
+				return
+			}
 		}
-		allPoems = append(allPoems, poems...)
 	}
-
-	return allPoems, nil
 }
 
+// errStopped is returned by loadDataset when yield asked to stop
+var errStopped = errors.New("stopped")
+
 // PoemWithMeta includes metadata about the poem's source
 type PoemWithMeta struct {
 	PoemData
 	Dynasty     string
 	DatasetName string
 	DatasetKey  string
+	SourceFile  string // Name of the JSON file within the dataset
+	SourcePath  string // Path of the JSON file relative to the data root, with forward slashes
+	SourceIndex int    // Position of the record in that file, counting every record
+	Occurrence  int    // Earlier records of the dataset without an id with the same content key
 }
 
-func (l *JSONLoader) loadDataset(key string, dataset DatasetInfo) ([]PoemWithMeta, error) {
-	fullPath := filepath.Join(l.basePath, dataset.Path)
-	dynasty := l.idToDynasty[dataset.ID]
+// SourceKey identifies the poem within the source data across versions of it:
+// its dataset and upstream id, or for poems without one, its content key, so
+// that records added, removed or moved around it don't change it. Records of
+// a dataset sharing a content key are told apart by their Occurrence.
+func (p PoemWithMeta) SourceKey() string {
+	if p.ID != "" {
+		return p.DatasetKey + ":" + p.ID
+	}
+	key := p.DatasetKey + ":" + p.contentKey()
+	if p.Occurrence > 0 {
+		key += "#" + strconv.Itoa(p.Occurrence)
+	}
+	return key
+}
 
-	info, err := os.Stat(fullPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to stat path %s: %w", fullPath, err)
+// contentKey identifies a record without an upstream id by what it says: its
+// title (or 词牌, or chapter), author and first line, ignoring whitespace
+func (p PoemWithMeta) contentKey() string {
+	var firstLine string
+	for _, paragraph := range p.Paragraphs {
+		if firstLine = keyText(paragraph); firstLine != "" {
+			break
+		}
 	}
+	if i := strings.IndexAny(firstLine, "。！？；"); i >= 0 {
+		firstLine = firstLine[:i]
+	}
+	return keyText(cmp.Or(p.Title, p.Rhythmic, p.Chapter)) + "/" + keyText(p.Author) + "/" + firstLine
+}
+
+// keyText drops the whitespace of s
+func keyText(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
+
+// SourceHash fingerprints the poem's source record, so that a change to it
+// upstream can be told from the key alone staying the same. Where the record
+// lies is left out, so that records added or removed before it don't change it.
+func (p PoemWithMeta) SourceHash() string {
+	data, _ := json.Marshal(struct {
+		PoemData
+		Dynasty, DatasetName, DatasetKey string
+	}{p.PoemData, p.Dynasty, p.DatasetName, p.DatasetKey})
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// AuthorData is an author's record in the author file of a dataset
+type AuthorData struct {
+	Name        string
+	Description string
+	Dynasty     string // Dynasty of the dataset, the one its poems are given
+	DatasetKey  string
+}
 
-	var poems []PoemWithMeta
+// LoadAuthors loads the author files (author*.json, such as authors.tang.json
+// or author.song.json) found beside the poem files of each dataset. Datasets
+// leave them out of their poems with Excludes, so they are found by name.
+func (l *JSONLoader) LoadAuthors() ([]AuthorData, error) {
+	var authors []AuthorData
+	seen := make(map[[2]string]bool)
 
-	if info.IsDir() {
-		// Load from directory
-		entries, err := os.ReadDir(fullPath)
+	for _, key := range slices.Sorted(maps.Keys(l.config.Datasets)) {
+		dataset := l.config.Datasets[key]
+		dynasty := l.idToDynasty[dataset.ID]
+
+		dir := filepath.Join(l.basePath, dataset.Path)
+		info, err := os.Stat(dir)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read directory %s: %w", fullPath, err)
+			return nil, fmt.Errorf("failed to stat path %s: %w", dir, err)
+		}
+		if !info.IsDir() {
+			dir = filepath.Dir(dir)
 		}
 
-		for _, entry := range entries {
-			if entry.IsDir() {
-				continue
-			}
+		// Datasets of single files may share a directory
+		if seen[[2]string{dir, dynasty}] {
+			continue
+		}
+		seen[[2]string{dir, dynasty}] = true
 
-			// Check if file should be excluded
-			if contains(dataset.Excludes, entry.Name()) {
-				continue
-			}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+		}
 
-			if filepath.Ext(entry.Name()) != ".json" {
+		for _, entry := range entries {
+			if entry.IsDir() || !isAuthorFile(entry.Name()) {
 				continue
 			}
 
-			filePath := filepath.Join(fullPath, entry.Name())
-			filePoems, err := l.loadJSONFile(filePath, dataset.Tag)
+			filePath := filepath.Join(dir, entry.Name())
+			fileAuthors, err := loadAuthorFile(filePath)
 			if err != nil {
 				fmt.Printf("Warning: failed to load %s: %v\n", filePath, err)
 				continue
 			}
 
-			for _, poem := range filePoems {
-				poemWithMeta := PoemWithMeta{
-					PoemData:    poem,
-					Dynasty:     dynasty,
-					DatasetName: dataset.Name,
-					DatasetKey:  key,
-				}
+			for _, author := range fileAuthors {
+				author.Dynasty = dynasty
+				author.DatasetKey = key
+				authors = append(authors, author)
+			}
+		}
+	}
 
-				// Set default author if not present in data
-				if poemWithMeta.Author == "" {
-					if defaultAuthor := getDefaultAuthorFromDataset(key); defaultAuthor != "" {
-						poemWithMeta.Author = defaultAuthor
-					}
-				}
+	return authors, nil
+}
+
+// isAuthorFile reports whether name is the name of an author file
+func isAuthorFile(name string) bool {
+	return strings.HasPrefix(name, "author") && filepath.Ext(name) == ".json"
+}
+
+// loadAuthorFile loads the authors with a description from an author file.
+// The description is under desc in 全唐诗, and under description, with a
+// short_description, in 宋词.
+func loadAuthorFile(path string) ([]AuthorData, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
 
-				poems = append(poems, poemWithMeta)
+	var rawAuthors []map[string]any
+	if err := json.Unmarshal(data, &rawAuthors); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	var authors []AuthorData
+	for _, raw := range rawAuthors {
+		author := AuthorData{Name: getString(raw, "name")}
+		for _, field := range []string{"desc", "description", "short_description"} {
+			if author.Description = strings.TrimSpace(getString(raw, field)); author.Description != "" {
+				break
 			}
 		}
-	} else {
-		// Load single file
-		filePoems, err := l.loadJSONFile(fullPath, dataset.Tag)
+
+		if author.Name != "" && author.Description != "" {
+			authors = append(authors, author)
+		}
+	}
+
+	return authors, nil
+}
+
+// datasetFile is a JSON file of poems in a dataset
+type datasetFile struct {
+	path       string // Path on disk
+	name       string // Name of the file
+	sourcePath string // Path relative to the data root, with forward slashes
+	optional   bool   // Skipped with a warning if it fails to load
+}
+
+// datasetFiles lists the poem files of a dataset: the JSON files of its
+// directory but the excluded ones, or its single file
+func (l *JSONLoader) datasetFiles(dataset DatasetInfo) ([]datasetFile, error) {
+	fullPath := filepath.Join(l.basePath, dataset.Path)
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat path %s: %w", fullPath, err)
+	}
+
+	if !info.IsDir() {
+		return []datasetFile{{
+			path:       fullPath,
+			name:       filepath.Base(fullPath),
+			sourcePath: filepath.ToSlash(filepath.Clean(dataset.Path)),
+		}}, nil
+	}
+
+	entries, err := os.ReadDir(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", fullPath, err)
+	}
+
+	var files []datasetFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		// Check if file should be excluded
+		if contains(dataset.Excludes, entry.Name()) {
+			continue
+		}
+
+		if filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		files = append(files, datasetFile{
+			path:       filepath.Join(fullPath, entry.Name()),
+			name:       entry.Name(),
+			sourcePath: filepath.ToSlash(filepath.Join(dataset.Path, entry.Name())),
+			optional:   true,
+		})
+	}
+
+	return files, nil
+}
+
+// loadDataset yields the poems of a dataset one file at a time. Returns
+// errStopped if yield asked to stop.
+func (l *JSONLoader) loadDataset(key string, dataset DatasetInfo, yield func(PoemWithMeta, error) bool) error {
+	dynasty := l.idToDynasty[dataset.ID]
+
+	files, err := l.datasetFiles(dataset)
+	if err != nil {
+		return err
+	}
+
+	// Records without an id that share a content key, by how many came before
+	occurrences := make(map[string]int)
+
+	for _, file := range files {
+		filePoems, indexes, err := l.loadJSONFile(file.path, dataset.Tag)
 		if err != nil {
-			return nil, err
+			if !file.optional {
+				return err
+			}
+			fmt.Printf("Warning: failed to load %s: %v\n", file.path, err)
+			continue
 		}
 
-		for _, poem := range filePoems {
+		for i, poem := range filePoems {
 			poemWithMeta := PoemWithMeta{
 				PoemData:    poem,
 				Dynasty:     dynasty,
 				DatasetName: dataset.Name,
 				DatasetKey:  key,
+				SourceFile:  file.name,
+				SourcePath:  file.sourcePath,
+				SourceIndex: indexes[i],
 			}
 
 			// Set default author if not present in data
@@ -181,26 +365,37 @@ func (l *JSONLoader) loadDataset(key string, dataset DatasetInfo) ([]PoemWithMet
 				}
 			}
 
-			poems = append(poems, poemWithMeta)
+			if poemWithMeta.ID == "" {
+				key := poemWithMeta.contentKey()
+				poemWithMeta.Occurrence = occurrences[key]
+				occurrences[key]++
+			}
+
+			if !yield(poemWithMeta, nil) {
+				return errStopped
+			}
 		}
 	}
 
-	return poems, nil
+	return nil
 }
 
-func (l *JSONLoader) loadJSONFile(path string, tag string) ([]PoemData, error) {
+// loadJSONFile loads the poems of a file, along with the index of the record
+// each was read from. Records without paragraphs are skipped.
+func (l *JSONLoader) loadJSONFile(path string, tag string) ([]PoemData, []int, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
 	var rawPoems []map[string]any
 	if err := json.Unmarshal(data, &rawPoems); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
 	var poems []PoemData
-	for _, raw := range rawPoems {
+	var indexes []int
+	for index, raw := range rawPoems {
 		poem := PoemData{
 			Title:  getString(raw, "title"),
 			Author: getString(raw, "author"),
@@ -247,10 +442,11 @@ func (l *JSONLoader) loadJSONFile(path string, tag string) ([]PoemData, error) {
 
 		if len(poem.Paragraphs) > 0 {
 			poems = append(poems, poem)
+			indexes = append(indexes, index)
 		}
 	}
 
-	return poems, nil
+	return poems, indexes, nil
 }
 
 func inferDynasty(key, name string) string {
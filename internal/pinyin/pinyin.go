@@ -0,0 +1,396 @@
+// Package pinyin annotates Chinese text with Hanyu Pinyin and builds the
+// pinyin search index.
+//
+// Readings come from the go-pinyin dictionary, which lists the most common
+// reading of each character first. Polyphonic characters are handled in two
+// ways: a short table of phrases common in classical poetry overrides the
+// default reading for annotation (重阳 is chóng yáng, not zhòng yáng), and the
+// search index also records the other readings of the polyphonic characters
+// that verse uses most, so a search finds a poem whichever reading the user
+// had in mind.
+package pinyin
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	gopinyin "github.com/mozillazg/go-pinyin"
+)
+
+// Style selects how tones are written in an annotation
+type Style string
+
+const (
+	// StyleMarks writes tones as diacritics: jìng yè sī
+	StyleMarks Style = "marks"
+	// StyleNumbers writes tones as a trailing digit: jing4 ye4 si1. The
+	// neutral tone has no digit.
+	StyleNumbers Style = "numbers"
+)
+
+// IsValid reports whether s is a known style
+func (s Style) IsValid() bool {
+	switch s {
+	case StyleMarks, StyleNumbers:
+		return true
+	}
+	return false
+}
+
+// phrases overrides the default reading of polyphonic characters in phrases
+// common in classical poetry, in both scripts
+var phrases = map[string][]string{
+	"还乡": {"huán", "xiāng"}, "還鄉": {"huán", "xiāng"},
+	"还家": {"huán", "jiā"}, "還家": {"huán", "jiā"},
+	"重阳": {"chóng", "yáng"}, "重陽": {"chóng", "yáng"},
+	"重来": {"chóng", "lái"}, "重來": {"chóng", "lái"},
+	"重逢": {"chóng", "féng"},
+	"重重": {"chóng", "chóng"},
+	"千重": {"qiān", "chóng"},
+	"万重": {"wàn", "chóng"}, "萬重": {"wàn", "chóng"},
+	"乐府": {"yuè", "fǔ"}, "樂府": {"yuè", "fǔ"},
+	"音乐": {"yīn", "yuè"}, "音樂": {"yīn", "yuè"},
+	"管弦乐": {"guǎn", "xián", "yuè"}, "管弦樂": {"guǎn", "xián", "yuè"},
+	"长安": {"cháng", "ān"}, "長安": {"cháng", "ān"},
+	"朝辞": {"zhāo", "cí"}, "朝辭": {"zhāo", "cí"},
+	"朝露": {"zhāo", "lù"},
+	"今朝": {"jīn", "zhāo"},
+	"少年": {"shào", "nián"},
+	"一行": {"yī", "háng"},
+	"长大": {"zhǎng", "dà"}, "長大": {"zhǎng", "dà"},
+}
+
+// maxPhraseRunes is the length of the longest entry in phrases
+const maxPhraseRunes = 3
+
+// polyphones are the polyphonic characters common in verse whose other
+// readings are added to the search index, in both scripts
+var polyphones = map[rune]bool{
+	'长': true, '長': true, '行': true, '还': true, '還': true, '重': true,
+	'乐': true, '樂': true, '朝': true, '少': true, '为': true, '為': true,
+	'将': true, '將': true, '相': true, '间': true, '間': true, '曾': true,
+	'处': true, '處': true, '几': true, '幾': true, '传': true, '傳': true,
+	'转': true, '轉': true, '种': true, '種': true, '调': true, '調': true,
+	'觉': true, '覺': true, '数': true, '數': true, '难': true, '難': true,
+	'兴': true, '興': true, '应': true, '應': true, '更': true, '散': true,
+	'着': true, '著': true, '骑': true, '騎': true, '看': true, '教': true,
+}
+
+// toneMarks maps each tone-marked letter to the bare letter and its tone
+var toneMarks = map[rune]struct {
+	base rune
+	tone int
+}{
+	'ā': {'a', 1}, 'á': {'a', 2}, 'ǎ': {'a', 3}, 'à': {'a', 4},
+	'ē': {'e', 1}, 'é': {'e', 2}, 'ě': {'e', 3}, 'è': {'e', 4},
+	'ī': {'i', 1}, 'í': {'i', 2}, 'ǐ': {'i', 3}, 'ì': {'i', 4},
+	'ō': {'o', 1}, 'ó': {'o', 2}, 'ǒ': {'o', 3}, 'ò': {'o', 4},
+	'ū': {'u', 1}, 'ú': {'u', 2}, 'ǔ': {'u', 3}, 'ù': {'u', 4},
+	'ǖ': {'ü', 1}, 'ǘ': {'ü', 2}, 'ǚ': {'ü', 3}, 'ǜ': {'ü', 4},
+	'ń': {'n', 2}, 'ň': {'n', 3}, 'ǹ': {'n', 4}, 'ḿ': {'m', 2},
+}
+
+// combiningTones maps the combining diacritics a few dictionary readings use
+// (m̄, ê̌) to their tone
+var combiningTones = map[rune]int{'\u0304': 1, '\u0301': 2, '\u030c': 3, '\u0300': 4}
+
+// IsLetter reports whether r can appear in written pinyin: a Latin letter,
+// ü, or a vowel with a tone mark
+func IsLetter(r rune) bool {
+	r = unicode.ToLower(r)
+	if r >= 'a' && r <= 'z' || r == 'ü' {
+		return true
+	}
+	_, marked := toneMarks[r]
+	_, combining := combiningTones[r]
+	return marked || combining
+}
+
+// Normalize reduces written pinyin to the form stored in the search index:
+// lower case letters only, without tones, separators or anything else, and
+// with ü written as v. "Jìng yè sī", "jing4 ye4 si1" and "jingyesi" all
+// normalize to "jingyesi".
+func Normalize(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if mark, ok := toneMarks[r]; ok {
+			r = mark.base
+		}
+		switch {
+		case r == 'ü':
+			b.WriteByte('v')
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Annotate returns the pinyin of text, one syllable per character separated
+// by spaces. Characters without a reading (punctuation, Latin letters) are
+// kept as they are.
+func Annotate(text string, style Style) string {
+	runes := []rune(text)
+	var b strings.Builder
+	var prev rune
+	for i, syllable := range syllables(runes) {
+		if syllable == "" {
+			b.WriteRune(runes[i])
+			prev = runes[i]
+			continue
+		}
+		if b.Len() > 0 && !unicode.IsSpace(prev) && !unicode.In(prev, unicode.Ps, unicode.Pi) {
+			b.WriteByte(' ')
+		}
+		if style == StyleNumbers {
+			syllable = withToneNumber(syllable)
+		}
+		b.WriteString(syllable)
+		prev, _ = utf8.DecodeLastRuneInString(syllable)
+	}
+	return b.String()
+}
+
+// Boundary marks the start of each syllable in the search index, so that a
+// query matches from the start of a syllable rather than inside one (see
+// Spellings). Normalize drops it from queries.
+const Boundary = "'"
+
+// maxSpellings caps the number of ways Spellings splits a query
+const maxSpellings = 16
+
+// Index returns the form of text stored in the pinyin search index: each run
+// of characters with a reading, normalized with Boundary before each syllable,
+// runs separated by spaces so that a match can't span punctuation. A run
+// containing one of the common polyphonic characters is repeated once for
+// each of the character's other readings.
+func Index(text string) string {
+	runes := []rune(text)
+	options := readingOptions(runes)
+
+	var segments []string
+	for i := 0; i < len(runes); {
+		if options[i] == nil {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && options[j] != nil {
+			j++
+		}
+
+		run := make([]string, j-i)
+		for k := range run {
+			run[k] = options[i+k][0]
+		}
+		segments = append(segments, Boundary+strings.Join(run, Boundary))
+		for k := range run {
+			for _, alt := range options[i+k][1:] {
+				swapped := slices.Clone(run)
+				swapped[k] = alt
+				segments = append(segments, Boundary+strings.Join(swapped, Boundary))
+			}
+		}
+		i = j
+	}
+	return strings.Join(segments, " ")
+}
+
+// Spellings returns the forms in which query, which must already be
+// normalized, appears in the search index: each way of splitting it into
+// syllables, the last of which may be incomplete, written with Boundary before
+// every syllable. "xian" is spelled 'xian, 'xi'an or, ending partway through
+// a syllable, 'xia'n (下年), while "huang" can't start inside 'chuang and
+// "ing" has no spelling at all. At most maxSpellings are
+// returned, preferring longer syllables first.
+func Spellings(query string) []string {
+	known := syllablePrefixes()
+	var spellings []string
+	var split func(rest, spelled string)
+	split = func(rest, spelled string) {
+		for n := min(len(rest), maxSyllableLen); n > 0 && len(spellings) < maxSpellings; n-- {
+			whole, ok := known[rest[:n]]
+			switch {
+			case !ok:
+			case n == len(rest):
+				spellings = append(spellings, spelled+Boundary+rest)
+			case whole:
+				split(rest[n:], spelled+Boundary+rest[:n])
+			}
+		}
+	}
+	split(query, "")
+	return spellings
+}
+
+// maxSyllableLen is the length of the longest normalized syllable (zhuang)
+const maxSyllableLen = 6
+
+// syllablePrefixes maps every prefix of a normalized dictionary syllable,
+// whole syllables included, to whether it is a whole syllable. The syllables
+// of interjections without a vowel (m, n, ng, hm) don't count as whole, or
+// they would split almost any query a second way (xia'n, mi'ng).
+var syllablePrefixes = sync.OnceValue(func() map[string]bool {
+	prefixes := make(map[string]bool)
+	for _, entry := range gopinyin.PinyinDict {
+		for _, reading := range strings.Split(entry, ",") {
+			syllable := Normalize(reading)
+			for n := 1; n <= len(syllable); n++ {
+				if !prefixes[syllable[:n]] {
+					prefixes[syllable[:n]] = n == len(syllable) && strings.ContainsAny(syllable, "aeiouv")
+				}
+			}
+		}
+	}
+	return prefixes
+})
+
+// Locate returns the rune ranges [start, end) of text whose pinyin spells
+// query, which must already be normalized. Readings are those Index records,
+// and as with the substring match the index is searched with, query may end
+// partway through its last syllable; it must start at a syllable, though.
+func Locate(text, query string) [][2]int {
+	if query == "" {
+		return nil
+	}
+	options := readingOptions([]rune(text))
+
+	var ranges [][2]int
+	for i := 0; i < len(options); {
+		if end := spell(options, i, query); end > i {
+			ranges = append(ranges, [2]int{i, end})
+			i = end
+			continue
+		}
+		i++
+	}
+	return ranges
+}
+
+// spell returns where the characters from i on, read with any of their
+// options, spell out rest, or -1 if they don't
+func spell(options [][]string, i int, rest string) int {
+	if rest == "" {
+		return i
+	}
+	if i == len(options) {
+		return -1
+	}
+	for _, option := range options[i] {
+		switch {
+		case strings.HasPrefix(rest, option):
+			if end := spell(options, i+1, rest[len(option):]); end >= 0 {
+				return end
+			}
+		case strings.HasPrefix(option, rest):
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// readingOptions returns the normalized readings of each rune: the reading
+// in context first, then the other readings of common polyphonic characters.
+// Runes without a reading get nil.
+func readingOptions(runes []rune) [][]string {
+	options := make([][]string, len(runes))
+	for i, syllable := range syllables(runes) {
+		if syllable == "" {
+			continue
+		}
+		options[i] = []string{Normalize(syllable)}
+		if !polyphones[runes[i]] {
+			continue
+		}
+		for _, reading := range readings(runes[i]) {
+			if alt := Normalize(reading); !slices.Contains(options[i], alt) {
+				options[i] = append(options[i], alt)
+			}
+		}
+	}
+	return options
+}
+
+// syllables returns the tone-marked reading of each rune in context, or ""
+// for runes without one
+func syllables(runes []rune) []string {
+	out := make([]string, len(runes))
+	for i := 0; i < len(runes); {
+		if phrase := phraseAt(runes, i); phrase != nil {
+			copy(out[i:], phrase)
+			i += len(phrase)
+			continue
+		}
+		if rs := readings(runes[i]); len(rs) > 0 {
+			out[i] = rs[0]
+		}
+		i++
+	}
+	return out
+}
+
+// phraseAt returns the readings of the longest entry of phrases starting at
+// runes[i], or nil
+func phraseAt(runes []rune, i int) []string {
+	for n := min(maxPhraseRunes, len(runes)-i); n >= 2; n-- {
+		if readings, ok := phrases[string(runes[i:i+n])]; ok {
+			return readings
+		}
+	}
+	return nil
+}
+
+// readings returns the dictionary readings of r, most common first, or nil
+// if it has none
+func readings(r rune) []string {
+	entry, ok := gopinyin.PinyinDict[int(r)]
+	if !ok || entry == "" {
+		return nil
+	}
+	return strings.Split(entry, ",")
+}
+
+// Tone returns the tone, 1 to 4, of the most common reading of r, or 0 if
+// r has no reading or is read with the neutral tone
+func Tone(r rune) int {
+	rs := readings(r)
+	if len(rs) == 0 {
+		return 0
+	}
+	for _, c := range rs[0] {
+		if mark, ok := toneMarks[c]; ok {
+			return mark.tone
+		}
+		if t, ok := combiningTones[c]; ok {
+			return t
+		}
+	}
+	return 0
+}
+
+// withToneNumber rewrites a tone-marked syllable with the tone as a trailing
+// digit
+func withToneNumber(syllable string) string {
+	var b strings.Builder
+	tone := 0
+	for _, r := range syllable {
+		if mark, ok := toneMarks[r]; ok {
+			b.WriteRune(mark.base)
+			tone = mark.tone
+			continue
+		}
+		if t, ok := combiningTones[r]; ok {
+			tone = t
+			continue
+		}
+		b.WriteRune(r)
+	}
+	if tone > 0 {
+		b.WriteString(strconv.Itoa(tone))
+	}
+	return b.String()
+}
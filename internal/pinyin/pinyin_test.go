@@ -0,0 +1,120 @@
+package pinyin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotate(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		style Style
+		want  string
+	}{
+		{"tone marks", "静夜思", StyleMarks, "jìng yè sī"},
+		{"tone numbers", "静夜思", StyleNumbers, "jing4 ye4 si1"},
+		{"punctuation kept", "床前明月光，疑是地上霜。", StyleMarks, "chuáng qián míng yuè guāng， yí shì dì shàng shuāng。"},
+		{"no space after opening quote", "「静夜思」", StyleMarks, "「jìng yè sī」"},
+		{"traditional characters", "靜夜思", StyleMarks, "jìng yè sī"},
+		{"polyphone in a known phrase", "九月九日重阳", StyleMarks, "jiǔ yuè jiǔ rì chóng yáng"},
+		{"polyphone elsewhere takes its common reading", "重山", StyleMarks, "zhòng shān"},
+		{"ü with tone number", "绿", StyleNumbers, "lü4"},
+		{"no readings", "abc，", StyleMarks, "abc，"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Annotate(tt.text, tt.style))
+		})
+	}
+}
+
+func TestWithToneNumber(t *testing.T) {
+	assert.Equal(t, "zhong4", withToneNumber("zhòng"))
+	assert.Equal(t, "lü4", withToneNumber("lǜ"))
+	assert.Equal(t, "m4", withToneNumber("m\u0300"))
+	assert.Equal(t, "de", withToneNumber("de"))
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "jingyesi", Normalize("Jìng yè sī"))
+	assert.Equal(t, "jingyesi", Normalize("jing4 ye4 si1"))
+	assert.Equal(t, "lvshui", Normalize("lǜ shuǐ"))
+	assert.Equal(t, "xian", Normalize("xi'an"))
+}
+
+func TestTone(t *testing.T) {
+	assert.Equal(t, 4, Tone('静'))
+	assert.Equal(t, 1, Tone('思'))
+	assert.Equal(t, 2, Tone('明'))
+	assert.Equal(t, 3, Tone('李'))
+	assert.Equal(t, 2, Tone('呣'))
+	assert.Equal(t, 0, Tone('，'))
+}
+
+func TestIndex(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"one run per phrase", "床前明月光，疑是地上霜。", "'chuang'qian'ming'yue'guang 'yi'shi'di'shang'shuang"},
+		{"common polyphone adds its other readings", "长安一片月", "'chang'an'yi'pian'yue 'zhang'an'yi'pian'yue"},
+		{"phrase reading comes first", "长大", "'zhang'da 'chang'da"},
+		{"no readings", "abc", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Index(tt.text))
+		})
+	}
+}
+
+func TestSpellings(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"whole syllables", "mingyue", []string{"'ming'yue", "'ming'yu'e"}},
+		{"partial last syllable", "mingyu", []string{"'ming'yu"}},
+		{"every split", "xian", []string{"'xian", "'xia'n", "'xi'an", "'xi'a'n"}},
+		{"can't start inside a syllable", "ing", nil},
+		{"not pinyin", "qqq", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Spellings(tt.query))
+		})
+	}
+
+	// 'huang appears nowhere in the index of 静夜思, though huang is inside
+	// chuang and shuang
+	index := Index("床前明月光，疑是地上霜。")
+	for _, spelling := range Spellings("huang") {
+		assert.NotContains(t, index, spelling)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  [][2]int
+	}{
+		{"whole syllables", "床前明月光", "mingyue", [][2]int{{2, 4}}},
+		{"partial last syllable", "床前明月光", "mingyu", [][2]int{{2, 4}}},
+		{"every occurrence", "明月明月", "ming", [][2]int{{0, 1}, {2, 3}}},
+		{"other reading of a polyphone", "长安", "zhangan", [][2]int{{0, 2}}},
+		{"doesn't span punctuation", "明，月", "mingyue", nil},
+		{"must start at a syllable", "明月", "ingyue", nil},
+		{"empty query", "明月", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Locate(tt.text, tt.query))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/palemoky/chinese-poetry-api/internal/classifier"
+	"github.com/palemoky/chinese-poetry-api/internal/database"
+	"github.com/palemoky/chinese-poetry-api/internal/loader"
+	"github.com/palemoky/chinese-poetry-api/internal/logger"
+)
+
+// ImportAuthors stores the descriptions of authors from the author files of
+// the datasets. An author is matched by name, normalized and converted like
+// the names of poem authors, and by the dynasty of the dataset, so that poets
+// of the same name in other dynasties are left alone. When several records
+// match an author, the first one loaded is kept.
+func (p *Processor) ImportAuthors(authors []loader.AuthorData) error {
+	descriptions := make([]database.AuthorDescription, 0, len(authors))
+	seen := make(map[[2]string]bool, len(authors))
+	for _, author := range authors {
+		name := classifier.NormalizeText(author.Name)
+		description := strings.TrimSpace(author.Description)
+		if name == "" || description == "" {
+			continue
+		}
+
+		var err error
+		if name, err = p.convertText(name, p.convertToTraditional); err != nil {
+			return fmt.Errorf("failed to convert author name: %w", err)
+		}
+		dynasty, err := p.convertText(author.Dynasty, p.convertToTraditional)
+		if err != nil {
+			return fmt.Errorf("failed to convert dynasty name: %w", err)
+		}
+
+		key := [2]string{name, dynasty}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if description, err = p.convertText(description, p.convertToTraditional); err != nil {
+			return fmt.Errorf("failed to convert author description: %w", err)
+		}
+		descriptions = append(descriptions, database.AuthorDescription{
+			Name:        name,
+			Dynasty:     dynasty,
+			Description: description,
+		})
+	}
+
+	updated, err := p.repo.UpdateAuthorDescriptions(descriptions)
+	if err != nil {
+		return fmt.Errorf("failed to update author descriptions: %w", err)
+	}
+
+	logger.Info("Author descriptions imported",
+		zap.Int("records", len(authors)),
+		zap.Int64("authors", updated),
+	)
+	return nil
+}
@@ -0,0 +1,229 @@
+package processor
+
+import (
+	"cmp"
+	"hash/fnv"
+	"slices"
+	"unicode"
+)
+
+// Near-duplicate detection compares poems by the character shingles of their
+// content. The Jaccard similarity of two poems' shingles is estimated from
+// MinHash signatures, and only poems whose signatures agree on a whole band
+// (locality-sensitive hashing) are compared at all.
+const (
+	shingleSize = 2  // Characters per shingle
+	minHashes   = 64 // Hash functions per MinHash signature
+	lshBands    = 16 // Bands of minHashes/lshBands rows each
+
+	// Estimated similarity from which two poems are versions of each other.
+	// A 五言绝句 with one character changed keeps a similarity of about 0.8,
+	// with two about 0.65, while two poems sharing a couplet are near 0.3.
+	nearDuplicateSimilarity = 0.5
+
+	// Poems sharing a band key are each compared with at most this many of
+	// the poems before them, so a crowded band costs linear time, not
+	// quadratic; versions of one poem are rarely more than a few
+	bucketWindow = 64
+	// Most versions one cluster takes. Joining means being similar to every
+	// version already in it, which gets slower as the cluster grows.
+	maxClusterSize = 32
+)
+
+// signature is the MinHash signature of a poem's shingles
+type signature [minHashes]uint32
+
+// minHashSeeds seed the hash functions of a signature
+var minHashSeeds = func() [minHashes]uint64 {
+	var seeds [minHashes]uint64
+	state := uint64(0x9e3779b97f4a7c15)
+	for i := range seeds {
+		state = mix64(state)
+		seeds[i] = state
+	}
+	return seeds
+}()
+
+// mix64 is the finalizer of splitmix64, turning x into a well-mixed hash
+func mix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// shingleHashes returns the hashes of the shingles of text, ignoring
+// punctuation and whitespace. Text shorter than a shingle is one shingle.
+func shingleHashes(text string) []uint64 {
+	chars := []rune(text)
+	chars = slices.DeleteFunc(chars, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSpace(r)
+	})
+	if len(chars) == 0 {
+		return nil
+	}
+
+	n := max(len(chars)-shingleSize+1, 1)
+	hashes := make([]uint64, 0, n)
+	for i := range n {
+		h := fnv.New64a()
+		h.Write([]byte(string(chars[i:min(i+shingleSize, len(chars))])))
+		hashes = append(hashes, h.Sum64())
+	}
+	return hashes
+}
+
+// minHash returns the signature of a set of shingle hashes
+func minHash(hashes []uint64) signature {
+	var sig signature
+	for i := range sig {
+		sig[i] = ^uint32(0)
+	}
+	for _, h := range hashes {
+		for i, seed := range minHashSeeds {
+			if v := uint32(mix64(h^seed) >> 32); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+// similarity estimates the Jaccard similarity of the shingles behind two
+// signatures: the share of hash functions they agree on
+func (s *signature) similarity(other *signature) float64 {
+	same := 0
+	for i := range s {
+		if s[i] == other[i] {
+			same++
+		}
+	}
+	return float64(same) / minHashes
+}
+
+// bandKey hashes band b of a signature, with b itself so bands don't collide
+func (s *signature) bandKey(b int) uint64 {
+	const rows = minHashes / lshBands
+	key := mix64(uint64(b))
+	for _, v := range s[b*rows : (b+1)*rows] {
+		key = mix64(key ^ uint64(v))
+	}
+	return key
+}
+
+// clusterer finds near duplicates among poems fed to it one at a time,
+// keeping only their IDs and signatures
+type clusterer struct {
+	ids  []int64
+	sigs []signature
+}
+
+// add adds a poem by its ID and folded content. Poems without shingles, which
+// have nothing to compare, are left out.
+func (c *clusterer) add(id int64, contentFolded string) {
+	hashes := shingleHashes(contentFolded)
+	if len(hashes) == 0 {
+		return
+	}
+	c.ids = append(c.ids, id)
+	c.sigs = append(c.sigs, minHash(hashes))
+}
+
+// clusters returns the cluster ID of each poem with near duplicates: the
+// lowest ID in its cluster. Poems are near duplicates when their contents
+// share most shingles, whatever their titles, so copies differing in a few
+// characters end up together. A poem only joins a cluster when it is a near
+// duplicate of every poem already in it, so similarity doesn't chain: with A
+// close to B and B close to C, A and C share a cluster only if they are close
+// to each other as well.
+func (c *clusterer) clusters() map[int64]int64 {
+	// Union-find over poem indexes, with the members of each cluster of more
+	// than one poem by its root. Clusters only grow by single poems, never by
+	// merging, so every root is a poem of its own cluster.
+	parent := make([]int32, len(c.ids))
+	for i := range parent {
+		parent[i] = int32(i)
+	}
+	find := func(i int32) int32 {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	members := make(map[int32][]int32)
+	join := func(i, j int32) {
+		ri, rj := find(i), find(j)
+		if ri == rj {
+			return
+		}
+		// The poem on its own joins the other's cluster
+		if _, clustered := members[ri]; clustered {
+			ri, rj = rj, ri
+		}
+		if _, clustered := members[ri]; clustered {
+			return
+		}
+		cluster, ok := members[rj]
+		if !ok {
+			cluster = []int32{rj}
+		}
+		if len(cluster) >= maxClusterSize {
+			return
+		}
+		for _, m := range cluster {
+			if c.sigs[ri].similarity(&c.sigs[m]) < nearDuplicateSimilarity {
+				return
+			}
+		}
+		parent[ri] = rj
+		members[rj] = append(cluster, ri)
+	}
+
+	// Poems agreeing on a band are compared, one band at a time, by sorting
+	// the poems on their key for it
+	type bandKey struct {
+		key   uint64
+		index int32
+	}
+	keys := make([]bandKey, len(c.ids))
+	for b := range lshBands {
+		for i := range c.sigs {
+			keys[i] = bandKey{c.sigs[i].bandKey(b), int32(i)}
+		}
+		slices.SortFunc(keys, func(x, y bandKey) int {
+			return cmp.Or(cmp.Compare(x.key, y.key), cmp.Compare(x.index, y.index))
+		})
+
+		for start := 0; start < len(keys); {
+			end := start + 1
+			for end < len(keys) && keys[end].key == keys[start].key {
+				end++
+			}
+			bucket := keys[start:end]
+			for k := 1; k < len(bucket); k++ {
+				for _, l := range bucket[max(k-bucketWindow, 0):k] {
+					join(bucket[k].index, l.index)
+				}
+			}
+			start = end
+		}
+	}
+
+	// The lowest ID of each cluster, by root, then the cluster of each poem
+	// whose cluster has another
+	lowest := make(map[int32]int64)
+	for root, cluster := range members {
+		lowest[root] = c.ids[root]
+		for _, m := range cluster {
+			lowest[root] = min(lowest[root], c.ids[m])
+		}
+	}
+	clusterIDs := make(map[int64]int64)
+	for i, id := range c.ids {
+		if low, ok := lowest[find(int32(i))]; ok {
+			clusterIDs[id] = low
+		}
+	}
+	return clusterIDs
+}
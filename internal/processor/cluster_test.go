@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterer(t *testing.T) {
+	var c clusterer
+	c.add(3, "床前看月光，疑是地上霜。举头望山月，低头思故乡。")
+	c.add(1, "床前明月光，疑是地上霜。举头望明月，低头思故乡。")
+	c.add(2, "春眠不觉晓，处处闻啼鸟。夜来风雨声，花落知多少。")
+	c.add(4, "白日依山尽，黄河入海流。欲穷千里目，更上一层楼。")
+	c.add(5, "白日依山尽，黄河入海流。欲穷千里目，更上一层楼。")
+	c.add(7, "。")
+
+	assert.Equal(t, map[int64]int64{1: 1, 3: 1, 4: 4, 5: 4}, c.clusters())
+	assert.Len(t, c.ids, 5)
+}
+
+func TestClustererDoesNotChain(t *testing.T) {
+	// a and c each differ from b in three characters, and from each other in six
+	a := "窗前皓月光，疑是地上雪。举头望明月，低头思故乡。"
+	b := "床前明月光，疑是地上霜。举头望明月，低头思故乡。"
+	cc := "床前明月光，疑是地上霜。举头望明月，低首思家园。"
+	sigA, sigB, sigC := minHash(shingleHashes(a)), minHash(shingleHashes(b)), minHash(shingleHashes(cc))
+	require.GreaterOrEqual(t, sigA.similarity(&sigB), nearDuplicateSimilarity)
+	require.GreaterOrEqual(t, sigB.similarity(&sigC), nearDuplicateSimilarity)
+	require.Less(t, sigA.similarity(&sigC), nearDuplicateSimilarity)
+
+	var c clusterer
+	c.add(1, a)
+	c.add(2, b)
+	c.add(3, cc)
+	clusterIDs := c.clusters()
+
+	// b is clustered with a or c, but not with both
+	assert.Contains(t, clusterIDs, int64(2))
+	assert.Len(t, clusterIDs, 2)
+}
+
+func TestSignatureSimilarity(t *testing.T) {
+	original := minHash(shingleHashes("床前明月光，疑是地上霜。举头望明月，低头思故乡。"))
+	copied := minHash(shingleHashes("床前明月光疑是地上霜举头望明月低头思故乡"))
+	changed := minHash(shingleHashes("窗前明月光，疑是地上霜。举头望明月，低头思故乡。"))
+	other := minHash(shingleHashes("白日依山尽，黄河入海流。欲穷千里目，更上一层楼。"))
+
+	assert.Equal(t, 1.0, original.similarity(&copied))
+	assert.GreaterOrEqual(t, original.similarity(&changed), nearDuplicateSimilarity)
+	assert.Less(t, original.similarity(&other), 0.2)
+}
+
+func TestShingleHashes(t *testing.T) {
+	require.Len(t, shingleHashes("床前明月光"), 4)
+	assert.Len(t, shingleHashes("月"), 1)
+	assert.Empty(t, shingleHashes("，。"))
+	assert.Equal(t, shingleHashes("明月光"), shingleHashes("明 月，光"))
+}
@@ -0,0 +1,43 @@
+package processor
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/palemoky/chinese-poetry-api/internal/database"
+)
+
+// sourceID derives a poem ID from the source key of its record
+func sourceID(key string) int64 {
+	hash := sha256.Sum256([]byte(key))
+	return int64(binary.BigEndian.Uint64(hash[:8]) >> (64 - database.PoemIDBits))
+}
+
+// idAllocator numbers poems by their source keys as they stream in, so that
+// a poem gets the same ID in every build and in both language variants.
+// Should the ID of a key be 0, taken, or given to an earlier poem, the poem
+// gets the next free ID; poems are loaded in a fixed order, so which poem that
+// is depends only on the source data. Only the IDs given are kept, about 8MB
+// for the 400,000 poems of the whole corpus.
+type idAllocator struct {
+	taken map[int64]bool
+}
+
+// newIDAllocator returns an allocator that never gives the IDs in taken,
+// which it adds the IDs it gives to
+func newIDAllocator(taken map[int64]bool) *idAllocator {
+	if taken == nil {
+		taken = make(map[int64]bool)
+	}
+	return &idAllocator{taken: taken}
+}
+
+// next returns the ID of the poem with the given source key
+func (a *idAllocator) next(key string) int64 {
+	id := sourceID(key)
+	for id == 0 || a.taken[id] {
+		id = (id + 1) & (1<<database.PoemIDBits - 1)
+	}
+	a.taken[id] = true
+	return id
+}
@@ -0,0 +1,93 @@
+package processor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/palemoky/chinese-poetry-api/internal/loader"
+)
+
+func TestIDAllocator(t *testing.T) {
+	poems := []loader.PoemWithMeta{
+		{PoemData: loader.PoemData{ID: "a", Title: "静夜思"}, DatasetKey: "tang"},
+		{PoemData: loader.PoemData{ID: "b", Title: "春晓"}, DatasetKey: "tang"},
+		{PoemData: loader.PoemData{Title: "水调歌头"}, DatasetKey: "song-ci", SourceFile: "ci.song.0.json", SourceIndex: 3},
+	}
+
+	ids := newIDAllocator(nil)
+	given := make(map[string]int64)
+	for _, poem := range poems {
+		id := ids.next(poem.SourceKey())
+		assert.Equal(t, sourceID(poem.SourceKey()), id)
+		assert.Greater(t, id, int64(0))
+		assert.Less(t, id, int64(1)<<53)
+		given[poem.SourceKey()] = id
+	}
+
+	// The same poems in another order, or along with others, keep their IDs
+	ids = newIDAllocator(nil)
+	ids.next(loader.PoemWithMeta{PoemData: loader.PoemData{ID: "c"}, DatasetKey: "tang"}.SourceKey())
+	for _, i := range []int{2, 0, 1} {
+		key := poems[i].SourceKey()
+		assert.Equal(t, given[key], ids.next(key), key)
+	}
+}
+
+func TestIDAllocatorTaken(t *testing.T) {
+	key := loader.PoemWithMeta{PoemData: loader.PoemData{ID: "a"}, DatasetKey: "tang"}.SourceKey()
+	id := sourceID(key)
+
+	taken := map[int64]bool{id: true}
+	assert.Equal(t, id+1, newIDAllocator(taken).next(key))
+	assert.True(t, taken[id+1])
+
+	// A key listed twice moves to the next ID the second time
+	ids := newIDAllocator(nil)
+	assert.Equal(t, []int64{id, id + 1}, []int64{ids.next(key), ids.next(key)})
+}
+
+func TestIDsWithoutUpstreamIDs(t *testing.T) {
+	type record struct {
+		Author     string   `json:"author"`
+		Rhythmic   string   `json:"rhythmic"`
+		Paragraphs []string `json:"paragraphs"`
+	}
+	// The IDs of a dataset whose records have no id, by first line
+	load := func(records ...record) map[string][]int64 {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "loader"), 0o755))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "ci"), 0o755))
+		config := `{"cp_path": "./", "datasets": {"songci": {"name": "宋词", "id": 1, "path": "ci", "tag": "paragraphs"}}}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "loader", "datas.json"), []byte(config), 0o644))
+		data, err := json.Marshal(records)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "ci", "ci.song.0.json"), data, 0o644))
+
+		l, err := loader.NewJSONLoader(filepath.Join(dir, "loader", "datas.json"))
+		require.NoError(t, err)
+		given := make(map[string][]int64)
+		ids := newIDAllocator(nil)
+		for poem, err := range l.Poems() {
+			require.NoError(t, err)
+			given[poem.Paragraphs[0]] = append(given[poem.Paragraphs[0]], ids.next(poem.SourceKey()))
+		}
+		return given
+	}
+
+	shuidiao := record{"苏轼", "水调歌头", []string{"明月几时有？把酒问青天。"}}
+	niannujiao := record{"苏轼", "念奴娇", []string{"大江东去，浪淘尽，千古风流人物。"}}
+	before := load(shuidiao, niannujiao, niannujiao)
+	require.Len(t, before[niannujiao.Paragraphs[0]], 2)
+	assert.NotEqual(t, before[niannujiao.Paragraphs[0]][0], before[niannujiao.Paragraphs[0]][1])
+
+	// A record added at the front of the file moves the others down
+	after := load(record{"晏殊", "浣溪沙", []string{"一曲新词酒一杯，去年天气旧亭台。"}}, shuidiao, niannujiao, niannujiao)
+	for line, ids := range before {
+		assert.Equal(t, ids, after[line], line)
+	}
+}
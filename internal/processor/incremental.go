@@ -0,0 +1,313 @@
+package processor
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"github.com/palemoky/chinese-poetry-api/internal/database"
+	"github.com/palemoky/chinese-poetry-api/internal/loader"
+	"github.com/palemoky/chinese-poetry-api/internal/logger"
+)
+
+// UpdatePlan lists what an incremental update changes in an existing database
+// to bring it in line with the source data
+type UpdatePlan struct {
+	Work       []PoemWork            // Poems to save: added ones under new IDs, the others under their own
+	Removed    []int64               // Poems to delete, as their source record is gone
+	Moved      []database.PoemSource // Unchanged poems whose source record moved, with its new position
+	Duplicates []string              // Source keys of skipped duplicates whose record is gone
+
+	Added   int
+	Changed int
+	Deleted int
+}
+
+// PlanUpdate compares the source poems with the poems stored by repos, one per
+// language variant of the same database, by source key. Poems whose source
+// record is unchanged are left alone, only taking the new position of the
+// record when records added or removed before it moved it. Changed poems keep
+// their ID, and added poems are numbered by their source keys like in a full
+// build, skipping the IDs in use, so existing IDs never move. Only the added
+// and changed poems are kept as the poems stream by.
+//
+// Source records that were skipped as exact duplicates of a stored poem are
+// left alone as well, unless they changed or the poem they duplicate changed
+// or went away. A record some variant is missing, as when an update failed
+// part way, is planned again, so that running the update again completes it.
+func PlanUpdate(poems iter.Seq2[loader.PoemWithMeta, error], repos ...database.RepositoryInterface) (*UpdatePlan, error) {
+	variants := make([][]database.PoemSource, len(repos))
+	for i, repo := range repos {
+		sources, err := repo.ListPoemSources()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list poem sources: %w", err)
+		}
+		variants[i] = sources
+	}
+	return planUpdate(poems, variants...)
+}
+
+// planUpdate plans an update from the poem sources stored by each language
+// variant, see PlanUpdate
+func planUpdate(poems iter.Seq2[loader.PoemWithMeta, error], variants ...[]database.PoemSource) (*UpdatePlan, error) {
+	// The variants store the same source under the same ID, or skip it as a
+	// duplicate, so a record is up to date when every variant has it with
+	// the hash of the source
+	type storedRecord struct {
+		id          int64 // 0 if every variant skipped it
+		hash        string
+		stale       bool   // The variants disagree on its hash
+		path        string // Position of the record when stored
+		index       int
+		placed      bool    // A variant stored the poem, giving path and index
+		moved       bool    // The variants disagree on its position
+		variants    uint64  // Bit set of the variants that have it
+		duplicateOf []int64 // Poems it was skipped for
+		seen        bool    // Still in the source
+	}
+	byKey := make(map[string]*storedRecord)
+	taken := make(map[int64]bool)
+	for v, sources := range variants {
+		for _, source := range sources {
+			if source.ID != 0 {
+				taken[source.ID] = true
+			}
+			if source.SourceKey == "" {
+				continue
+			}
+			r, ok := byKey[source.SourceKey]
+			if !ok {
+				r = &storedRecord{hash: source.SourceHash}
+				byKey[source.SourceKey] = r
+			}
+			r.stale = r.stale || r.hash != source.SourceHash
+			r.variants |= 1 << v
+			if source.ID != 0 {
+				r.id = source.ID
+				if !r.placed {
+					r.path, r.index, r.placed = source.SourcePath, source.SourceIndex, true
+				}
+				r.moved = r.moved || r.path != source.SourcePath || r.index != source.SourceIndex
+			} else {
+				r.duplicateOf = append(r.duplicateOf, source.DuplicateOf)
+			}
+		}
+	}
+	allVariants := uint64(1)<<len(variants) - 1
+
+	// The IDs of removed poems stay taken too, so that links to them don't
+	// lead to another poem
+	stored := maps.Clone(taken)
+	ids := newIDAllocator(taken)
+
+	plan := &UpdatePlan{}
+	work := func(poem loader.PoemWithMeta, id int64) {
+		if id == 0 {
+			id = ids.next(poem.SourceKey())
+			plan.Added++
+		} else {
+			plan.Changed++
+		}
+		plan.Work = append(plan.Work, PoemWork{PoemWithMeta: poem, ID: id})
+	}
+
+	// Up to date records that were skipped as duplicates, in case the poem
+	// they duplicate is saved again or deleted
+	var skipped []loader.PoemWithMeta
+	added := make(map[string]bool)
+	for poem, err := range poems {
+		if err != nil {
+			return nil, fmt.Errorf("failed to load poems: %w", err)
+		}
+
+		// A record listed twice by the source is loaded once
+		key := poem.SourceKey()
+		r, ok := byKey[key]
+		switch {
+		case !ok:
+			if added[key] {
+				continue
+			}
+			added[key] = true
+			work(poem, 0)
+		case r.seen:
+			continue
+		case r.stale || r.hash != poem.SourceHash() || r.variants != allVariants:
+			r.seen = true
+			work(poem, r.id)
+		default:
+			r.seen = true
+			if r.placed && (r.moved || r.path != poem.SourcePath || r.index != poem.SourceIndex) {
+				plan.Moved = append(plan.Moved, database.PoemSource{ID: r.id, SourcePath: poem.SourcePath, SourceIndex: poem.SourceIndex})
+			}
+			if len(r.duplicateOf) > 0 {
+				skipped = append(skipped, poem)
+			}
+		}
+	}
+
+	// Stored poems and skipped records whose source record is gone
+	gone := make(map[int64]bool)
+	for _, sources := range variants {
+		for _, source := range sources {
+			if r, ok := byKey[source.SourceKey]; ok && r.seen {
+				continue
+			}
+			switch {
+			case source.ID == 0:
+				plan.Duplicates = append(plan.Duplicates, source.SourceKey)
+			case !gone[source.ID]:
+				gone[source.ID] = true
+				plan.Removed = append(plan.Removed, source.ID)
+				plan.Deleted++
+			}
+		}
+	}
+	slices.Sort(plan.Duplicates)
+	plan.Duplicates = slices.Compact(plan.Duplicates)
+
+	// Skipped records may no longer be duplicates once the poem they
+	// duplicate is saved again or deleted, so they are tried again after it
+	redone := maps.Clone(gone)
+	for _, w := range plan.Work {
+		redone[w.ID] = true
+	}
+	for _, poem := range skipped {
+		r := byKey[poem.SourceKey()]
+		if slices.ContainsFunc(r.duplicateOf, func(id int64) bool { return redone[id] || !stored[id] }) {
+			work(poem, r.id)
+		}
+	}
+
+	return plan, nil
+}
+
+// Update applies plan to the database: it deletes the removed poems, saves
+// the added and changed ones in place, and clusters near duplicates again
+// over all poems. The FTS triggers keep the search index in sync.
+//
+// No poem is deleted to be inserted again, so each step leaves the database
+// whole, and an update that fails part way is completed by running it again:
+// the poems already saved are found up to date, and the others planned again.
+// A changed poem that would now duplicate another stored poem keeps its
+// stored version and is reported, rather than being dropped with its ID.
+func (p *Processor) Update(plan *UpdatePlan) error {
+	logger.Info("Updating poems",
+		zap.Int("added", plan.Added),
+		zap.Int("changed", plan.Changed),
+		zap.Int("deleted", plan.Deleted),
+		zap.Int("moved", len(plan.Moved)),
+	)
+
+	if err := p.repo.DeletePoems(plan.Removed); err != nil {
+		return fmt.Errorf("failed to delete poems: %w", err)
+	}
+	if err := p.repo.DeleteDuplicates(plan.Duplicates); err != nil {
+		return fmt.Errorf("failed to delete duplicates: %w", err)
+	}
+	if err := p.repo.UpdateSourcePositions(plan.Moved); err != nil {
+		return fmt.Errorf("failed to update source positions: %w", err)
+	}
+
+	if len(plan.Work) > 0 {
+		if err := p.process(workSeq(plan.Work), p.saveBatch); err != nil {
+			return err
+		}
+	}
+
+	// Only now, since changed poems may have moved to another author
+	authors, err := p.repo.DeleteUnusedAuthors()
+	if err != nil {
+		return fmt.Errorf("failed to delete unused authors: %w", err)
+	}
+	logger.Info("Unused authors deleted", zap.Int64("authors", authors))
+
+	return p.reclusterStored()
+}
+
+// saveBatch saves a batch of added and changed poems, reporting the ones that
+// duplicate another stored poem
+func (p *Processor) saveBatch(batch []*database.Poem) error {
+	unsaved, err := p.repo.SavePoems(batch)
+	if err != nil {
+		return err
+	}
+	for _, poem := range unsaved {
+		if poem.ID != 0 {
+			logger.Warn("Changed poem duplicates another poem, keeping its previous version",
+				zap.Int64("id", poem.ID),
+				zap.String("source_key", poem.SourceKey),
+				zap.Int64("duplicate_of", poem.DuplicateOf),
+			)
+			continue
+		}
+		logger.Info("Poem skipped as a duplicate",
+			zap.String("source_key", poem.SourceKey),
+			zap.Int64("duplicate_of", poem.DuplicateOf),
+		)
+	}
+	return nil
+}
+
+// clusterPageSize is the number of poems read at a time for clustering
+const clusterPageSize = 10000
+
+// reclusterStored clusters near duplicates over all stored poems, as added
+// and changed poems may join or split clusters, and saves the clusters that
+// changed. Poems are read a page at a time, keeping only their IDs and
+// signatures, but those of every poem: some 270 bytes a poem, or 110MB for
+// the 400,000 poems of the whole corpus, which makes clustering the peak of a
+// build's memory.
+func (p *Processor) reclusterStored() error {
+	var c clusterer
+	previous := make(map[int64]int64) // Cluster of each poem that was in one
+	for afterID := int64(0); ; {
+		poems, err := p.repo.ListPoemTexts(afterID, clusterPageSize)
+		if err != nil {
+			return fmt.Errorf("failed to list poems: %w", err)
+		}
+		for _, poem := range poems {
+			c.add(poem.ID, poem.ContentFolded)
+			if poem.ClusterID != nil {
+				previous[poem.ID] = *poem.ClusterID
+			}
+		}
+		if len(poems) < clusterPageSize {
+			break
+		}
+		afterID = poems[len(poems)-1].ID
+	}
+
+	clusterIDs := c.clusters()
+	var changed []*database.Poem
+	for id, clusterID := range clusterIDs {
+		if before, ok := previous[id]; !ok || before != clusterID {
+			changed = append(changed, &database.Poem{ID: id, ClusterID: &clusterID})
+		}
+	}
+	for id := range previous {
+		if _, ok := clusterIDs[id]; !ok {
+			changed = append(changed, &database.Poem{ID: id})
+		}
+	}
+	slices.SortFunc(changed, func(a, b *database.Poem) int { return cmp.Compare(a.ID, b.ID) })
+	if err := p.repo.UpdateClusterIDs(changed); err != nil {
+		return fmt.Errorf("failed to update clusters: %w", err)
+	}
+
+	clusters := 0
+	for id, clusterID := range clusterIDs {
+		if id == clusterID {
+			clusters++
+		}
+	}
+	logger.Info("Near duplicates clustered",
+		zap.Int("clusters", clusters),
+		zap.Int("updated", len(changed)),
+	)
+	return nil
+}
@@ -0,0 +1,202 @@
+package processor
+
+import (
+	"iter"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/palemoky/chinese-poetry-api/internal/database"
+	"github.com/palemoky/chinese-poetry-api/internal/loader"
+)
+
+func TestPlanUpdate(t *testing.T) {
+	poem := func(id, title string) loader.PoemWithMeta {
+		return loader.PoemWithMeta{
+			PoemData:   loader.PoemData{ID: id, Title: title, Paragraphs: []string{"床前明月光"}},
+			Dynasty:    "唐",
+			DatasetKey: "tang",
+		}
+	}
+	kept, changed, added := poem("a", "静夜思"), poem("b", "春晓"), poem("c", "登鹳雀楼")
+	hans := []database.PoemSource{
+		{ID: 1, SourceKey: kept.SourceKey(), SourceHash: kept.SourceHash()},
+		{ID: 2, SourceKey: changed.SourceKey(), SourceHash: "old"},
+		{ID: 5, SourceKey: "tang:gone", SourceHash: "gone"},
+	}
+	// The same poems in the other language variant
+	hant := []database.PoemSource{
+		{ID: 1, SourceKey: kept.SourceKey(), SourceHash: kept.SourceHash()},
+		{ID: 5, SourceKey: "tang:gone", SourceHash: "gone"},
+	}
+
+	plan, err := planUpdate(poemSeq(kept, changed, added, added), hans, hant)
+	require.NoError(t, err)
+
+	assert.Equal(t, []PoemWork{{PoemWithMeta: changed, ID: 2}, {PoemWithMeta: added, ID: sourceID(added.SourceKey())}}, plan.Work)
+	assert.Equal(t, []int64{5}, plan.Removed)
+	assert.Empty(t, plan.Duplicates)
+	assert.Equal(t, 1, plan.Added)
+	assert.Equal(t, 1, plan.Changed)
+	assert.Equal(t, 1, plan.Deleted)
+}
+
+func TestPlanUpdateDuplicates(t *testing.T) {
+	poem := func(id, content string) loader.PoemWithMeta {
+		return loader.PoemWithMeta{
+			PoemData:   loader.PoemData{ID: id, Title: "静夜思", Paragraphs: []string{content}},
+			DatasetKey: "tang",
+		}
+	}
+	original, skipped, orphaned, partial := poem("a", "床前明月光"), poem("b", "床前明月光"), poem("c", "疑是地上霜"), poem("d", "举头望明月")
+	hans := []database.PoemSource{
+		{ID: 1, SourceKey: original.SourceKey(), SourceHash: original.SourceHash()},
+		{ID: 4, SourceKey: partial.SourceKey(), SourceHash: partial.SourceHash()},
+		{ID: 7, SourceKey: "tang:gone", SourceHash: "gone"},
+		{SourceKey: skipped.SourceKey(), SourceHash: skipped.SourceHash(), DuplicateOf: 1},
+		{SourceKey: orphaned.SourceKey(), SourceHash: orphaned.SourceHash(), DuplicateOf: 7},
+		{SourceKey: "tang:old", SourceHash: "old", DuplicateOf: 1},
+	}
+	// An update stopped before saving partial in the other variant
+	hant := []database.PoemSource{
+		{ID: 1, SourceKey: original.SourceKey(), SourceHash: original.SourceHash()},
+		{ID: 7, SourceKey: "tang:gone", SourceHash: "gone"},
+		{SourceKey: skipped.SourceKey(), SourceHash: skipped.SourceHash(), DuplicateOf: 1},
+		{SourceKey: orphaned.SourceKey(), SourceHash: orphaned.SourceHash(), DuplicateOf: 7},
+	}
+
+	plan, err := planUpdate(poemSeq(original, skipped, orphaned, partial), hans, hant)
+	require.NoError(t, err)
+
+	// The record skipped for a poem now gone is tried again after the rest
+	assert.Equal(t, []PoemWork{{PoemWithMeta: partial, ID: 4}, {PoemWithMeta: orphaned, ID: sourceID(orphaned.SourceKey())}}, plan.Work)
+	assert.Equal(t, []int64{7}, plan.Removed)
+	assert.Equal(t, []string{"tang:old"}, plan.Duplicates)
+	assert.Equal(t, 1, plan.Added)
+	assert.Equal(t, 1, plan.Changed)
+	assert.Equal(t, 1, plan.Deleted)
+}
+
+func TestPlanUpdateUnchanged(t *testing.T) {
+	poems := []loader.PoemWithMeta{
+		{PoemData: loader.PoemData{Title: "无题", Paragraphs: []string{"相见时难别亦难"}}, DatasetKey: "tang", SourceFile: "poet.tang.0.json"},
+		{PoemData: loader.PoemData{Title: "无题", Paragraphs: []string{"来是空言去绝踪"}}, DatasetKey: "tang", SourceFile: "poet.tang.0.json", SourceIndex: 1},
+	}
+	assert.NotEqual(t, poems[0].SourceKey(), poems[1].SourceKey())
+
+	stored := []database.PoemSource{
+		{ID: 1, SourceKey: poems[0].SourceKey(), SourceHash: poems[0].SourceHash()},
+		{ID: 2, SourceKey: poems[1].SourceKey(), SourceHash: poems[1].SourceHash()},
+	}
+	plan, err := planUpdate(poemSeq(poems...), stored)
+	require.NoError(t, err)
+	assert.Empty(t, plan.Work)
+	assert.Empty(t, plan.Removed)
+}
+
+func TestPlanUpdateMoved(t *testing.T) {
+	poems := []loader.PoemWithMeta{
+		{PoemData: loader.PoemData{ID: "a", Title: "春晓"}, DatasetKey: "tang", SourcePath: "tang/poet.tang.0.json"},
+		{PoemData: loader.PoemData{ID: "b", Title: "静夜思"}, DatasetKey: "tang", SourcePath: "tang/poet.tang.0.json", SourceIndex: 1},
+		{PoemData: loader.PoemData{ID: "c", Title: "登鹳雀楼"}, DatasetKey: "tang", SourcePath: "tang/poet.tang.0.json", SourceIndex: 2},
+	}
+	// Before a is inserted upstream; the other variant also missed moving c
+	hans := []database.PoemSource{
+		{ID: 2, SourceKey: poems[1].SourceKey(), SourceHash: poems[1].SourceHash(), SourcePath: "tang/poet.tang.0.json"},
+		{ID: 3, SourceKey: poems[2].SourceKey(), SourceHash: poems[2].SourceHash(), SourcePath: "tang/poet.tang.0.json", SourceIndex: 2},
+	}
+	hant := []database.PoemSource{
+		{ID: 2, SourceKey: poems[1].SourceKey(), SourceHash: poems[1].SourceHash(), SourcePath: "tang/poet.tang.0.json"},
+		{ID: 3, SourceKey: poems[2].SourceKey(), SourceHash: poems[2].SourceHash(), SourcePath: "tang/poet.tang.0.json", SourceIndex: 1},
+	}
+
+	plan, err := planUpdate(poemSeq(poems...), hans, hant)
+	require.NoError(t, err)
+
+	// Moved poems keep their stored version, only taking the new position
+	assert.Equal(t, []PoemWork{{PoemWithMeta: poems[0], ID: sourceID(poems[0].SourceKey())}}, plan.Work)
+	assert.Equal(t, []database.PoemSource{
+		{ID: 2, SourcePath: "tang/poet.tang.0.json", SourceIndex: 1},
+		{ID: 3, SourcePath: "tang/poet.tang.0.json", SourceIndex: 2},
+	}, plan.Moved)
+	assert.Equal(t, 1, plan.Added)
+	assert.Zero(t, plan.Changed)
+}
+
+func TestUpdate(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "poetry.db"), 1, 1)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	require.NoError(t, db.Migrate())
+	hans := database.NewRepositoryWithLang(db, database.LangHans)
+	hant := database.NewRepositoryWithLang(db, database.LangHant)
+	processors := []*Processor{NewProcessor(hans, 2, false), NewProcessor(hant, 2, true)}
+
+	poem := func(id, title string, paragraphs ...string) loader.PoemWithMeta {
+		return loader.PoemWithMeta{
+			PoemData:   loader.PoemData{ID: id, Title: title, Author: "李白", Paragraphs: paragraphs},
+			Dynasty:    "唐",
+			DatasetKey: "tang",
+		}
+	}
+	jingyesi := poem("a", "静夜思", "床前明月光，疑是地上霜。", "举头望明月，低头思故乡。")
+	copied := poem("b", "静夜思", "床前明月光，疑是地上霜。", "举头望明月，低头思故乡。")
+	chunxiao := poem("c", "春晓", "春眠不觉晓，处处闻啼鸟。", "夜来风雨声，花落知多少。")
+	for _, proc := range processors {
+		require.NoError(t, proc.Process(poemSeq(jingyesi, copied, chunxiao)))
+	}
+	count, err := hans.CountPoems()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// The copy skipped by the build isn't taken for a new poem
+	plan, err := PlanUpdate(poemSeq(jingyesi, copied, chunxiao), hans, hant)
+	require.NoError(t, err)
+	assert.Empty(t, plan.Work)
+	assert.Empty(t, plan.Removed)
+	assert.Empty(t, plan.Duplicates)
+
+	// A poem changed into a copy of another keeps its previous version
+	changed := poem("c", "静夜思", "床前明月光，疑是地上霜。", "举头望明月，低头思故乡。")
+	plan, err = PlanUpdate(poemSeq(jingyesi, copied, changed), hans, hant)
+	require.NoError(t, err)
+	require.Equal(t, 1, plan.Changed)
+	for _, proc := range processors {
+		require.NoError(t, proc.Update(plan))
+	}
+	stored, err := hant.GetPoemByID(strconv.FormatInt(plan.Work[0].ID, 10))
+	require.NoError(t, err)
+	assert.Equal(t, "春曉", stored.Title)
+
+	// Without the original, the copy takes its place
+	plan, err = PlanUpdate(poemSeq(copied, chunxiao), hans, hant)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{sourceID(jingyesi.SourceKey())}, plan.Removed)
+	require.Len(t, plan.Work, 1)
+	assert.Equal(t, copied, plan.Work[0].PoemWithMeta)
+	for _, proc := range processors {
+		require.NoError(t, proc.Update(plan))
+	}
+	plan, err = PlanUpdate(poemSeq(copied, chunxiao), hans, hant)
+	require.NoError(t, err)
+	assert.Empty(t, plan.Work)
+	assert.Empty(t, plan.Removed)
+	assert.Empty(t, plan.Duplicates)
+	count, err = hant.CountPoems()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+// poemSeq streams poems as the loader does
+func poemSeq(poems ...loader.PoemWithMeta) iter.Seq2[loader.PoemWithMeta, error] {
+	return func(yield func(loader.PoemWithMeta, error) bool) {
+		for _, poem := range poems {
+			if !yield(poem, nil) {
+				return
+			}
+		}
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -20,6 +22,7 @@ import (
 	"github.com/palemoky/chinese-poetry-api/internal/database"
 	"github.com/palemoky/chinese-poetry-api/internal/loader"
 	"github.com/palemoky/chinese-poetry-api/internal/logger"
+	"github.com/palemoky/chinese-poetry-api/internal/pinyin"
 )
 
 const (
@@ -94,125 +97,137 @@ func (p *Processor) SetBatchSize(size int) {
 	}
 }
 
-// prewarmCache pre-populates the cache with unique dynasties, authors, and poetry types
-// This prevents all workers from hitting the database simultaneously with a cold cache
-// which can cause lock contention and apparent deadlock with SQLite's single-writer model
-func (p *Processor) prewarmCache(poems []loader.PoemWithMeta) error {
-	// Extract unique dynasties first (there are very few, ~20)
-	dynastySet := make(map[string]struct{})
-	for _, poem := range poems {
-		if poem.Dynasty != "" {
-			dynasty := poem.Dynasty
-			// Convert if needed
-			converted, err := p.convertText(dynasty, p.convertToTraditional)
-			if err != nil {
-				continue // Skip on error, will be handled during processing
-			}
-			dynastySet[converted] = struct{}{}
-		}
+// warmCache looks up the dynasty and author of a poem the first time they come
+// in, one poem at a time, so that workers find them in the cache rather than
+// all missing it at once, which contends for SQLite's single writer. warmed
+// holds the pairs of source author and dynasty already looked up.
+func (p *Processor) warmCache(poem loader.PoemWithMeta, warmed map[[2]string]bool) error {
+	key := [2]string{poem.Author, poem.Dynasty}
+	if warmed[key] {
+		return nil
 	}
+	warmed[key] = true
 
-	// Pre-warm dynasty cache (sequential, safe)
-	for dynasty := range dynastySet {
-		if _, err := p.repo.GetOrCreateDynasty(dynasty); err != nil {
+	var dynastyID int64
+	if poem.Dynasty != "" {
+		dynasty, err := p.convertText(poem.Dynasty, p.convertToTraditional)
+		if err != nil {
+			return nil // Will be handled during processing
+		}
+		if dynastyID, err = p.repo.GetOrCreateDynasty(dynasty); err != nil {
 			return fmt.Errorf("failed to pre-warm dynasty cache for %q: %w", dynasty, err)
 		}
 	}
 
-	// Extract unique authors (more, but still manageable ~10k)
-	// We need dynasty IDs first, so dynasties must be cached before this
-	authorSet := make(map[string]string) // author name -> dynasty name
-	for _, poem := range poems {
-		author := classifier.NormalizeText(poem.Author)
-		if author == "" {
-			author = "佚名"
-		}
-		// Convert author name
-		converted, err := p.convertText(author, p.convertToTraditional)
-		if err != nil {
-			continue
-		}
-		if _, exists := authorSet[converted]; !exists {
-			dynasty := poem.Dynasty
-			if dynasty != "" {
-				dynasty, _ = p.convertText(dynasty, p.convertToTraditional)
-			}
-			authorSet[converted] = dynasty
-		}
+	author := classifier.NormalizeText(poem.Author)
+	if author == "" {
+		author = "佚名"
+	}
+	author, err := p.convertText(author, p.convertToTraditional)
+	if err != nil {
+		return nil
 	}
+	// A failure is retried during processing
+	_, _ = p.repo.GetOrCreateAuthor(author, dynastyID)
+	return nil
+}
 
-	// Pre-warm author cache
-	for author, dynasty := range authorSet {
-		var dynastyID int64 = 0
-		if dynasty != "" {
-			var err error
-			dynastyID, err = p.repo.GetOrCreateDynasty(dynasty)
+// Process processes poems as they stream in from the loader, numbering them
+// by their source keys (see idAllocator), then clusters near duplicates
+func (p *Processor) Process(poems iter.Seq2[loader.PoemWithMeta, error]) error {
+	ids := newIDAllocator(nil)
+	works := func(yield func(PoemWork, error) bool) {
+		for poem, err := range poems {
 			if err != nil {
-				continue // Will be handled during processing
+				yield(PoemWork{}, err)
+				return
+			}
+			if !yield(PoemWork{PoemWithMeta: poem, ID: ids.next(poem.SourceKey())}, nil) {
+				return
 			}
-		}
-		if _, err := p.repo.GetOrCreateAuthor(author, dynastyID); err != nil {
-			// Log but don't fail - will be retried during processing
-			continue
 		}
 	}
 
-	logger.Info("Cache pre-warmed",
-		zap.Int("dynasties", len(dynastySet)),
-		zap.Int("authors", len(authorSet)),
-	)
+	if err := p.process(works, p.insertBatch); err != nil {
+		return err
+	}
+	return p.reclusterStored()
+}
 
+// insertBatch inserts a batch of new poems, recording the ones skipped as
+// exact duplicates of a stored poem so that updates know their source records
+func (p *Processor) insertBatch(batch []*database.Poem) error {
+	if err := p.repo.BatchInsertPoems(batch, p.batchSize); err != nil {
+		return err
+	}
+	if _, err := p.repo.RecordDuplicates(batch); err != nil {
+		return fmt.Errorf("failed to record duplicates: %w", err)
+	}
 	return nil
 }
 
-// Process processes all poems with concurrent workers and batch insertion
-func (p *Processor) Process(poems []loader.PoemWithMeta) error {
-	total := len(poems)
+// workSeq streams poems already held in memory
+func workSeq(works []PoemWork) iter.Seq2[PoemWork, error] {
+	return func(yield func(PoemWork, error) bool) {
+		for _, work := range works {
+			if !yield(work, nil) {
+				return
+			}
+		}
+	}
+}
+
+// process processes poems under the IDs they were given as they stream in:
+// workers convert and classify poems while earlier ones are written with
+// write. Poems are written in the order they came in, a batch at a time, so
+// that of two
+// exact duplicates the one kept is the same in every build. Buffers between
+// the stages are bounded, so the poems held at a time don't grow with the
+// number of poems. What does grow is kept small: the IDs given (see
+// idAllocator), some 20 bytes a poem, and the authors looked up by warmCache.
+func (p *Processor) process(works iter.Seq2[PoemWork, error], write func(batch []*database.Poem) error) error {
 	logger.Info("Processing poems",
-		zap.Int("total", total),
 		zap.Int("workers", p.workers),
 		zap.Int("batch_size", p.batchSize),
 	)
 
-	// Pre-warm the cache before starting workers
-	// This prevents all workers from hitting the DB simultaneously with a cold cache
-	if err := p.prewarmCache(poems); err != nil {
-		return fmt.Errorf("failed to pre-warm cache: %w", err)
-	}
-
 	// Create progress container
 	progress := mpb.New(
 		mpb.WithWidth(60),
 		mpb.WithRefreshRate(100*time.Millisecond),
 	)
 
-	// Create progress bar
-	bar := progress.AddBar(int64(total),
+	// Create progress bar, whose total grows as poems are read
+	bar := progress.AddBar(0,
 		mpb.PrependDecorators(
 			decor.Name("Processing: ", decor.WC{W: 12, C: decor.DindentRight}),
 			decor.CountersNoUnit("%d / %d", decor.WCSyncWidth),
 		),
 		mpb.AppendDecorators(
-			decor.Percentage(decor.WC{W: 5}),
-			decor.Name(" | "),
-			decor.AverageETA(decor.ET_STYLE_GO, decor.WC{W: 6}),
-			decor.Name(" | "),
 			decor.AverageSpeed(0, "%.0f poems/s", decor.WC{W: 12}),
 		),
 	)
 
 	// Channels for work distribution
 	// Buffer sizes are adaptive based on system resources
-	// Get optimal configuration
 	workBuffer, resultBuffer, errorBuffer, _, _, _ := getOptimalConfig()
 
-	workCh := make(chan PoemWork, workBuffer)
-	resultCh := make(chan *database.Poem, resultBuffer)
+	// Each poem gets a channel for its result, queued in the order the poems
+	// came in. Inserting from the head of the queue keeps that order while
+	// workers finish in any, and the queue's capacity bounds the poems read
+	// ahead of insertion.
+	type job struct {
+		work   PoemWork
+		result chan<- *database.Poem
+	}
+	workCh := make(chan job, workBuffer)
+	pending := make(chan chan *database.Poem, resultBuffer)
 	errorCh := make(chan error, errorBuffer)
+	stop := make(chan struct{}) // Closed when insertion fails
 	var wg sync.WaitGroup
 
-	// Progress counter
-	var processed atomic.Int64
+	// Progress counters
+	var read atomic.Int64
 	var errorCount atomic.Int64
 
 	// Start workers to process poems (CPU-intensive work)
@@ -220,65 +235,73 @@ func (p *Processor) Process(poems []loader.PoemWithMeta) error {
 		wg.Add(1)
 		go func(workerID int) {
 			defer wg.Done()
-			for work := range workCh {
-				poem, err := p.processPoem(work)
+			for j := range workCh {
+				poem, err := p.processPoem(j.work)
 				if err != nil {
 					errorCount.Add(1)
 					// Non-blocking error recording
 					select {
-					case errorCh <- fmt.Errorf("worker %d: %s - %w", workerID, work.Title, err):
+					case errorCh <- fmt.Errorf("worker %d: %s - %w", workerID, j.work.Title, err):
 					default:
 						// Discard error to avoid blocking
 					}
-					processed.Add(1)
-					bar.Increment()
-					continue
 				}
 
-				// Skip nil poems (e.g., empty content after normalization)
-				if poem == nil {
-					processed.Add(1)
-					bar.Increment()
-					continue
-				}
-
-				// Send processed poem to result channel
-				resultCh <- poem
-				processed.Add(1)
+				// Failed and skipped poems (e.g., empty content after
+				// normalization) send nil, so that the queue moves on
+				j.result <- poem
 				bar.Increment()
 			}
 		}(i)
 	}
 
-	// Start batch inserter goroutine
-	insertDone := make(chan error, 1)
+	// Read poems and send them to workers, warming the cache on the way
+	feedDone := make(chan error, 1)
 	go func() {
-		insertDone <- p.batchInserter(resultCh)
-	}()
+		defer close(pending)
+		defer close(workCh)
 
-	// Send work to workers
-	go func() {
-		for i, poem := range poems {
-			workCh <- PoemWork{
-				PoemWithMeta: poem,
-				ID:           int64(i + 1), // Sequential ID starting from 1
+		warmed := make(map[[2]string]bool)
+		for work, err := range works {
+			if err != nil {
+				feedDone <- err
+				return
+			}
+			if err := p.warmCache(work.PoemWithMeta, warmed); err != nil {
+				feedDone <- err
+				return
 			}
+
+			result := make(chan *database.Poem, 1)
+			select {
+			case pending <- result:
+			case <-stop:
+				feedDone <- nil
+				return
+			}
+			bar.SetTotal(read.Add(1), false)
+			workCh <- job{work: work, result: result}
 		}
-		close(workCh)
+		feedDone <- nil
 	}()
 
+	inserted, insertErr := p.insertInOrder(pending, write)
+	if insertErr != nil {
+		close(stop)
+	}
+
 	// Wait for all workers to finish processing
 	wg.Wait()
+	feedErr := <-feedDone
 
-	// Complete the processing progress bar before starting insertion
-	bar.SetTotal(int64(total), true) // Mark as complete
-	progress.Wait()                  // Wait for processing bar to finish rendering
-
-	close(resultCh) // Signal batch inserter to finish
+	bar.SetTotal(-1, true) // Mark as complete
+	progress.Wait()        // Wait for the progress bar to finish rendering
 
-	// Wait for batch inserter to complete
-	if err := <-insertDone; err != nil {
-		return fmt.Errorf("batch insertion failed: %w", err)
+	if feedErr != nil {
+		return fmt.Errorf("failed to read poems: %w", feedErr)
+	}
+	if insertErr != nil {
+		return fmt.Errorf("batch insertion failed: %w", insertErr)
 	}
 
 	close(errorCh)
@@ -293,14 +316,14 @@ func (p *Processor) Process(poems []loader.PoemWithMeta) error {
 	}
 
 	// Print summary
-	successCount := processed.Load()
+	total := read.Load()
 	failCount := errorCount.Load()
 
 	if failCount > 0 {
 		logger.Warn("Processing completed with errors",
-			zap.Int64("success", successCount-failCount),
+			zap.Int64("success", total-failCount),
 			zap.Int64("failed", failCount),
-			zap.Int("total", total),
+			zap.Int64("total", total),
 		)
 		if len(errors) > 0 {
 			for i := range min(len(errors), SampleErrorCount) {
@@ -310,51 +333,46 @@ func (p *Processor) Process(poems []loader.PoemWithMeta) error {
 		return fmt.Errorf("processing completed with %d errors", failCount)
 	}
 
-	logger.Info("Processing completed successfully", zap.Int("total", total))
+	logger.Info("Processing completed successfully",
+		zap.Int64("total", total),
+		zap.Int("inserted", inserted),
+	)
 	return nil
 }
 
-// batchInserter collects poems and inserts them using large transactions
-// This approach reduces fsync overhead by grouping many inserts into fewer transactions
-func (p *Processor) batchInserter(resultCh <-chan *database.Poem) error {
-	// Collect all poems first (they're already processed)
-	// Filter out nil poems as a safety measure
-	allPoems := make([]*database.Poem, 0, cap(resultCh))
-
-	for poem := range resultCh {
-		if poem != nil {
-			allPoems = append(allPoems, poem)
+// insertInOrder writes the results queued in pending in their order with
+// write, in batches of the configured size. Each batch is its own short
+// transaction, so that workers looking up authors meanwhile get the
+// connection in between. Returns the number of poems sent to the database.
+func (p *Processor) insertInOrder(pending <-chan chan *database.Poem, write func(batch []*database.Poem) error) (int, error) {
+	inserted := 0
+	batch := make([]*database.Poem, 0, p.batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
 		}
-	}
-
-	if len(allPoems) == 0 {
+		if err := write(batch); err != nil {
+			return fmt.Errorf("failed to insert poems %d-%d: %w", inserted, inserted+len(batch), err)
+		}
+		inserted += len(batch)
+		batch = make([]*database.Poem, 0, p.batchSize)
 		return nil
 	}
 
-	logger.Info("Batch inserter starting", zap.Int("poems", len(allPoems)))
-
-	// Create a new progress container for insertion
-	progress := mpb.New(
-		mpb.WithWidth(60),
-		mpb.WithRefreshRate(100*time.Millisecond),
-	)
-
-	// Use large transactions for maximum performance
-	// Transaction size: 20,000 poems per transaction (reduces fsync calls)
-	// Batch size: use current configured batch size for inserts within transaction
-	transactionSize := 20000
-
-	err := p.repo.BatchInsertPoemsWithTransaction(allPoems, transactionSize, p.batchSize, progress)
-
-	// Wait for progress bar to finish rendering
-	progress.Wait()
-
-	if err != nil {
-		return fmt.Errorf("failed to insert poems with transactions: %w", err)
+	for result := range pending {
+		poem := <-result
+		if poem == nil {
+			continue
+		}
+		batch = append(batch, poem)
+		if len(batch) >= p.batchSize {
+			if err := flush(); err != nil {
+				return inserted, err
+			}
+		}
 	}
 
-	logger.Info("Batch insertion complete", zap.Int("inserted", len(allPoems)))
-	return nil
+	return inserted, flush()
 }
 
 // resolveTitleByCategory determines the final title based on poetry type category
@@ -364,7 +382,7 @@ func (p *Processor) batchInserter(resultCh <-chan *database.Poem) error {
 // - Others (诗/曲/诗经/楚辞/蒙学): use title
 func resolveTitleByCategory(poem loader.PoemData, category string) string {
 	switch category {
-	case "词", "宋词": // 宋词/五代词 - use rhythmic (词牌名) as title
+	case classifier.CategoryCi: // 宋词/五代词 - use rhythmic (词牌名) as title
 		if poem.Rhythmic != "" {
 			// Rhythmic is the main title (词牌名)
 			// If there's also a title, merge them as "词牌名·副标题"
@@ -392,10 +410,12 @@ func (p *Processor) processPoem(work PoemWork) (*database.Poem, error) {
 	poem := work.PoemData
 
 	// Normalize all text fields (trim whitespace)
-	// NormalizeAndSplitParagraphs also fixes sentences that were merged into a
-	// single string (e.g. "A。B。" → ["A。","B。"]).
+	// SplitStanzas also fixes sentences that were merged into a single string
+	// (e.g. "A。B。" → ["A。","B。"]), and keeps the breaks between the 上阕 and
+	// 下阕 of a ci or the 章 of 诗经, which flat paragraphs lose.
 	author := classifier.NormalizeText(poem.Author)
-	paragraphs := classifier.NormalizeAndSplitParagraphs(poem.Paragraphs)
+	stanzas := classifier.SplitStanzas(poem.Paragraphs)
+	paragraphs := slices.Concat(stanzas...)
 	rhythmic := classifier.NormalizeText(poem.Rhythmic)
 
 	// Skip poems with empty content after normalization (return nil to skip silently)
@@ -466,6 +486,34 @@ func (p *Processor) processPoem(work PoemWork) (*database.Poem, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get poetry type: %w", err)
 	}
+	typeEvidence, err := json.Marshal(typeInfo.Evidence)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal type evidence: %w", err)
+	}
+
+	// File ci under their 词牌, which otherwise only survives in the title
+	var ciPaiID *int64
+	if typeInfo.Category == classifier.CategoryCi {
+		if name := classifier.CiPaiName(rhythmic); name != "" {
+			id, err := p.repo.GetOrCreateCiPai(name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get/create ci pai: %w", err)
+			}
+			ciPaiID = &id
+		}
+	}
+
+	// Keep the 宫调 and 曲牌 of 元曲, which are only written in the title
+	var gongDiao, quPai string
+	if typeInfo.Category == classifier.CategoryQu {
+		gongDiao, quPai = classifier.ParseQuTitle(poem.Title)
+		if gongDiao, err = p.convertText(gongDiao, p.convertToTraditional); err != nil {
+			return nil, fmt.Errorf("failed to convert gong diao: %w", err)
+		}
+		if quPai, err = p.convertText(quPai, p.convertToTraditional); err != nil {
+			return nil, fmt.Errorf("failed to convert qu pai: %w", err)
+		}
+	}
 
 	// Resolve final title based on category (handles 词/论语/四书五经/etc.)
 	// This intelligently maps different source fields (title/rhythmic/chapter) to the final title
@@ -477,7 +525,7 @@ func (p *Processor) processPoem(work PoemWork) (*database.Poem, error) {
 		return nil, fmt.Errorf("failed to convert final title: %w", err)
 	}
 
-	// Use sequential ID assigned during processing
+	// Use the ID assigned before processing
 	poemID := work.ID
 
 	// Convert paragraphs to JSON for storage
@@ -486,23 +534,97 @@ func (p *Processor) processPoem(work PoemWork) (*database.Poem, error) {
 		return nil, fmt.Errorf("failed to marshal paragraphs: %w", err)
 	}
 
+	// Keep the stanza breaks as the number of paragraphs in each stanza,
+	// which conversion leaves unchanged
+	var stanzaSizes []byte
+	if len(stanzas) > 1 {
+		sizes := make([]int, len(stanzas))
+		for i, stanza := range stanzas {
+			sizes[i] = len(stanza)
+		}
+		if stanzaSizes, err = json.Marshal(sizes); err != nil {
+			return nil, fmt.Errorf("failed to marshal stanza sizes: %w", err)
+		}
+	}
+
 	// Calculate content hash for deduplication.
 	// Hash the plain joined text (not the JSON bytes) so that poems whose
 	// sentences were originally merged ("A。B。") hash identically to the
-	// correctly-split version (["A。","B。"]) after normalization.
+	// correctly-split version (["A。","B。"]) after normalization. Variant
+	// characters are folded first, so copies written with 爲 and with 為 do
+	// too; the stored content keeps the characters as written.
 	joinedText := strings.Join(paragraphs, "")
-	hash := sha256.Sum256([]byte(joinedText))
+	foldedText := classifier.FoldVariants(joinedText)
+	hash := sha256.Sum256([]byte(foldedText))
 	contentHash := hex.EncodeToString(hash[:])
 
+	// Check the tonal pattern against the 近体诗 templates. Only 诗 follows
+	// them: 词 and 曲 with lines of five or seven characters have patterns of
+	// their own.
+	var meterStatus string
+	var meterDetail datatypes.JSON
+	if typeInfo.Category == classifier.CategoryPoetry {
+		if meter := classifier.AnalyzeMeter(paragraphs); meter != nil {
+			detail, err := json.Marshal(meter)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal meter: %w", err)
+			}
+			meterStatus, meterDetail = meter.Status, datatypes.JSON(detail)
+		}
+	}
+
+	// Find the rhymes: 诗 in 平水韵, 词 in 词林正韵. 曲 rhymes in 中原音韵 and
+	// the older texts predate both, so they are left alone.
+	var rhymeDetail datatypes.JSON
+	var rhymeSystem string
+	switch typeInfo.Category {
+	case classifier.CategoryPoetry, classifier.CategoryOther:
+		rhymeSystem = classifier.RhymeSystemPingshui
+	case classifier.CategoryCi:
+		rhymeSystem = classifier.RhymeSystemCilin
+	}
+	if rhyme := classifier.AnalyzeRhyme(paragraphs, rhymeSystem); rhyme != nil {
+		detail, err := json.Marshal(rhyme)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal rhyme: %w", err)
+		}
+		rhymeDetail = datatypes.JSON(detail)
+	}
+
 	// Create poem record
+	// The pinyin index is built from the converted text, so it follows the
+	// readings of the characters this database stores. Paragraphs are joined
+	// with a newline so that an index run never spans two of them. The folded
+	// title and content are indexed for search alongside the text as written.
 	dbPoem := &database.Poem{
-		ID:          poemID,
-		Title:       finalTitle, // Category-aware title (may be from title/rhythmic/chapter)
-		AuthorID:    &authorID,
-		DynastyID:   &dynastyID,
-		TypeID:      &typeID,
-		Content:     datatypes.JSON(contentJSON),
-		ContentHash: contentHash,
+		ID:             poemID,
+		Title:          finalTitle, // Category-aware title (may be from title/rhythmic/chapter)
+		AuthorID:       &authorID,
+		DynastyID:      &dynastyID,
+		TypeID:         &typeID,
+		TypeRule:       typeInfo.Rule,
+		TypeConfidence: typeInfo.Confidence,
+		TypeEvidence:   datatypes.JSON(typeEvidence),
+		CiPaiID:        ciPaiID,
+		GongDiao:       gongDiao,
+		QuPai:          quPai,
+		Content:        datatypes.JSON(contentJSON),
+		StanzaSizes:    datatypes.JSON(stanzaSizes),
+		ContentHash:    contentHash,
+		SourceKey:      work.SourceKey(),
+		SourceHash:     work.SourceHash(),
+		DatasetKey:     work.DatasetKey,
+		DatasetName:    work.DatasetName,
+		SourcePath:     work.SourcePath,
+		SourceID:       work.PoemData.ID,
+		SourceIndex:    work.SourceIndex,
+		TitlePinyin:    pinyin.Index(finalTitle),
+		ContentPinyin:  pinyin.Index(strings.Join(paragraphs, "\n")),
+		TitleFolded:    classifier.FoldVariants(finalTitle),
+		ContentFolded:  foldedText,
+		MeterStatus:    meterStatus,
+		MeterDetail:    meterDetail,
+		RhymeDetail:    rhymeDetail,
 	}
 
 	return dbPoem, nil
@@ -0,0 +1,34 @@
+package search
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/palemoky/chinese-poetry-api/internal/pinyin"
+)
+
+// ParsePinyin parses a pinyin search query such as "jingyesi", "jing ye si",
+// "jing4 ye4 si1" or "jìng yè sī" and returns it normalized for the pinyin
+// index (see pinyin.Normalize). Unlike Parse, whitespace only separates
+// syllables, so the whole query is a single term. It returns a *SyntaxError
+// if the query contains anything but pinyin letters, tone numbers,
+// apostrophes and whitespace, or no letters at all.
+func ParsePinyin(query string) (string, error) {
+	for i, r := range []rune(query) {
+		if !pinyin.IsLetter(r) && !(r >= '1' && r <= '5') && r != '\'' && !unicode.IsSpace(r) {
+			return "", &SyntaxError{Offset: i, Msg: fmt.Sprintf("unexpected %q in pinyin query", r)}
+		}
+	}
+
+	normalized := pinyin.Normalize(query)
+	if normalized == "" {
+		return "", &SyntaxError{Offset: 0, Msg: "pinyin query must contain at least one letter"}
+	}
+	return normalized, nil
+}
+
+// IsPinyinQuery reports whether query can be searched as pinyin
+func IsPinyinQuery(query string) bool {
+	_, err := ParsePinyin(query)
+	return err == nil
+}
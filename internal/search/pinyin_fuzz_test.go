@@ -0,0 +1,51 @@
+package search
+
+import (
+	"errors"
+	"testing"
+	"unicode/utf8"
+)
+
+// FuzzIsPinyinQuery tests pinyin query parsing with random inputs
+func FuzzIsPinyinQuery(f *testing.F) {
+	// Seed corpus with pinyin in every accepted form, and things that aren't
+	f.Add("jingyesi")
+	f.Add("jing ye si")
+	f.Add("jìng yè sī")
+	f.Add("jing4 ye4 si1")
+	f.Add("xi'an lǜ")
+	f.Add("静夜思")
+	f.Add("li-bai")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, query string) {
+		// Should not panic
+		normalized, err := ParsePinyin(query)
+		if IsPinyinQuery(query) != (err == nil) {
+			t.Fatalf("IsPinyinQuery(%q) disagrees with ParsePinyin error %v", query, err)
+		}
+		if err != nil {
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("ParsePinyin(%q) returned a non-syntax error: %v", query, err)
+			}
+			if syntaxErr.Offset < 0 || syntaxErr.Offset > utf8.RuneCountInString(query) {
+				t.Errorf("ParsePinyin(%q) reported offset %d outside the query", query, syntaxErr.Offset)
+			}
+			return
+		}
+
+		// The normalized form is plain lower case letters, and normalizes to itself
+		if normalized == "" {
+			t.Errorf("ParsePinyin(%q) returned an empty query", query)
+		}
+		for _, r := range normalized {
+			if r < 'a' || r > 'z' {
+				t.Errorf("ParsePinyin(%q) = %q, contains %q", query, normalized, r)
+			}
+		}
+		if again, err := ParsePinyin(normalized); err != nil || again != normalized {
+			t.Errorf("ParsePinyin(%q) = %q, %v; want it unchanged", normalized, again, err)
+		}
+	})
+}
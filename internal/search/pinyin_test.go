@@ -0,0 +1,55 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePinyin(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"jingyesi", "jingyesi"},
+		{"jing ye si", "jingyesi"},
+		{"Jing Ye Si", "jingyesi"},
+		{"jing4 ye4 si1", "jingyesi"},
+		{"jìng yè sī", "jingyesi"},
+		{"xi'an", "xian"},
+		{"lǜ shuǐ", "lvshui"},
+		{"　chun　", "chun"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := ParsePinyin(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, IsPinyinQuery(tt.query))
+		})
+	}
+}
+
+func TestParsePinyinErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		offset int
+	}{
+		{"Chinese characters", "jing 夜思", 5},
+		{"operator", "li-bai", 2},
+		{"tone number out of range", "jing6", 4},
+		{"no letters", "4 ' 2", 0},
+		{"empty", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePinyin(tt.query)
+			var syntaxErr *SyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			assert.Equal(t, tt.offset, syntaxErr.Offset)
+			assert.False(t, IsPinyinQuery(tt.query))
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Package search parses the query syntax accepted by poem search.
+//
+// A query is a list of whitespace-separated terms, all of which must match:
+//
+//	明月 故乡          both terms
+//	明月 OR 春风       either term (OR binds tighter than the implicit AND)
+//	明月 -故乡         明月 but not 故乡
+//	"明月 光"          a quoted phrase, matched literally including spaces
+//	title:静夜思       a term restricted to one field: title, content or author
+//
+// Terms are matched as substrings, so a phrase only differs from a bare term
+// in that it may contain whitespace, and is never read as OR, an exclusion or
+// a field prefix.
+package search
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Field restricts a term to part of a poem
+type Field string
+
+const (
+	FieldTitle   Field = "title"
+	FieldContent Field = "content"
+	FieldAuthor  Field = "author"
+)
+
+// Term is a single search term or phrase
+type Term struct {
+	Field Field  // Empty to match any field the search covers
+	Text  string // Matched as a substring
+}
+
+// Clause matches a poem when any of its terms does. An excluded clause holds
+// exactly one term, which must not match.
+type Clause struct {
+	Terms   []Term
+	Exclude bool
+}
+
+// Query is a parsed search query; a poem matches when every clause does
+type Query struct {
+	Clauses []Clause
+}
+
+// Terms returns the terms a matching poem contains, i.e. those of every
+// clause that isn't excluded
+func (q *Query) Terms() []Term {
+	var terms []Term
+	for _, clause := range q.Clauses {
+		if !clause.Exclude {
+			terms = append(terms, clause.Terms...)
+		}
+	}
+	return terms
+}
+
+// SyntaxError reports a malformed query. Offset counts runes from the start
+// of the query.
+type SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid query at offset %d: %s", e.Offset, e.Msg)
+}
+
+// orOperator joins alternatives into one clause
+const orOperator = "OR"
+
+// token is a lexed term, or the OR operator when or is set
+type token struct {
+	offset  int
+	or      bool
+	exclude bool
+	term    Term
+}
+
+// Parse parses a query. It returns a *SyntaxError if the query is malformed
+// or has no term a poem must contain.
+func Parse(query string) (*Query, error) {
+	tokens, err := lex([]rune(query))
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{}
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.or {
+			return nil, &SyntaxError{Offset: tok.offset, Msg: "OR must come between two terms"}
+		}
+
+		clause := Clause{Terms: []Term{tok.term}, Exclude: tok.exclude}
+		for i+1 < len(tokens) && tokens[i+1].or {
+			if i+2 >= len(tokens) || tokens[i+2].or {
+				return nil, &SyntaxError{Offset: tokens[i+1].offset, Msg: "OR must come between two terms"}
+			}
+			next := tokens[i+2]
+			if clause.Exclude || next.exclude {
+				return nil, &SyntaxError{Offset: next.offset, Msg: "excluded terms cannot be combined with OR"}
+			}
+			clause.Terms = append(clause.Terms, next.term)
+			i += 2
+		}
+		q.Clauses = append(q.Clauses, clause)
+	}
+
+	if len(q.Terms()) == 0 {
+		return nil, &SyntaxError{Offset: 0, Msg: "query must contain at least one term that is not excluded"}
+	}
+	return q, nil
+}
+
+// lex splits a query into tokens
+func lex(runes []rune) ([]token, error) {
+	var tokens []token
+	i := 0
+	for {
+		for i < len(runes) && unicode.IsSpace(runes[i]) {
+			i++
+		}
+		if i == len(runes) {
+			return tokens, nil
+		}
+
+		tok := token{offset: i}
+		if runes[i] == '-' {
+			tok.exclude = true
+			i++
+		}
+
+		// A field prefix is an ASCII word followed by a colon
+		if j := fieldPrefixEnd(runes, i); j >= 0 {
+			name := strings.ToLower(string(runes[i:j]))
+			switch Field(name) {
+			case FieldTitle, FieldContent, FieldAuthor:
+				tok.term.Field = Field(name)
+			default:
+				return nil, &SyntaxError{Offset: i, Msg: fmt.Sprintf("unknown field %q, expected title, content or author", name)}
+			}
+			i = j + 1
+		}
+
+		start := i
+		if i < len(runes) && runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, &SyntaxError{Offset: i, Msg: "unterminated quoted phrase"}
+			}
+			tok.term.Text = string(runes[i+1 : end])
+			if strings.TrimSpace(tok.term.Text) == "" {
+				return nil, &SyntaxError{Offset: i, Msg: "empty quoted phrase"}
+			}
+			i = end + 1
+			if i < len(runes) && !unicode.IsSpace(runes[i]) {
+				return nil, &SyntaxError{Offset: i, Msg: "quoted phrase must be followed by a space"}
+			}
+		} else {
+			for i < len(runes) && !unicode.IsSpace(runes[i]) {
+				if runes[i] == '"' {
+					return nil, &SyntaxError{Offset: i, Msg: "unexpected quote inside a term"}
+				}
+				i++
+			}
+			tok.term.Text = string(runes[start:i])
+			if tok.term.Text == "" {
+				return nil, &SyntaxError{Offset: tok.offset, Msg: "missing term after " + string(runes[tok.offset:start])}
+			}
+			if tok.term.Text == orOperator && !tok.exclude && tok.term.Field == "" {
+				tok.or = true
+			}
+		}
+		tokens = append(tokens, tok)
+	}
+}
+
+// fieldPrefixEnd returns the index of the colon ending a field prefix at
+// runes[i:], or -1 if there is none
+func fieldPrefixEnd(runes []rune, i int) int {
+	j := i
+	for j < len(runes) && (runes[j] >= 'a' && runes[j] <= 'z' || runes[j] >= 'A' && runes[j] <= 'Z') {
+		j++
+	}
+	if j > i && j < len(runes) && runes[j] == ':' {
+		return j
+	}
+	return -1
+}
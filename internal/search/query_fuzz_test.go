@@ -0,0 +1,42 @@
+package search
+
+import (
+	"errors"
+	"testing"
+)
+
+// FuzzParse tests the Parse function with random inputs
+func FuzzParse(f *testing.F) {
+	// Seed corpus with valid and malformed queries
+	f.Add("静夜思")
+	f.Add("明月 OR 春风 -故乡")
+	f.Add(`title:"明月 光" author:李白`)
+	f.Add(`"unterminated`)
+	f.Add("OR")
+	f.Add("-")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, query string) {
+		// Should not panic
+		q, err := Parse(query)
+		if err != nil {
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("Parse(%q) returned a non-syntax error: %v", query, err)
+			}
+			return
+		}
+
+		// A parsed query always has a term to match, and no empty terms
+		if len(q.Terms()) == 0 {
+			t.Errorf("Parse(%q) returned a query without terms", query)
+		}
+		for _, clause := range q.Clauses {
+			for _, term := range clause.Terms {
+				if term.Text == "" {
+					t.Errorf("Parse(%q) returned an empty term", query)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []Clause
+	}{
+		{
+			name:  "single term",
+			query: "静夜思",
+			want:  []Clause{{Terms: []Term{{Text: "静夜思"}}}},
+		},
+		{
+			name:  "required terms",
+			query: "明月  故乡",
+			want: []Clause{
+				{Terms: []Term{{Text: "明月"}}},
+				{Terms: []Term{{Text: "故乡"}}},
+			},
+		},
+		{
+			name:  "full-width space separates terms",
+			query: "明月　故乡",
+			want: []Clause{
+				{Terms: []Term{{Text: "明月"}}},
+				{Terms: []Term{{Text: "故乡"}}},
+			},
+		},
+		{
+			name:  "OR group",
+			query: "明月 OR 春风 OR 秋水 故乡",
+			want: []Clause{
+				{Terms: []Term{{Text: "明月"}, {Text: "春风"}, {Text: "秋水"}}},
+				{Terms: []Term{{Text: "故乡"}}},
+			},
+		},
+		{
+			name:  "lowercase or is a term",
+			query: "明月 or",
+			want: []Clause{
+				{Terms: []Term{{Text: "明月"}}},
+				{Terms: []Term{{Text: "or"}}},
+			},
+		},
+		{
+			name:  "excluded term",
+			query: "明月 -故乡",
+			want: []Clause{
+				{Terms: []Term{{Text: "明月"}}},
+				{Terms: []Term{{Text: "故乡"}}, Exclude: true},
+			},
+		},
+		{
+			name:  "quoted phrase",
+			query: `"明月 OR 光" -"故 乡"`,
+			want: []Clause{
+				{Terms: []Term{{Text: "明月 OR 光"}}},
+				{Terms: []Term{{Text: "故 乡"}}, Exclude: true},
+			},
+		},
+		{
+			name:  "field prefixes",
+			query: `title:静夜思 AUTHOR:李白 -content:"故 乡"`,
+			want: []Clause{
+				{Terms: []Term{{Field: FieldTitle, Text: "静夜思"}}},
+				{Terms: []Term{{Field: FieldAuthor, Text: "李白"}}},
+				{Terms: []Term{{Field: FieldContent, Text: "故 乡"}}, Exclude: true},
+			},
+		},
+		{
+			name:  "field prefix in OR group",
+			query: "author:李白 OR author:杜甫",
+			want: []Clause{
+				{Terms: []Term{{Field: FieldAuthor, Text: "李白"}, {Field: FieldAuthor, Text: "杜甫"}}},
+			},
+		},
+		{
+			name:  "hyphen inside a term",
+			query: "明-月",
+			want:  []Clause{{Terms: []Term{{Text: "明-月"}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, q.Clauses)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		offset int
+		msg    string
+	}{
+		{"empty", "  ", 0, "query must contain at least one term that is not excluded"},
+		{"only excluded", "-明月 -故乡", 0, "query must contain at least one term that is not excluded"},
+		{"leading OR", "OR 明月", 0, "OR must come between two terms"},
+		{"trailing OR", "明月 OR", 3, "OR must come between two terms"},
+		{"double OR", "明月 OR OR 故乡", 3, "OR must come between two terms"},
+		{"excluded in OR group", "明月 OR -故乡", 6, "excluded terms cannot be combined with OR"},
+		{"unterminated phrase", `明月 "故乡`, 3, "unterminated quoted phrase"},
+		{"empty phrase", `明月 "  "`, 3, "empty quoted phrase"},
+		{"text after phrase", `"明月"光`, 4, "quoted phrase must be followed by a space"},
+		{"quote inside term", `明"月`, 1, "unexpected quote inside a term"},
+		{"unknown field", "year:唐", 0, `unknown field "year", expected title, content or author`},
+		{"missing term after field", "title: 明月", 0, "missing term after title:"},
+		{"lone minus", "明月 -", 3, "missing term after -"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			var syntaxErr *SyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			assert.Equal(t, tt.offset, syntaxErr.Offset)
+			assert.Equal(t, tt.msg, syntaxErr.Msg)
+		})
+	}
+}
+
+func TestQueryTerms(t *testing.T) {
+	q, err := Parse("明月 OR 春风 -故乡 title:静夜思")
+	require.NoError(t, err)
+	assert.Equal(t, []Term{{Text: "明月"}, {Text: "春风"}, {Field: FieldTitle, Text: "静夜思"}}, q.Terms())
+}